@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Entry is a single audit record for a sensitive operation. It never
+// carries the plaintext or the ciphertext, only who asked and the outcome.
+type Entry struct {
+	Operation string
+	RequestID string
+	IP        string
+	UserAgent string
+	Success   bool
+	Reason    string
+	Duration  time.Duration
+	Timestamp time.Time
+}
+
+// Logger writes audit entries from a background goroutine so request
+// handlers never block on log output. When the buffer is full the entry is
+// dropped and counted rather than slowing down the caller.
+type Logger struct {
+	entries chan Entry
+	dropped atomic.Uint64
+}
+
+func NewLogger(bufferSize int) *Logger {
+	if bufferSize <= 0 {
+		bufferSize = 1024
+	}
+
+	l := &Logger{entries: make(chan Entry, bufferSize)}
+	go l.run()
+	return l
+}
+
+// Record queues an entry for writing without blocking
+func (l *Logger) Record(entry Entry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+func (l *Logger) run() {
+	for entry := range l.entries {
+		event := log.Info()
+		if !entry.Success {
+			event = log.Warn()
+		}
+
+		event.
+			Str("type", "audit").
+			Str("operation", entry.Operation).
+			Str("request_id", entry.RequestID).
+			Str("ip", entry.IP).
+			Str("user_agent", entry.UserAgent).
+			Bool("success", entry.Success).
+			Str("reason", entry.Reason).
+			Dur("duration", entry.Duration).
+			Time("at", entry.Timestamp).
+			Uint64("dropped", l.dropped.Swap(0)).
+			Msg("Audit")
+	}
+}
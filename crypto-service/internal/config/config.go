@@ -2,12 +2,41 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	HybridEncryption HybridEncryptionConfig
-	AppEnv    string
-	AppPort   string
+	HybridEncryption  HybridEncryptionConfig
+	AppEnv            string
+	AppPort           string
+	LogLevel          string
+	LogBodySampleRate int
+	Maintenance       MaintenanceConfig
+	RequestTimeout    time.Duration
+	CORSOrigins       string
+	AdminToken        string
+	ReloadFile        string
+	Decrypt           DecryptConfig
+	// TrustedProxies lists the IPs or CIDRs, such as Kong's, whose X-Forwarded-For header
+	// is taken as the client address
+	TrustedProxies []string
+}
+
+// MaintenanceConfig controls maintenance mode. Enabled forces it on; otherwise it
+// can be toggled at runtime through the admin endpoint.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
+}
+
+// DecryptConfig controls auditing and per-IP rate limiting of the decrypt
+// endpoint. A RateLimit of 0 disables the limiter.
+type DecryptConfig struct {
+	RateLimit       int
+	RateLimitWindow time.Duration
+	AuditBufferSize int
 }
 
 type HybridEncryptionConfig struct {
@@ -16,20 +45,54 @@ type HybridEncryptionConfig struct {
 }
 
 func Load() *Config {
+	logBodySampleRate, _ := strconv.Atoi(getEnv("LOG_BODY_SAMPLE_RATE", "1"))
+	decryptRateLimit, _ := strconv.Atoi(getEnv("DECRYPT_RATE_LIMIT", "0"))
+	decryptRateLimitWindow, _ := time.ParseDuration(getEnv("DECRYPT_RATE_LIMIT_WINDOW", "1m"))
+	auditBufferSize, _ := strconv.Atoi(getEnv("AUDIT_BUFFER_SIZE", "1024"))
+	maintenanceEnabled, _ := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	maintenanceRetryAfter, _ := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	requestTimeout, _ := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
 
 	return &Config{
 		HybridEncryption: HybridEncryptionConfig{
 			PrivateKeyPath: getEnv("HYBRID_ENCRYPTION_PRIVATE_KEY_PATH", "app/keys/private.pem"),
 			PublicKeyPath:  getEnv("HYBRID_ENCRYPTION_PUBLIC_KEY_PATH", "app/keys/public.pem"),
 		},
-		AppEnv:  getEnv("APP_ENV", "development"),
-		AppPort: getEnv("APP_PORT", "3000"),
+		AppEnv:            getEnv("APP_ENV", "development"),
+		AppPort:           getEnv("APP_PORT", "3000"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogBodySampleRate: logBodySampleRate,
+		Maintenance: MaintenanceConfig{
+			Enabled:    maintenanceEnabled,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		RequestTimeout: requestTimeout,
+		CORSOrigins:    getEnv("CORS_ALLOW_ORIGINS", "*"),
+		AdminToken:     getEnv("ADMIN_TOKEN", ""),
+		ReloadFile:     getEnv("CONFIG_RELOAD_FILE", ""),
+		Decrypt: DecryptConfig{
+			RateLimit:       decryptRateLimit,
+			RateLimitWindow: decryptRateLimitWindow,
+			AuditBufferSize: auditBufferSize,
+		},
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16")),
 	}
 }
 
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
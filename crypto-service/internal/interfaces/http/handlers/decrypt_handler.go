@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/audit"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/interfaces/validator"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/schemas"
 	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils"
 	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils/crypto"
 )
@@ -90,17 +94,62 @@ func validateAndProcessRequest(body []byte) (*RequestBody, error) {
 	return &reqBody, nil
 }
 
-func DecryptHandler(privateKeyPath string) fiber.Handler {
+// NewDecryptAuditEntry builds the audit record for a decrypt attempt from
+// the request metadata
+func NewDecryptAuditEntry(c *fiber.Ctx, success bool, reason string) audit.Entry {
+	requestID, _ := c.Locals("requestid").(string)
+	return audit.Entry{
+		Operation: "decrypt",
+		RequestID: requestID,
+		IP:        c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   success,
+		Reason:    reason,
+	}
+}
+
+// SchemaHeader names the header that may carry the schema instead of the query parameter
+const SchemaHeader = "X-Decrypt-Schema"
+
+// DecryptHandler decrypts the request payload and returns it as generic JSON.
+// When a schema is named via the "schema" query parameter or SchemaHeader,
+// the payload is decoded into that registered struct and validated instead.
+func DecryptHandler(privateKeyPath string, auditLog *audit.Logger) fiber.Handler {
+	requestValidator := validator.NewRequestValidator()
+
 	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		record := func(success bool, reason string) {
+			entry := NewDecryptAuditEntry(c, success, reason)
+			entry.Duration = time.Since(start)
+			auditLog.Record(entry)
+		}
+
 		// Only process JSON requests
 		if c.Get("Content-Type") != "application/json" {
+			record(false, "invalid content type")
 			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid content type. Expected application/json")
 		}
 
+		schemaName := c.Query("schema")
+		if schemaName == "" {
+			schemaName = c.Get(SchemaHeader)
+		}
+
+		var target any
+		if schemaName != "" {
+			var ok bool
+			if target, ok = schemas.New(schemaName); !ok {
+				record(false, "unknown schema")
+				return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Unknown schema: %s", schemaName))
+			}
+		}
+
 		// Read and validate request
 		reqBody, err := validateAndProcessRequest(c.Body())
 		if err != nil {
 			log.Printf("Request validation failed: %v", err)
+			record(false, "invalid request")
 			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 		}
 
@@ -108,20 +157,39 @@ func DecryptHandler(privateKeyPath string) fiber.Handler {
 		decryptedData, err := decryptData(privateKeyPath, reqBody.Data)
 		if err != nil {
 			log.Printf("Decryption failed: %v", err)
+			record(false, "decryption failed")
 			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to decrypt data. Invalid or corrupted data.")
 		}
 
 		// Validate JSON
 		if !json.Valid(decryptedData) {
 			log.Print("Decrypted data is not valid JSON")
+			record(false, "decrypted data is not valid JSON")
 			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Decrypted data is not valid JSON")
 		}
 
+		if target != nil {
+			if err := json.Unmarshal(decryptedData, target); err != nil {
+				record(false, "decrypted data does not match schema")
+				return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Decrypted data does not match schema %s", schemaName))
+			}
+			if fieldErrors := requestValidator.Validate(target); len(fieldErrors) > 0 {
+				record(false, "schema validation failed")
+				return utils.ValidationErrorResponse(c, fieldErrors)
+			}
+
+			record(true, "")
+			return utils.SuccessResponse(c, "Decrypted Successfully", target)
+		}
+
 		var jsonData interface{}
 		if err := json.Unmarshal(decryptedData, &jsonData); err != nil {
 			log.Printf("Failed to unmarshal decrypted data: %v", err)
+			record(false, "failed to process decrypted data")
 			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to process decrypted data")
 		}
+
+		record(true, "")
 		return utils.SuccessResponse(c, "Decrypted Successfully", jsonData)
 	}
 }
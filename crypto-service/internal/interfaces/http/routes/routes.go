@@ -1,20 +1,36 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/audit"
 	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/config"
 	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/middleware"
 	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils"
 )
 
-func SetupRoutes(app *fiber.App, cfg *config.Config) {
+func SetupRoutes(app *fiber.App, configHolder *config.Holder) {
+	cfg := configHolder.Get()
 	api := app.Group("/api")
 
 	api.Get("/health", func(c *fiber.Ctx) error {
 		return utils.SuccessResponse(c, "OK", nil)
 	})
 
-	api.Post("/decrypt", handlers.DecryptHandler(cfg.HybridEncryption.PrivateKeyPath))
+	auditLog := audit.NewLogger(cfg.Decrypt.AuditBufferSize)
+	decryptLimiter := middleware.PerIPRateLimiter(
+		func() (int, time.Duration) {
+			decrypt := configHolder.Get().Decrypt
+			return decrypt.RateLimit, decrypt.RateLimitWindow
+		},
+		func(c *fiber.Ctx) {
+			auditLog.Record(handlers.NewDecryptAuditEntry(c, false, "rate limited"))
+		},
+	)
+
+	api.Post("/decrypt", decryptLimiter, handlers.DecryptHandler(cfg.HybridEncryption.PrivateKeyPath, auditLog))
 
 	api.Post("/encrypt", handlers.EncryptHandler(cfg.HybridEncryption.PublicKeyPath))
 
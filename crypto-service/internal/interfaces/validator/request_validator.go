@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils"
+)
+
+// RequestValidator validates request DTOs against their `validate` struct tags and
+// reports failures per field, using the JSON field names clients send.
+type RequestValidator struct {
+	validate *validator.Validate
+}
+
+func NewRequestValidator() *RequestValidator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	return &RequestValidator{
+		validate: validate,
+	}
+}
+
+// Validate returns one FieldError per failed field, or nil when the request is valid
+func (v *RequestValidator) Validate(req interface{}) []utils.FieldError {
+	err := v.validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []utils.FieldError{{Message: err.Error()}}
+	}
+
+	fieldErrors := make([]utils.FieldError, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		fieldErrors = append(fieldErrors, utils.FieldError{
+			Field:   validationErr.Field(),
+			Tag:     validationErr.Tag(),
+			Message: fieldErrorMessage(validationErr),
+		})
+	}
+
+	return fieldErrors
+}
+
+func fieldErrorMessage(validationErr validator.FieldError) string {
+	field := validationErr.Field()
+	param := validationErr.Param()
+
+	switch validationErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "min":
+		if validationErr.Kind() == reflect.Slice {
+			return field + " must contain at least " + param + " items"
+		}
+		return field + " must be at least " + param + " characters long"
+	case "max":
+		if validationErr.Kind() == reflect.Slice {
+			return field + " must contain at most " + param + " items"
+		}
+		return field + " must be at most " + param + " characters long"
+	case "uuid":
+		return field + " must be a valid UUID"
+	case "url":
+		return field + " must be a valid URL"
+	case "oneof":
+		return field + " must be one of: " + param
+	default:
+		return field + " is invalid"
+	}
+}
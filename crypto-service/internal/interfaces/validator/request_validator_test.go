@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils"
+)
+
+func TestValidateListsEveryFailedRule(t *testing.T) {
+	request := struct {
+		Email   string   `json:"email" validate:"required,email"`
+		Name    string   `json:"name" validate:"min=2"`
+		RoleIDs []string `json:"role_ids" validate:"min=1"`
+		Status  string   `json:"status,omitempty" validate:"oneof=active suspended"`
+		Valid   string   `json:"valid" validate:"required"`
+	}{Email: "not-an-email", Name: "A", Status: "deleted", Valid: "set"}
+
+	got := NewRequestValidator().Validate(&request)
+
+	want := []utils.FieldError{
+		{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+		{Field: "name", Tag: "min", Message: "name must be at least 2 characters long"},
+		{Field: "role_ids", Tag: "min", Message: "role_ids must contain at least 1 items"},
+		{Field: "status", Tag: "oneof", Message: "status must be one of: active suspended"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("errors = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestValidateAcceptsValidRequest(t *testing.T) {
+	request := struct {
+		Email string `json:"email" validate:"required,email"`
+	}{Email: "ada@example.com"}
+
+	if got := NewRequestValidator().Validate(&request); got != nil {
+		t.Errorf("errors = %+v, want none", got)
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/tasiuskenways/scalable-ecommerce/crypto-service/internal/utils"
+)
+
+// PerIPRateLimiter allows at most max requests per client IP within window, as
+// returned by settings on each request. A max of 0 disables limiting. When the
+// settings change the limiter is rebuilt, which resets the per-IP counters.
+// onLimit, when set, is called for every rejected request before the 429 is sent.
+func PerIPRateLimiter(settings func() (max int, window time.Duration), onLimit func(c *fiber.Ctx)) fiber.Handler {
+	var (
+		mu            sync.Mutex
+		current       fiber.Handler
+		currentMax    int
+		currentWindow time.Duration
+	)
+
+	return func(c *fiber.Ctx) error {
+		max, window := settings()
+		if max <= 0 {
+			return c.Next()
+		}
+
+		mu.Lock()
+		if current == nil || max != currentMax || window != currentWindow {
+			current = newPerIPLimiter(max, window, onLimit)
+			currentMax, currentWindow = max, window
+		}
+		handler := current
+		mu.Unlock()
+
+		return handler(c)
+	}
+}
+
+func newPerIPLimiter(max int, window time.Duration, onLimit func(c *fiber.Ctx)) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			if onLimit != nil {
+				onLimit(c)
+			}
+			return utils.ErrorResponse(c, fiber.StatusTooManyRequests, "Too many requests, please try again later")
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// limitedApp allows one request per client within a minute. app.Test connects from
+// 0.0.0.0, so trusting that address stands in for trusting Kong.
+func limitedApp(trustedProxies []string) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
+	})
+	app.Use(PerIPRateLimiter(func() (int, time.Duration) { return 1, time.Minute }, nil))
+	app.Post("/decrypt", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	return app
+}
+
+func post(t *testing.T, app *fiber.App, forwardedFor string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/decrypt", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestPerIPRateLimiterKeysOnForwardedClient(t *testing.T) {
+	app := limitedApp([]string{"0.0.0.0"})
+
+	if status := post(t, app, "203.0.113.1"); status != fiber.StatusOK {
+		t.Fatalf("first client: status = %d, want 200", status)
+	}
+	if status := post(t, app, "203.0.113.1"); status != fiber.StatusTooManyRequests {
+		t.Fatalf("first client again: status = %d, want 429", status)
+	}
+	// Another client behind the same proxy has its own budget
+	if status := post(t, app, "203.0.113.2"); status != fiber.StatusOK {
+		t.Fatalf("second client: status = %d, want 200", status)
+	}
+}
+
+func TestPerIPRateLimiterIgnoresHeaderFromUntrustedPeer(t *testing.T) {
+	app := limitedApp([]string{"10.0.0.0/8"})
+
+	if status := post(t, app, "203.0.113.1"); status != fiber.StatusOK {
+		t.Fatalf("first request: status = %d, want 200", status)
+	}
+	// A spoofed header does not earn a fresh budget when the peer is not a trusted proxy
+	if status := post(t, app, "203.0.113.2"); status != fiber.StatusTooManyRequests {
+		t.Fatalf("spoofed client: status = %d, want 429", status)
+	}
+}
@@ -0,0 +1,44 @@
+package schemas
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LoginPayload mirrors the user-service login request
+type LoginPayload struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RegisterPayload mirrors the user-service registration request. The optional
+// profile is passed through untouched and validated by the user service, which
+// also enforces the password policy.
+type RegisterPayload struct {
+	Email    string          `json:"email" validate:"required,email"`
+	Password string          `json:"password" validate:"required"`
+	Name     string          `json:"name" validate:"required,min=2"`
+	Profile  json.RawMessage `json:"profile,omitempty"`
+}
+
+// RefreshTokenPayload mirrors the user-service refresh token request
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// registry maps a schema name to a constructor for a fresh target value
+var registry = map[string]func() any{
+	"login":         func() any { return &LoginPayload{} },
+	"register":      func() any { return &RegisterPayload{} },
+	"refresh_token": func() any { return &RefreshTokenPayload{} },
+}
+
+// New returns a pointer to a zero value of the named schema, or false when
+// no schema is registered under that name
+func New(name string) (any, bool) {
+	newTarget, ok := registry[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return newTarget(), true
+}
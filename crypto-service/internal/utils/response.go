@@ -5,45 +5,70 @@ import (
 )
 
 type Response struct {
-	RequestId string `json:"requestId"`
+	RequestID string `json:"requestId"`
 	Success   bool   `json:"success"`
+	Code      string `json:"code,omitempty"`
 	Message   string `json:"message"`
 	Data      any    `json:"data,omitempty"`
 	Error     string `json:"error,omitempty"`
+	Errors    any    `json:"errors,omitempty"`
+}
+
+// FieldError describes a validation failure on a single request field and the rule it broke
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag,omitempty"`
+	Message string `json:"message"`
 }
 
 func SuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusOK).JSON(Response{
-		RequestId: c.Locals("requestid").(string),
-		Success: true,
-		Message: message,
-		Data:    data,
+		RequestID: getRequestID(c),
+		Success:   true,
+		Message:   message,
+		Data:      data,
 	})
 }
 
 func CreatedResponse(c *fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusCreated).JSON(Response{
-		RequestId: c.Locals("requestid").(string),
-		Success: true,
-		Message: message,
-		Data:    data,
+		RequestID: getRequestID(c),
+		Success:   true,
+		Message:   message,
+		Data:      data,
 	})
 }
 
 func ErrorResponse(c *fiber.Ctx, statusCode int, message string) error {
+	return ErrorResponseWithCode(c, statusCode, "", message)
+}
+
+// ErrorResponseWithCode writes an error envelope carrying a machine-readable code alongside the message
+func ErrorResponseWithCode(c *fiber.Ctx, statusCode int, code string, message string) error {
 	return c.Status(statusCode).JSON(Response{
-		RequestId: c.Locals("requestid").(string),
-		Success: false,
-		Message: message,
-		Error:   message,
+		RequestID: getRequestID(c),
+		Success:   false,
+		Code:      code,
+		Message:   message,
+		Error:     message,
 	})
 }
 
-func ValidationErrorResponse(c *fiber.Ctx, errors []string) error {
-	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-		"requestId": c.Locals("requestid").(string),
-		"success": false,
-		"message": "Validation failed",
-		"errors":  errors,
+func ValidationErrorResponse(c *fiber.Ctx, errors []FieldError) error {
+	return c.Status(fiber.StatusBadRequest).JSON(Response{
+		RequestID: getRequestID(c),
+		Success:   false,
+		Message:   "Validation failed",
+		Errors:    errors,
 	})
-}
\ No newline at end of file
+}
+
+// Helper function to get request ID from context
+func getRequestID(c *fiber.Ctx) string {
+	if rid := c.Locals("requestid"); rid != nil {
+		if ridStr, ok := rid.(string); ok {
+			return ridStr
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// envelope calls respond inside a request that carries a request ID and returns
+// the status and decoded JSON body
+func envelope(t *testing.T, respond fiber.Handler) (int, map[string]any) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Locals("requestid", "req-1")
+		return respond(c)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestEnvelopeShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond fiber.Handler
+		status  int
+		success bool
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "success",
+			respond: func(c *fiber.Ctx) error { return SuccessResponse(c, "ok", fiber.Map{"k": "v"}) },
+			status:  fiber.StatusOK,
+			success: true,
+			present: []string{"data"},
+			absent:  []string{"code", "error", "errors"},
+		},
+		{
+			name:    "created",
+			respond: func(c *fiber.Ctx) error { return CreatedResponse(c, "created", fiber.Map{"k": "v"}) },
+			status:  fiber.StatusCreated,
+			success: true,
+			present: []string{"data"},
+			absent:  []string{"code", "error", "errors"},
+		},
+		{
+			name:    "error without code",
+			respond: func(c *fiber.Ctx) error { return ErrorResponse(c, fiber.StatusBadRequest, "bad") },
+			status:  fiber.StatusBadRequest,
+			present: []string{"error"},
+			absent:  []string{"code", "data", "errors"},
+		},
+		{
+			name: "error with code",
+			respond: func(c *fiber.Ctx) error {
+				return ErrorResponseWithCode(c, fiber.StatusTooManyRequests, "RATE_LIMITED", "slow down")
+			},
+			status:  fiber.StatusTooManyRequests,
+			present: []string{"code", "error"},
+			absent:  []string{"data", "errors"},
+		},
+		{
+			name: "validation",
+			respond: func(c *fiber.Ctx) error {
+				return ValidationErrorResponse(c, []FieldError{{Field: "payload", Tag: "required", Message: "payload is required"}})
+			},
+			status:  fiber.StatusBadRequest,
+			present: []string{"errors"},
+			absent:  []string{"code", "data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := envelope(t, tt.respond)
+
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if body["requestId"] != "req-1" {
+				t.Errorf("requestId = %v, want req-1", body["requestId"])
+			}
+			if success, ok := body["success"].(bool); !ok || success != tt.success {
+				t.Errorf("success = %v, want %v", body["success"], tt.success)
+			}
+			if _, ok := body["message"].(string); !ok {
+				t.Errorf("message missing from %v", body)
+			}
+			for _, key := range tt.present {
+				if _, ok := body[key]; !ok {
+					t.Errorf("%s missing from %v", key, body)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := body[key]; ok {
+					t.Errorf("unexpected %s in %v", key, body)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvelopeWithoutRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error { return SuccessResponse(c, "ok", nil) })
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	if id, ok := body["requestId"]; !ok || id != "" {
+		t.Errorf("requestId = %v, want an empty string", id)
+	}
+}
@@ -29,7 +29,9 @@ function CryptoDecryptHandler:access(conf)
     method = "POST",
     body = body,
     headers = {
-      ["Content-Type"] = "application/json"
+      ["Content-Type"] = "application/json",
+      -- crypto-service rate limits and audits per client, so pass on the client's address
+      ["X-Forwarded-For"] = kong.client.get_forwarded_ip(),
     }
   })
 
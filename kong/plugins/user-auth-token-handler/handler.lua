@@ -3,6 +3,8 @@ local jwt = require "resty.jwt"
 local redis = require "resty.redis"
 local http = require "resty.http"
 local cjson = require "cjson"
+local sha256 = require "resty.sha256"
+local resty_str = require "resty.string"
 
 local JwtBlacklistHandler = {
   PRIORITY = 2000,
@@ -40,9 +42,10 @@ function JwtBlacklistHandler:access(conf)
     end
   end
 
-  -- check blacklist (using jti or raw token)
-  local jti = jwt_obj.payload.jti or token
-  local res, _ = red:get("blacklist:" .. jti)
+  -- check blacklist (keyed by the SHA-256 hex digest of the raw token, matching user-service)
+  local hasher = sha256:new()
+  hasher:update(token)
+  local res, _ = red:get("blacklist:" .. resty_str.to_hex(hasher:final()))
 
   if res and res ~= ngx.null then
     return kong.response.exit(401, { message = "Token revoked" })
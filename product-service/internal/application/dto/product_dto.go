@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+)
+
 type CreateProductRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Description string  `json:"description" validate:"max=1000"`
@@ -17,12 +23,19 @@ type UpdateProductRequest struct {
 	Stock       *int     `json:"stock,omitempty" validate:"omitempty,min=0"`
 	CategoryID  *string  `json:"category_id,omitempty" validate:"omitempty,uuid"`
 	IsActive    *bool    `json:"is_active,omitempty"`
+	// Version, when sent, must match the product's current version or the update is rejected
+	Version *int64 `json:"version,omitempty" validate:"omitempty,min=1"`
 }
 
 type UpdateStockRequest struct {
 	Stock int `json:"stock" validate:"required,min=0"`
 }
 
+type BulkStockUpdateItem struct {
+	ID    string `json:"id" validate:"required,uuid"`
+	Stock int    `json:"stock" validate:"min=0"`
+}
+
 type ProductResponse struct {
 	ID          string            `json:"id"`
 	Name        string            `json:"name"`
@@ -34,6 +47,10 @@ type ProductResponse struct {
 	StoreID     string            `json:"store_id"`
 	SKU         string            `json:"sku"`
 	IsActive    bool              `json:"is_active"`
+	ViewCount   int64             `json:"view_count"`
+	Version     int64             `json:"version"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	UpdatedBy   string            `json:"updated_by,omitempty"`
 	CreatedAt   string            `json:"created_at"`
 	UpdatedAt   string            `json:"updated_at"`
 }
@@ -65,6 +82,13 @@ type ProductListResponse struct {
 	Limit    int               `json:"limit"`
 }
 
+type CursorPaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasNext    bool        `json:"has_next"`
+}
+
 type CategoryListResponse struct {
 	Categories []CategoryResponse `json:"categories"`
 	Total      int64              `json:"total"`
@@ -75,3 +99,65 @@ type CategoryListResponse struct {
 type GetProductsByIdsRequest struct {
 	Ids []string `json:"ids" validate:"required"`
 }
+
+type BulkDeleteProductsRequest struct {
+	Ids []string `json:"ids" validate:"required"`
+}
+
+// AdjustPricesRequest selects a store's products by category and/or ID and changes their
+// price by a percentage or a fixed amount; exactly one of Percent and Amount must be set
+type AdjustPricesRequest struct {
+	StoreID    string   `json:"store_id" validate:"required,uuid"`
+	CategoryID string   `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	ProductIDs []string `json:"product_ids,omitempty" validate:"omitempty,max=100,dive,uuid"`
+	Percent    *float64 `json:"percent,omitempty"`
+	Amount     *float64 `json:"amount,omitempty"`
+}
+
+// CreatePriceScheduleRequest schedules new_price for one product, or for a store's products
+// in one category; without ends_at the change is permanent
+type CreatePriceScheduleRequest struct {
+	StoreID    string     `json:"store_id" validate:"required,uuid"`
+	ProductID  string     `json:"product_id,omitempty" validate:"omitempty,uuid"`
+	CategoryID string     `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	NewPrice   *float64   `json:"new_price" validate:"required,min=0"`
+	StartsAt   time.Time  `json:"starts_at" validate:"required"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+}
+
+// UpdatePriceScheduleRequest changes a pending scheduled price change; omitted fields are kept.
+// clear_ends_at removes the end so the change becomes permanent.
+type UpdatePriceScheduleRequest struct {
+	NewPrice    *float64   `json:"new_price,omitempty" validate:"omitempty,min=0"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	ClearEndsAt bool       `json:"clear_ends_at,omitempty"`
+}
+
+// PriceHistoryResponse is the public view of a price change. The price history route
+// needs no authentication, so it leaves out who made the change.
+type PriceHistoryResponse struct {
+	ID         string    `json:"id"`
+	ProductID  string    `json:"product_id"`
+	OldPrice   float64   `json:"old_price"`
+	NewPrice   float64   `json:"new_price"`
+	Source     string    `json:"source"`
+	ScheduleID string    `json:"schedule_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewPriceHistoryResponse(history []*entities.PriceHistory) []PriceHistoryResponse {
+	responses := make([]PriceHistoryResponse, 0, len(history))
+	for _, h := range history {
+		responses = append(responses, PriceHistoryResponse{
+			ID:         h.ID,
+			ProductID:  h.ProductID,
+			OldPrice:   h.OldPrice,
+			NewPrice:   h.NewPrice,
+			Source:     h.Source,
+			ScheduleID: h.ScheduleID,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
+	repoImpl "github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/repositories"
+)
+
+// fakeProductRepo keeps products in memory. Only the methods the tests reach are
+// implemented; the others panic through the nil embedded interface.
+type fakeProductRepo struct {
+	repositories.ProductRepository
+
+	mu       sync.Mutex
+	products map[string]entities.Product
+}
+
+func newFakeProductRepo(products ...entities.Product) *fakeProductRepo {
+	repo := &fakeProductRepo{products: make(map[string]entities.Product)}
+	for _, product := range products {
+		repo.products[product.ID] = product
+	}
+	return repo
+}
+
+func (r *fakeProductRepo) GetByIDs(ctx context.Context, ids []string) ([]*entities.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var products []*entities.Product
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok {
+			products = append(products, &product)
+		}
+	}
+	return products, nil
+}
+
+func (r *fakeProductRepo) DeleteByIDs(ctx context.Context, ids []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for _, id := range ids {
+		if _, ok := r.products[id]; ok {
+			delete(r.products, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// fakePriceScheduleRepo keeps scheduled price changes in memory. Start and Finish only
+// move the status and record the call; the prices they write are covered by the
+// repository tests.
+type fakePriceScheduleRepo struct {
+	repositories.PriceScheduleRepository
+
+	mu       sync.Mutex
+	changes  map[string]entities.ScheduledPriceChange
+	started  []string
+	finished map[string]string
+	// failStart makes Start fail for that change
+	failStart string
+	// startBeforeUpdate has the scheduler start a change between it being read and saved
+	startBeforeUpdate bool
+}
+
+func newFakePriceScheduleRepo(changes ...entities.ScheduledPriceChange) *fakePriceScheduleRepo {
+	repo := &fakePriceScheduleRepo{changes: make(map[string]entities.ScheduledPriceChange), finished: make(map[string]string)}
+	for _, change := range changes {
+		repo.changes[change.ID] = change
+	}
+	return repo
+}
+
+func (r *fakePriceScheduleRepo) get(id string) entities.ScheduledPriceChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.changes[id]
+}
+
+func (r *fakePriceScheduleRepo) GetByID(ctx context.Context, id string) (*entities.ScheduledPriceChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change, ok := r.changes[id]
+	if !ok {
+		return nil, repoImpl.ErrPriceScheduleNotFound
+	}
+	return &change, nil
+}
+
+func (r *fakePriceScheduleRepo) UpdatePending(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.changes[change.ID]
+	if r.startBeforeUpdate {
+		stored.Status = entities.PriceScheduleStatusActive
+		r.changes[change.ID] = stored
+	}
+	if stored.Status != entities.PriceScheduleStatusPending {
+		return false, nil
+	}
+	stored.NewPrice, stored.StartsAt, stored.EndsAt = change.NewPrice, change.StartsAt, change.EndsAt
+	r.changes[change.ID] = stored
+	return true, nil
+}
+
+func (r *fakePriceScheduleRepo) HasOverlap(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.changes {
+		open := other.Status == entities.PriceScheduleStatusPending || other.Status == entities.PriceScheduleStatusActive
+		sameTarget := other.ProductID == change.ProductID && other.CategoryID == change.CategoryID
+		if other.ID != change.ID && other.StoreID == change.StoreID && open && sameTarget && change.Overlaps(&other) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePriceScheduleRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledPriceChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*entities.ScheduledPriceChange
+	for _, change := range r.changes {
+		starting := change.Status == entities.PriceScheduleStatusPending && !change.StartsAt.After(now)
+		ending := change.Status == entities.PriceScheduleStatusActive && change.EndsAt != nil && !change.EndsAt.After(now)
+		if starting || ending {
+			due = append(due, &change)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakePriceScheduleRepo) Start(ctx context.Context, id string, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id == r.failStart {
+		return 0, errors.New("connection reset")
+	}
+	change := r.changes[id]
+	change.Status = entities.PriceScheduleStatusActive
+	r.changes[id] = change
+	r.started = append(r.started, id)
+	return 1, nil
+}
+
+func (r *fakePriceScheduleRepo) Finish(ctx context.Context, id, status string, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	change := r.changes[id]
+	change.Status = status
+	r.changes[id] = change
+	r.finished[id] = status
+	return 1, nil
+}
+
+// serveMemberships returns a store service client backed by a fake store service that
+// knows the given permissions per store and user. It reports how many membership
+// lookups it answered.
+func serveMemberships(t *testing.T, permissions map[string]map[string]external.RolePermissions) (*external.StoreServiceClient, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	lookups := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/internal/stores/{store}/members/{user}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lookups++
+		mu.Unlock()
+
+		granted, ok := permissions[r.PathValue("store")][r.PathValue("user")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		raw, _ := json.Marshal(external.StoreMembership{StoreID: r.PathValue("store"), UserID: r.PathValue("user"), Permissions: granted})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(external.ServiceResponse{Success: true, Data: raw})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return external.NewStoreServiceClient(server.URL), func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return lookups
+	}
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
+	repoImpl "github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/repositories"
+)
+
+// priceScheduleLock is the JobLock name that keeps the scheduler to one instance
+const priceScheduleLock = "price_schedules"
+
+// defaultPriceScheduleInterval is used when the configured interval is not positive
+const defaultPriceScheduleInterval = time.Minute
+
+// priceScheduleBatch caps how many due changes a single run handles; the rest wait a tick
+const priceScheduleBatch = 100
+
+type priceScheduleService struct {
+	scheduleRepo repositories.PriceScheduleRepository
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+	storeService *external.StoreServiceClient
+	lock         repositories.JobLock
+}
+
+func NewPriceScheduleService(
+	scheduleRepo repositories.PriceScheduleRepository,
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	storeService *external.StoreServiceClient,
+	lock repositories.JobLock,
+) services.PriceScheduleService {
+	return &priceScheduleService{
+		scheduleRepo: scheduleRepo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		storeService: storeService,
+		lock:         lock,
+	}
+}
+
+// validateWindow checks the price and window of a change that has not started yet
+func validateWindow(change *entities.ScheduledPriceChange, now time.Time) error {
+	if change.NewPrice < 0 {
+		return errors.New("new_price must not be negative")
+	}
+	if change.StartsAt.IsZero() {
+		return errors.New("starts_at is required")
+	}
+	if change.EndsAt != nil {
+		if !change.EndsAt.After(change.StartsAt) {
+			return errors.New("ends_at must be after starts_at")
+		}
+		if !change.EndsAt.After(now) {
+			return errors.New("ends_at must be in the future")
+		}
+	}
+	return nil
+}
+
+func (s *priceScheduleService) CreateSchedule(ctx context.Context, userID string, change *entities.ScheduledPriceChange) error {
+	if _, err := uuid.Parse(change.StoreID); err != nil {
+		return errors.New("a valid store_id is required")
+	}
+	if (change.ProductID == "") == (change.CategoryID == "") {
+		return errors.New("exactly one of product_id and category_id is required")
+	}
+	if err := validateWindow(change, time.Now()); err != nil {
+		return err
+	}
+
+	if change.ProductID != "" {
+		product, err := s.productRepo.GetByID(ctx, change.ProductID)
+		if err != nil {
+			return fmt.Errorf("product not found: %w", err)
+		}
+		if product.StoreID != change.StoreID {
+			return errors.New("product does not belong to the store")
+		}
+	} else if _, err := s.categoryRepo.GetByID(ctx, change.CategoryID); err != nil {
+		return fmt.Errorf("category not found: %w", err)
+	}
+
+	if err := authorizeStoreAction(ctx, s.storeService, change.StoreID, userID, canEditProducts); err != nil {
+		return err
+	}
+
+	overlaps, err := s.scheduleRepo.HasOverlap(ctx, change)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return services.ErrPriceScheduleOverlap
+	}
+
+	change.ID = ""
+	change.Status = entities.PriceScheduleStatusPending
+	change.CreatedBy = userID
+	return s.scheduleRepo.Create(ctx, change)
+}
+
+// getAuthorized loads a change the user may manage
+func (s *priceScheduleService) getAuthorized(ctx context.Context, userID, id string) (*entities.ScheduledPriceChange, error) {
+	change, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repoImpl.ErrPriceScheduleNotFound) {
+			return nil, services.ErrPriceScheduleNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeStoreAction(ctx, s.storeService, change.StoreID, userID, canEditProducts); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *priceScheduleService) GetSchedule(ctx context.Context, userID, id string) (*entities.ScheduledPriceChange, error) {
+	return s.getAuthorized(ctx, userID, id)
+}
+
+func (s *priceScheduleService) GetSchedulesByStore(ctx context.Context, userID, storeID string, limit, offset int) ([]*entities.ScheduledPriceChange, error) {
+	if _, err := uuid.Parse(storeID); err != nil {
+		return nil, errors.New("a valid store_id is required")
+	}
+	if err := authorizeStoreAction(ctx, s.storeService, storeID, userID, canEditProducts); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetByStore(ctx, storeID, limit, offset)
+}
+
+func (s *priceScheduleService) UpdateSchedule(ctx context.Context, userID, id string, update *entities.PriceScheduleUpdate) (*entities.ScheduledPriceChange, error) {
+	change, err := s.getAuthorized(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != entities.PriceScheduleStatusPending {
+		return nil, services.ErrPriceScheduleStarted
+	}
+
+	if update.NewPrice != nil {
+		change.NewPrice = *update.NewPrice
+	}
+	if update.StartsAt != nil {
+		change.StartsAt = *update.StartsAt
+	}
+	if update.ClearEndsAt {
+		if update.EndsAt != nil {
+			return nil, errors.New("ends_at and clear_ends_at cannot both be set")
+		}
+		change.EndsAt = nil
+	}
+	if update.EndsAt != nil {
+		change.EndsAt = update.EndsAt
+	}
+	if err := validateWindow(change, time.Now()); err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.scheduleRepo.HasOverlap(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, services.ErrPriceScheduleOverlap
+	}
+
+	// The scheduler may have started the change since it was read
+	updated, err := s.scheduleRepo.UpdatePending(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, services.ErrPriceScheduleStarted
+	}
+	return s.scheduleRepo.GetByID(ctx, id)
+}
+
+func (s *priceScheduleService) CancelSchedule(ctx context.Context, userID, id string) (*entities.ScheduledPriceChange, error) {
+	if _, err := s.getAuthorized(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.scheduleRepo.Finish(ctx, id, entities.PriceScheduleStatusCancelled, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetByID(ctx, id)
+}
+
+func (s *priceScheduleService) RunDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.scheduleRepo.GetDue(ctx, now, priceScheduleBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range due {
+		var affected int64
+		var action string
+		switch {
+		case change.Status == entities.PriceScheduleStatusActive:
+			action = "end"
+			affected, err = s.scheduleRepo.Finish(ctx, change.ID, entities.PriceScheduleStatusCompleted, now)
+		case change.EndsAt != nil && !change.EndsAt.After(now):
+			// The whole window passed while the scheduler was not running; applying the
+			// price now would only flip it back on the next tick
+			action = "skip expired"
+			affected, err = s.scheduleRepo.Finish(ctx, change.ID, entities.PriceScheduleStatusCompleted, now)
+		default:
+			action = "start"
+			affected, err = s.scheduleRepo.Start(ctx, change.ID, now)
+		}
+		if err != nil {
+			// One failing change must not hold up the others
+			log.Printf("Failed to %s scheduled price change %s: %v", action, change.ID, err)
+			continue
+		}
+		log.Printf("Scheduled price change %s: %s, %d products repriced", change.ID, action, affected)
+	}
+	return nil
+}
+
+func (s *priceScheduleService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultPriceScheduleInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runLocked(ctx, interval)
+		}
+	}
+}
+
+// runLocked runs the due changes if no other instance is; the lock expires after interval
+// so a crashed holder cannot stall the schedule
+func (s *priceScheduleService) runLocked(ctx context.Context, interval time.Duration) {
+	acquired, err := s.lock.Acquire(ctx, priceScheduleLock, interval)
+	if err != nil {
+		log.Printf("Failed to acquire price schedule lock: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := s.lock.Release(ctx, priceScheduleLock); err != nil {
+			log.Printf("Failed to release price schedule lock: %v", err)
+		}
+	}()
+
+	if err := s.RunDue(ctx); err != nil {
+		log.Printf("Failed to run scheduled price changes: %v", err)
+	}
+}
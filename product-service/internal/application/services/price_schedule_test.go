@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
+)
+
+// newTestPriceScheduleService returns a scheduler over changes that lets "manager" edit
+// the products of "store"
+func newTestPriceScheduleService(t *testing.T, changes ...entities.ScheduledPriceChange) (*priceScheduleService, *fakePriceScheduleRepo) {
+	t.Helper()
+
+	repo := newFakePriceScheduleRepo(changes...)
+	storeService, _ := serveMemberships(t, map[string]map[string]external.RolePermissions{
+		"store": {"manager": {CanEditProducts: true}},
+	})
+	return &priceScheduleService{scheduleRepo: repo, storeService: storeService}, repo
+}
+
+// pendingChange schedules a price for product from start for the given length; a zero
+// length leaves it open-ended
+func pendingChange(id, product string, start time.Time, length time.Duration) entities.ScheduledPriceChange {
+	change := entities.ScheduledPriceChange{
+		ID:        id,
+		StoreID:   "store",
+		ProductID: product,
+		NewPrice:  8,
+		StartsAt:  start,
+		Status:    entities.PriceScheduleStatusPending,
+	}
+	if length > 0 {
+		endsAt := start.Add(length)
+		change.EndsAt = &endsAt
+	}
+	return change
+}
+
+func TestRunDueStartsEndsAndSkipsExpiredChanges(t *testing.T) {
+	now := time.Now()
+	ending := pendingChange("ending", "b", now.Add(-2*time.Hour), time.Hour+59*time.Minute)
+	ending.Status = entities.PriceScheduleStatusActive
+	service, repo := newTestPriceScheduleService(t,
+		pendingChange("starting", "a", now.Add(-time.Minute), time.Hour),
+		ending,
+		// The whole window passed while the scheduler was down
+		pendingChange("expired", "c", now.Add(-2*time.Hour), time.Hour),
+		pendingChange("future", "d", now.Add(time.Hour), time.Hour),
+	)
+
+	if err := service.RunDue(context.Background()); err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+
+	if !slices.Equal(repo.started, []string{"starting"}) {
+		t.Errorf("started %v, want only the due pending change", repo.started)
+	}
+	wantFinished := map[string]string{
+		"ending":  entities.PriceScheduleStatusCompleted,
+		"expired": entities.PriceScheduleStatusCompleted,
+	}
+	if len(repo.finished) != len(wantFinished) {
+		t.Errorf("finished %v, want %v", repo.finished, wantFinished)
+	}
+	for id, status := range wantFinished {
+		if repo.finished[id] != status {
+			t.Errorf("%s finished as %q, want %q", id, repo.finished[id], status)
+		}
+	}
+	if status := repo.get("future").Status; status != entities.PriceScheduleStatusPending {
+		t.Errorf("future change is %s, want pending", status)
+	}
+}
+
+func TestRunDueContinuesPastFailingChange(t *testing.T) {
+	now := time.Now()
+	service, repo := newTestPriceScheduleService(t,
+		pendingChange("failing", "a", now.Add(-time.Minute), 0),
+		pendingChange("healthy", "b", now.Add(-time.Minute), 0),
+	)
+	repo.failStart = "failing"
+
+	if err := service.RunDue(context.Background()); err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+
+	if !slices.Equal(repo.started, []string{"healthy"}) {
+		t.Errorf("started %v, want the healthy change despite the failure", repo.started)
+	}
+	if status := repo.get("failing").Status; status != entities.PriceScheduleStatusPending {
+		t.Errorf("failing change is %s, want it left pending for the next run", status)
+	}
+}
+
+func TestUpdateScheduleLosesRaceWithStart(t *testing.T) {
+	service, repo := newTestPriceScheduleService(t, pendingChange("sale", "a", time.Now().Add(time.Hour), time.Hour))
+	repo.startBeforeUpdate = true
+	price := 7.0
+
+	_, err := service.UpdateSchedule(context.Background(), "manager", "sale", &entities.PriceScheduleUpdate{NewPrice: &price})
+
+	if !errors.Is(err, services.ErrPriceScheduleStarted) {
+		t.Fatalf("err = %v, want ErrPriceScheduleStarted", err)
+	}
+	if stored := repo.get("sale"); stored.NewPrice != 8 {
+		t.Errorf("new price = %v, want the started change untouched", stored.NewPrice)
+	}
+}
+
+func TestUpdateScheduleCanMakeChangePermanent(t *testing.T) {
+	service, repo := newTestPriceScheduleService(t, pendingChange("sale", "a", time.Now().Add(time.Hour), time.Hour))
+
+	updated, err := service.UpdateSchedule(context.Background(), "manager", "sale", &entities.PriceScheduleUpdate{ClearEndsAt: true})
+
+	if err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+	if updated.EndsAt != nil || repo.get("sale").EndsAt != nil {
+		t.Errorf("ends_at = %v, want the change made permanent", updated.EndsAt)
+	}
+}
+
+func TestUpdateScheduleRejectsEndsAtWithClear(t *testing.T) {
+	service, repo := newTestPriceScheduleService(t, pendingChange("sale", "a", time.Now().Add(time.Hour), time.Hour))
+	endsAt := time.Now().Add(3 * time.Hour)
+
+	_, err := service.UpdateSchedule(context.Background(), "manager", "sale", &entities.PriceScheduleUpdate{EndsAt: &endsAt, ClearEndsAt: true})
+
+	if err == nil {
+		t.Fatal("ends_at and clear_ends_at accepted together")
+	}
+	if repo.get("sale").EndsAt == nil {
+		t.Error("ends_at cleared by a rejected update")
+	}
+}
+
+func TestUpdateScheduleRejectsOverlap(t *testing.T) {
+	now := time.Now()
+	service, repo := newTestPriceScheduleService(t,
+		pendingChange("sale", "a", now.Add(time.Hour), time.Hour),
+		pendingChange("later", "a", now.Add(4*time.Hour), time.Hour),
+		// Other products do not conflict
+		pendingChange("elsewhere", "b", now.Add(time.Hour), 0),
+	)
+
+	_, err := service.UpdateSchedule(context.Background(), "manager", "sale", &entities.PriceScheduleUpdate{ClearEndsAt: true})
+
+	if !errors.Is(err, services.ErrPriceScheduleOverlap) {
+		t.Fatalf("err = %v, want ErrPriceScheduleOverlap", err)
+	}
+	if repo.get("sale").EndsAt == nil {
+		t.Error("overlapping update was saved")
+	}
+}
@@ -2,38 +2,104 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/config"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
+	repoImpl "github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils/pagination"
 )
 
 type productService struct {
 	productRepo  repositories.ProductRepository
 	categoryRepo repositories.CategoryRepository
+	storeService *external.StoreServiceClient
+	search       config.SearchConfig
 }
 
-func NewProductService(productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository) services.ProductService {
+func NewProductService(
+	productRepo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	storeService *external.StoreServiceClient,
+	search config.SearchConfig,
+) services.ProductService {
 	return &productService{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
+		storeService: storeService,
+		search:       search,
 	}
 }
 
-func (s *productService) CreateProduct(ctx context.Context, product *entities.Product) error {
+// authorizeStoreAction checks with the store service that the user's role in the store
+// grants the permission selected by allowed
+func (s *productService) authorizeStoreAction(ctx context.Context, storeID, userID string, allowed func(external.RolePermissions) bool) error {
+	return authorizeStoreAction(ctx, s.storeService, storeID, userID, allowed)
+}
+
+func authorizeStoreAction(ctx context.Context, storeService *external.StoreServiceClient, storeID, userID string, allowed func(external.RolePermissions) bool) error {
+	membership, err := storeService.GetMembership(ctx, storeID, userID)
+	if err != nil {
+		if errors.Is(err, external.ErrNotStoreMember) {
+			return services.ErrForbidden
+		}
+		return fmt.Errorf("failed to verify store membership: %w", err)
+	}
+
+	if !allowed(membership.Permissions) {
+		return services.ErrInsufficientStoreRole
+	}
+	return nil
+}
+
+func canCreateProducts(p external.RolePermissions) bool { return p.CanCreateProducts }
+func canEditProducts(p external.RolePermissions) bool   { return p.CanEditProducts }
+func canDeleteProducts(p external.RolePermissions) bool { return p.CanDeleteProducts }
+
+// validateProductFields checks the bounds of the product's own fields
+func validateProductFields(product *entities.Product) error {
+	if product.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	if product.Stock < 0 {
+		return errors.New("stock must not be negative")
+	}
+	return nil
+}
+
+func (s *productService) CreateProduct(ctx context.Context, userID string, product *entities.Product, dryRun bool) error {
+	if err := validateProductFields(product); err != nil {
+		return err
+	}
+
+	if err := s.authorizeStoreAction(ctx, product.StoreID, userID, canCreateProducts); err != nil {
+		return err
+	}
+
 	// Check if category exists
 	_, err := s.categoryRepo.GetByID(ctx, product.CategoryID)
 	if err != nil {
 		return fmt.Errorf("category not found: %w", err)
 	}
 
-	// Check if SKU already exists
-	existingProduct, err := s.productRepo.GetBySKU(ctx, product.SKU)
+	// SKUs are unique per store; only the store's own products can conflict
+	existingProduct, err := s.productRepo.GetBySKU(ctx, product.StoreID, product.SKU)
 	if err == nil && existingProduct != nil {
-		return fmt.Errorf("product with SKU %s already exists", product.SKU)
+		return &services.SKUConflictError{SKU: product.SKU, ExistingProductID: existingProduct.ID}
 	}
 
+	product.CreatedBy = userID
+	product.UpdatedBy = userID
+	if dryRun {
+		return nil
+	}
 	return s.productRepo.Create(ctx, product)
 }
 
@@ -41,14 +107,37 @@ func (s *productService) GetProduct(ctx context.Context, id string) (*entities.P
 	return s.productRepo.GetByID(ctx, id)
 }
 
-func (s *productService) GetProductBySKU(ctx context.Context, sku string) (*entities.Product, error) {
-	return s.productRepo.GetBySKU(ctx, sku)
+func (s *productService) GetProductBySKU(ctx context.Context, storeID, sku string) (*entities.Product, error) {
+	return s.productRepo.GetBySKU(ctx, storeID, sku)
 }
 
 func (s *productService) GetProducts(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
 	return s.productRepo.GetAll(ctx, limit, offset)
 }
 
+// GetProductsByCursor returns a page of products using keyset pagination on (created_at, id)
+// along with the cursor for the next page, which is empty on the last page.
+func (s *productService) GetProductsByCursor(ctx context.Context, cursor string, limit int) ([]*entities.Product, string, error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	products, err := s.productRepo.GetAllByCursor(ctx, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(products) <= limit {
+		return products, "", nil
+	}
+
+	products = products[:limit]
+	last := products[len(products)-1]
+	return products, pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode(), nil
+}
+
 func (s *productService) GetProductsByCategory(ctx context.Context, categoryID string, limit, offset int) ([]*entities.Product, error) {
 	// Check if category exists
 	_, err := s.categoryRepo.GetByID(ctx, categoryID)
@@ -59,42 +148,169 @@ func (s *productService) GetProductsByCategory(ctx context.Context, categoryID s
 	return s.productRepo.GetByCategory(ctx, categoryID, limit, offset)
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, product *entities.Product) error {
-	// Check if product exists
-	existingProduct, err := s.productRepo.GetByID(ctx, product.ID)
+func (s *productService) GetPopularProducts(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
+	return s.productRepo.GetPopular(ctx, limit, offset)
+}
+
+// maxRelatedProducts caps how many related products a single request can return
+const maxRelatedProducts = 50
+
+func (s *productService) GetRelatedProducts(ctx context.Context, id string, limit int, priceBand float64) ([]*entities.Product, error) {
+	if limit < 1 || limit > maxRelatedProducts {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxRelatedProducts)
+	}
+	if priceBand < 0 || priceBand > 1 {
+		return nil, fmt.Errorf("priceBand must be between 0 and 1")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("product not found: %w", err)
+		return nil, fmt.Errorf("product not found: %w", err)
 	}
 
-	// If category is being updated, check if new category exists
-	if product.CategoryID != existingProduct.CategoryID {
-		_, err := s.categoryRepo.GetByID(ctx, product.CategoryID)
-		if err != nil {
-			return fmt.Errorf("category not found: %w", err)
+	var minPrice, maxPrice float64
+	if priceBand > 0 {
+		minPrice = product.Price * (1 - priceBand)
+		maxPrice = product.Price * (1 + priceBand)
+	}
+
+	return s.productRepo.GetRelated(ctx, product, minPrice, maxPrice, limit)
+}
+
+// GetProductsByCategories returns products in any of the given categories. Unknown
+// category IDs simply match nothing.
+func (s *productService) GetProductsByCategories(ctx context.Context, categoryIDs []string, limit, offset int) ([]*entities.Product, error) {
+	return s.productRepo.GetByCategories(ctx, categoryIDs, limit, offset)
+}
+
+func (s *productService) GetProductsByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.Product, error) {
+	return s.productRepo.GetByStore(ctx, storeID, limit, offset)
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, userID, id string, update *entities.ProductUpdate, dryRun bool) (*entities.Product, error) {
+	// The current row is only read to authorize and validate; the write below touches just
+	// the provided columns, so concurrent changes to other fields survive
+	product, err := s.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, services.ErrProductNotFound
+	}
+	if err := s.authorizeStoreAction(ctx, product.StoreID, userID, canEditProducts); err != nil {
+		return nil, err
+	}
+	var version int64
+	if update.Version != nil {
+		version = *update.Version
+		if version != product.Version {
+			return nil, services.ErrVersionConflict
 		}
 	}
 
-	// If SKU is being updated, check if new SKU already exists
-	if product.SKU != existingProduct.SKU {
-		existingBySKU, err := s.productRepo.GetBySKU(ctx, product.SKU)
-		if err == nil && existingBySKU != nil && existingBySKU.ID != product.ID {
-			return fmt.Errorf("product with SKU %s already exists", product.SKU)
+	if update.CategoryID != nil && *update.CategoryID != product.CategoryID {
+		if _, err := s.categoryRepo.GetByID(ctx, *update.CategoryID); err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
 		}
 	}
 
-	return s.productRepo.Update(ctx, product)
+	update.ApplyTo(product)
+	product.UpdatedBy = userID
+	if err := validateProductFields(product); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return product, nil
+	}
+
+	fields := update.Fields()
+	if len(fields) == 0 {
+		return product, nil
+	}
+	fields["updated_by"] = userID
+	if err := s.productRepo.UpdatePartial(ctx, id, version, fields); err != nil {
+		if errors.Is(err, repoImpl.ErrProductVersionConflict) {
+			return nil, services.ErrVersionConflict
+		}
+		return nil, err
+	}
+	return s.productRepo.GetByID(ctx, id)
 }
 
-func (s *productService) DeleteProduct(ctx context.Context, id string) error {
+func (s *productService) DeleteProduct(ctx context.Context, userID, id string) error {
 	// Check if product exists
-	_, err := s.productRepo.GetByID(ctx, id)
+	product, err := s.productRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("product not found: %w", err)
 	}
 
+	if err := s.authorizeStoreAction(ctx, product.StoreID, userID, canDeleteProducts); err != nil {
+		return err
+	}
+
 	return s.productRepo.Delete(ctx, id)
 }
 
+// maxBulkDelete caps how many products a single bulk delete can cover
+const maxBulkDelete = 100
+
+func (s *productService) DeleteProducts(ctx context.Context, userID string, ids []string) (*entities.BulkDeleteResult, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one product id is required")
+	}
+	if len(ids) > maxBulkDelete {
+		return nil, fmt.Errorf("at most %d products can be deleted at once", maxBulkDelete)
+	}
+
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid product id: %s", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every store involved must allow the deletion before anything is removed
+	found := make(map[string]bool, len(products))
+	authorized := make(map[string]bool)
+	existing := make([]string, 0, len(products))
+	for _, product := range products {
+		found[product.ID] = true
+		existing = append(existing, product.ID)
+		if authorized[product.StoreID] {
+			continue
+		}
+		if err := s.authorizeStoreAction(ctx, product.StoreID, userID, canDeleteProducts); err != nil {
+			return nil, err
+		}
+		authorized[product.StoreID] = true
+	}
+
+	result := &entities.BulkDeleteResult{NotFound: []string{}}
+	for _, id := range unique {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+
+	if len(existing) > 0 {
+		deleted, err := s.productRepo.DeleteByIDs(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		result.Deleted = deleted
+	}
+
+	return result, nil
+}
+
 func (s *productService) UpdateProductStock(ctx context.Context, id string, stock int) error {
 	// Check if product exists
 	_, err := s.productRepo.GetByID(ctx, id)
@@ -105,17 +321,80 @@ func (s *productService) UpdateProductStock(ctx context.Context, id string, stoc
 	return s.productRepo.UpdateStock(ctx, id, stock)
 }
 
-func (s *productService) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*entities.Product, error) {
-	return s.productRepo.Search(ctx, query, limit, offset)
+// maxStockBatch caps how many products a single bulk stock update can cover
+const maxStockBatch = 100
+
+func (s *productService) UpdateStockBatch(ctx context.Context, userID string, updates []entities.StockUpdate) ([]entities.StockUpdateResult, error) {
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("at least one stock update is required")
+	}
+	if len(updates) > maxStockBatch {
+		return nil, fmt.Errorf("at most %d stock updates can be applied at once", maxStockBatch)
+	}
+
+	seen := make(map[string]bool, len(updates))
+	ids := make([]string, 0, len(updates))
+	for _, update := range updates {
+		if update.ProductID == "" {
+			return nil, fmt.Errorf("product id is required for every stock update")
+		}
+		if _, err := uuid.Parse(update.ProductID); err != nil {
+			return nil, fmt.Errorf("invalid product id: %s", update.ProductID)
+		}
+		if update.Stock < 0 {
+			return nil, fmt.Errorf("stock for product %s cannot be negative", update.ProductID)
+		}
+		if !seen[update.ProductID] {
+			seen[update.ProductID] = true
+			ids = append(ids, update.ProductID)
+		}
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every store involved must allow the update before any stock changes
+	authorized := make(map[string]bool)
+	for _, product := range products {
+		if authorized[product.StoreID] {
+			continue
+		}
+		if err := s.authorizeStoreAction(ctx, product.StoreID, userID, canEditProducts); err != nil {
+			return nil, err
+		}
+		authorized[product.StoreID] = true
+	}
+
+	return s.productRepo.UpdateStockBatch(ctx, updates)
+}
+
+// SearchProducts trims the query and checks it against the configured length bounds
+// before searching
+func (s *productService) SearchProducts(ctx context.Context, query, categoryID string, limit, offset int) ([]*entities.Product, error) {
+	query = strings.TrimSpace(query)
+	length := utf8.RuneCountInString(query)
+	if length < s.search.MinQueryLength {
+		return nil, fmt.Errorf("%w: must be at least %d characters", services.ErrInvalidSearchQuery, s.search.MinQueryLength)
+	}
+	if s.search.MaxQueryLength > 0 && length > s.search.MaxQueryLength {
+		return nil, fmt.Errorf("%w: must be at most %d characters", services.ErrInvalidSearchQuery, s.search.MaxQueryLength)
+	}
+
+	return s.productRepo.Search(ctx, query, categoryID, limit, offset)
 }
 
 type categoryService struct {
 	categoryRepo repositories.CategoryRepository
+	// fallbackCategoryID receives the products of a force-deleted category
+	fallbackCategoryID string
 }
 
-func NewCategoryService(categoryRepo repositories.CategoryRepository) services.CategoryService {
+func NewCategoryService(categoryRepo repositories.CategoryRepository, fallbackCategoryID string) services.CategoryService {
 	return &categoryService{
-		categoryRepo: categoryRepo,
+		categoryRepo:       categoryRepo,
+		fallbackCategoryID: fallbackCategoryID,
 	}
 }
 
@@ -159,16 +438,127 @@ func (s *categoryService) UpdateCategory(ctx context.Context, category *entities
 	return s.categoryRepo.Update(ctx, category)
 }
 
-func (s *categoryService) DeleteCategory(ctx context.Context, id string) error {
+func (s *categoryService) DeleteCategory(ctx context.Context, id string, force bool) error {
 	// Check if category exists
 	_, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("category not found: %w", err)
 	}
 
-	return s.categoryRepo.Delete(ctx, id)
+	count, err := s.categoryRepo.CountProducts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return s.categoryRepo.Delete(ctx, id)
+	}
+	if !force {
+		return services.ErrCategoryInUse
+	}
+
+	if s.fallbackCategoryID == "" || s.fallbackCategoryID == id {
+		return services.ErrNoFallbackCategory
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, s.fallbackCategoryID); err != nil {
+		return services.ErrNoFallbackCategory
+	}
+
+	return s.categoryRepo.DeleteAndReassign(ctx, id, s.fallbackCategoryID)
+}
+
+func (s *categoryService) RestoreCategory(ctx context.Context, id string) error {
+	return s.categoryRepo.Restore(ctx, id)
 }
 
 func (s *productService) GetProductsByIds(ctx context.Context, ids []string) ([]*entities.Product, error) {
 	return s.productRepo.GetByIDs(ctx, ids)
 }
+
+// maxAvailabilityBatch caps how many products a single availability check can cover
+const maxAvailabilityBatch = 100
+
+func (s *productService) GetProductAvailability(ctx context.Context, id string) (*entities.ProductAvailability, error) {
+	availability, err := s.GetProductsAvailability(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(availability) == 0 {
+		return nil, fmt.Errorf("product not found")
+	}
+	return &availability[0], nil
+}
+
+// GetProductsAvailability returns the stock and active flag of each known product. A
+// product is available when it is active and has stock left.
+func (s *productService) GetProductsAvailability(ctx context.Context, ids []string) ([]entities.ProductAvailability, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one product id is required")
+	}
+	if len(ids) > maxAvailabilityBatch {
+		return nil, fmt.Errorf("at most %d products can be checked at once", maxAvailabilityBatch)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid product id: %s", id)
+		}
+	}
+
+	availability, err := s.productRepo.GetAvailability(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range availability {
+		availability[i].Available = availability[i].IsActive && availability[i].Stock > 0
+	}
+	return availability, nil
+}
+
+// maxPriceAdjustmentIDs caps how many products a price adjustment can list explicitly
+const maxPriceAdjustmentIDs = 100
+
+func (s *productService) AdjustPrices(ctx context.Context, userID string, adjustment *entities.PriceAdjustment) (*entities.PriceAdjustmentResult, error) {
+	if _, err := uuid.Parse(adjustment.StoreID); err != nil {
+		return nil, fmt.Errorf("a valid store_id is required")
+	}
+	if adjustment.CategoryID == "" && len(adjustment.ProductIDs) == 0 {
+		return nil, fmt.Errorf("category_id or product_ids is required")
+	}
+	if len(adjustment.ProductIDs) > maxPriceAdjustmentIDs {
+		return nil, fmt.Errorf("at most %d products can be adjusted at once", maxPriceAdjustmentIDs)
+	}
+	for _, id := range adjustment.ProductIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid product id: %s", id)
+		}
+	}
+	if (adjustment.Percent == nil) == (adjustment.Amount == nil) {
+		return nil, fmt.Errorf("exactly one of percent and amount is required")
+	}
+	if adjustment.Percent != nil && *adjustment.Percent == 0 || adjustment.Amount != nil && *adjustment.Amount == 0 {
+		return nil, fmt.Errorf("adjustment must not be zero")
+	}
+
+	if adjustment.CategoryID != "" {
+		if _, err := s.categoryRepo.GetByID(ctx, adjustment.CategoryID); err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+	}
+
+	if err := s.authorizeStoreAction(ctx, adjustment.StoreID, userID, canEditProducts); err != nil {
+		return nil, err
+	}
+
+	affected, err := s.productRepo.AdjustPrices(ctx, adjustment, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.PriceAdjustmentResult{Affected: affected}, nil
+}
+
+func (s *productService) GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]*entities.PriceHistory, error) {
+	if _, err := s.productRepo.GetByID(ctx, id); err != nil {
+		return nil, services.ErrProductNotFound
+	}
+	return s.productRepo.GetPriceHistory(ctx, id, limit, offset)
+}
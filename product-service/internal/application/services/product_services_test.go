@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
+)
+
+var canDelete = external.RolePermissions{CanDeleteProducts: true}
+
+func TestDeleteProductsReportsUnknownIDs(t *testing.T) {
+	first, second, unknown := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	products := newFakeProductRepo(
+		entities.Product{ID: first, StoreID: "store"},
+		entities.Product{ID: second, StoreID: "store"},
+	)
+	storeService, lookups := serveMemberships(t, map[string]map[string]external.RolePermissions{
+		"store": {"manager": canDelete},
+	})
+	service := &productService{productRepo: products, storeService: storeService}
+
+	result, err := service.DeleteProducts(context.Background(), "manager", []string{first, unknown, second, first})
+	if err != nil {
+		t.Fatalf("DeleteProducts: %v", err)
+	}
+
+	if result.Deleted != 2 {
+		t.Errorf("deleted = %d, want 2", result.Deleted)
+	}
+	if !slices.Equal(result.NotFound, []string{unknown}) {
+		t.Errorf("not found = %v, want [%s]", result.NotFound, unknown)
+	}
+	if len(products.products) != 0 {
+		t.Errorf("%d products left, want none", len(products.products))
+	}
+	if n := lookups(); n != 1 {
+		t.Errorf("store service asked %d times, want once for the one store", n)
+	}
+}
+
+func TestDeleteProductsDeletesNothingUnlessEveryStoreAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted map[string]external.RolePermissions
+		want    error
+	}{
+		{name: "not a member of one store", granted: map[string]external.RolePermissions{"own": canDelete}, want: services.ErrForbidden},
+		{name: "role without delete in one store", granted: map[string]external.RolePermissions{"own": canDelete, "other": {CanEditProducts: true}}, want: services.ErrInsufficientStoreRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			own, other := uuid.NewString(), uuid.NewString()
+			products := newFakeProductRepo(
+				entities.Product{ID: own, StoreID: "own"},
+				entities.Product{ID: other, StoreID: "other"},
+			)
+			permissions := map[string]map[string]external.RolePermissions{}
+			for storeID, granted := range tt.granted {
+				permissions[storeID] = map[string]external.RolePermissions{"manager": granted}
+			}
+			storeService, _ := serveMemberships(t, permissions)
+			service := &productService{productRepo: products, storeService: storeService}
+
+			_, err := service.DeleteProducts(context.Background(), "manager", []string{own, other})
+
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if len(products.products) != 2 {
+				t.Errorf("%d products left, want both kept", len(products.products))
+			}
+		})
+	}
+}
+
+func TestDeleteProductsValidatesIDs(t *testing.T) {
+	tooMany := make([]string, maxBulkDelete+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	tests := []struct {
+		name string
+		ids  []string
+		want string
+	}{
+		{name: "none", ids: nil, want: "at least one"},
+		{name: "over the limit", ids: tooMany, want: "at most"},
+		{name: "not a UUID", ids: []string{uuid.NewString(), "42"}, want: "invalid product id: 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storeService, lookups := serveMemberships(t, nil)
+			service := &productService{productRepo: newFakeProductRepo(), storeService: storeService}
+
+			_, err := service.DeleteProducts(context.Background(), "manager", tt.ids)
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one mentioning %q", err, tt.want)
+			}
+			if n := lookups(); n != 0 {
+				t.Errorf("store service asked %d times before the ids were validated", n)
+			}
+		})
+	}
+}
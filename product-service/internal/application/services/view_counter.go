@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+)
+
+// recordViewTimeout bounds the background write of a single view to the buffer
+const recordViewTimeout = 2 * time.Second
+
+// defaultViewFlushInterval is used when the configured interval is not positive
+const defaultViewFlushInterval = 30 * time.Second
+
+type viewCounter struct {
+	buffer      repositories.ViewBuffer
+	productRepo repositories.ProductRepository
+}
+
+func NewViewCounter(buffer repositories.ViewBuffer, productRepo repositories.ProductRepository) services.ViewCounter {
+	return &viewCounter{
+		buffer:      buffer,
+		productRepo: productRepo,
+	}
+}
+
+// RecordView buffers the view in the background; a lost view is not worth failing or
+// delaying a read for
+func (v *viewCounter) RecordView(productID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), recordViewTimeout)
+		defer cancel()
+
+		if err := v.buffer.Add(ctx, productID); err != nil {
+			log.Printf("Failed to record view of product %s: %v", productID, err)
+		}
+	}()
+}
+
+func (v *viewCounter) Flush(ctx context.Context) error {
+	counts, err := v.buffer.Drain(ctx)
+	if err != nil || len(counts) == 0 {
+		return err
+	}
+	if err := v.productRepo.IncrementViewCounts(ctx, counts); err != nil {
+		// The increments run in one transaction, so none of them landed; keep them for the next flush
+		if requeueErr := v.buffer.Requeue(ctx, counts); requeueErr != nil {
+			log.Printf("Failed to requeue product view counts: %v", requeueErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (v *viewCounter) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultViewFlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := v.Flush(ctx); err != nil {
+				log.Printf("Failed to flush product view counts: %v", err)
+			}
+		}
+	}
+}
@@ -1,15 +1,54 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	AppEnv   string
-	AppPort  string
+	Database          DatabaseConfig
+	Redis             RedisConfig
+	AppEnv            string
+	AppPort           string
+	LogLevel          string
+	LogBodySampleRate int
+	Maintenance       MaintenanceConfig
+	RequestTimeout    time.Duration
+	CORSOrigins       string
+	AdminToken        string
+	ReloadFile        string
+	StoreServiceURL   string
+	InternalSigning   InternalSigningConfig
+	// FallbackCategoryID receives the products of a force-deleted category
+	FallbackCategoryID string
+	Search             SearchConfig
+	// ViewFlushInterval is how often buffered product views are written to Postgres
+	ViewFlushInterval time.Duration
+	// PriceScheduleInterval is how often scheduled price changes are checked for starts and ends
+	PriceScheduleInterval time.Duration
+}
+
+// SearchConfig bounds the length, in characters, of product search queries
+type SearchConfig struct {
+	MinQueryLength int
+	MaxQueryLength int
+}
+
+// InternalSigningConfig holds the shared secret used to verify HMAC-signed requests
+// from other services and how far their timestamps may drift. Secret is required.
+type InternalSigningConfig struct {
+	Secret  string
+	MaxSkew time.Duration
+}
+
+// MaintenanceConfig controls maintenance mode. Enabled forces it on; otherwise it
+// can be toggled at runtime through the admin endpoint.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
 }
 
 type DatabaseConfig struct {
@@ -19,6 +58,8 @@ type DatabaseConfig struct {
 	DBName   string
 	Port     int
 	SSLMode  string
+	// QueryTimeout bounds each repository operation
+	QueryTimeout time.Duration
 }
 
 type RedisConfig struct {
@@ -32,15 +73,26 @@ func Load() *Config {
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	queryTimeout, _ := time.ParseDuration(getEnv("DB_QUERY_TIMEOUT", "5s"))
+	logBodySampleRate, _ := strconv.Atoi(getEnv("LOG_BODY_SAMPLE_RATE", "1"))
+	maintenanceEnabled, _ := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	maintenanceRetryAfter, _ := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	requestTimeout, _ := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
+	internalSignatureMaxSkew, _ := time.ParseDuration(getEnv("INTERNAL_SIGNATURE_MAX_SKEW", "5m"))
+	searchMinQueryLength, _ := strconv.Atoi(getEnv("SEARCH_MIN_QUERY_LENGTH", "2"))
+	searchMaxQueryLength, _ := strconv.Atoi(getEnv("SEARCH_MAX_QUERY_LENGTH", "100"))
+	viewFlushInterval, _ := time.ParseDuration(getEnv("VIEW_COUNT_FLUSH_INTERVAL", "30s"))
+	priceScheduleInterval, _ := time.ParseDuration(getEnv("PRICE_SCHEDULE_INTERVAL", "1m"))
 
 	return &Config{
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "postgres"),
-			Port:     dbPort,
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:         getEnv("DB_HOST", "localhost"),
+			User:         getEnv("DB_USER", "postgres"),
+			Password:     getEnv("DB_PASSWORD", "postgres"),
+			DBName:       getEnv("DB_NAME", "postgres"),
+			Port:         dbPort,
+			SSLMode:      getEnv("DB_SSLMODE", "disable"),
+			QueryTimeout: queryTimeout,
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -48,9 +100,43 @@ func Load() *Config {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       redisDB,
 		},
-		AppEnv:  getEnv("APP_ENV", "development"),
-		AppPort: getEnv("APP_PORT", "3004"),
+		AppEnv:            getEnv("APP_ENV", "development"),
+		AppPort:           getEnv("APP_PORT", "3004"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogBodySampleRate: logBodySampleRate,
+		Maintenance: MaintenanceConfig{
+			Enabled:    maintenanceEnabled,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		RequestTimeout:  requestTimeout,
+		CORSOrigins:     getEnv("CORS_ALLOW_ORIGINS", "*"),
+		AdminToken:      getEnv("ADMIN_TOKEN", ""),
+		ReloadFile:      getEnv("CONFIG_RELOAD_FILE", ""),
+		StoreServiceURL: getEnv("STORE_SERVICE_URL", "http://store-service:3006"),
+		InternalSigning: InternalSigningConfig{
+			Secret:  getEnv("INTERNAL_SIGNING_SECRET", ""),
+			MaxSkew: internalSignatureMaxSkew,
+		},
+		FallbackCategoryID: getEnv("FALLBACK_CATEGORY_ID", ""),
+		Search: SearchConfig{
+			MinQueryLength: searchMinQueryLength,
+			MaxQueryLength: searchMaxQueryLength,
+		},
+		ViewFlushInterval:     viewFlushInterval,
+		PriceScheduleInterval: priceScheduleInterval,
+	}
+}
+
+// Validate reports settings the service cannot run without, such as the secret used to
+// verify internal requests
+func (c *Config) Validate() error {
+	if c.InternalSigning.Secret == "" {
+		return errors.New("INTERNAL_SIGNING_SECRET is required")
+	}
+	if c.InternalSigning.MaxSkew <= 0 {
+		return fmt.Errorf("INTERNAL_SIGNATURE_MAX_SKEW must be a positive duration such as 5m, got %s", c.InternalSigning.MaxSkew)
 	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateInternalSigning(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		maxSkew time.Duration
+		valid   bool
+	}{
+		{name: "secret and skew", secret: "shared", maxSkew: 5 * time.Minute, valid: true},
+		{name: "missing secret", maxSkew: 5 * time.Minute},
+		{name: "zero skew", secret: "shared"},
+		{name: "negative skew", secret: "shared", maxSkew: -time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{InternalSigning: InternalSigningConfig{Secret: tt.secret, MaxSkew: tt.maxSkew}}
+			err := cfg.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Validate accepted invalid internal signing settings")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoReloadFile is returned by Reload when CONFIG_RELOAD_FILE is not set
+var ErrNoReloadFile = errors.New("CONFIG_RELOAD_FILE is not set")
+
+// Holder gives concurrent access to the configuration and lets the settings that
+// are safe to change be reloaded without a restart
+type Holder struct {
+	mu  sync.RWMutex
+	cfg *Config
+}
+
+func NewHolder(cfg *Config) *Holder {
+	return &Holder{cfg: cfg}
+}
+
+// Get returns the configuration currently in effect. The returned value is never
+// modified, so callers should fetch it again rather than hold on to it.
+func (h *Holder) Get() *Config {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg
+}
+
+// Reload re-reads the env file named by CONFIG_RELOAD_FILE and swaps in a copy of the
+// configuration carrying the reloadable settings found in it. Nothing else is read from
+// the file and the process environment is left alone, so connection settings, URLs and
+// secrets keep their startup values even if the file lists them. Settings missing from
+// the file keep their current value.
+func (h *Holder) Reload() (*Config, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cfg.ReloadFile == "" {
+		return nil, ErrNoReloadFile
+	}
+	// Parse the whole file first so a malformed one changes nothing
+	values, err := readEnvFile(h.cfg.ReloadFile)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *h.cfg
+	if err := next.applyReloadable(values); err != nil {
+		return nil, err
+	}
+	h.cfg = &next
+	return h.cfg, nil
+}
+
+// applyReloadable sets the reloadable settings present in values and reports every value
+// that does not parse. The others are still applied, so callers should work on a copy.
+func (c *Config) applyReloadable(values map[string]string) error {
+	return errors.Join(
+		reloadValue(values, "LOG_LEVEL", &c.LogLevel, parseString),
+		reloadValue(values, "LOG_BODY_SAMPLE_RATE", &c.LogBodySampleRate, strconv.Atoi),
+		reloadValue(values, "CORS_ALLOW_ORIGINS", &c.CORSOrigins, parseString),
+		reloadValue(values, "MAINTENANCE_MODE", &c.Maintenance.Enabled, strconv.ParseBool),
+		reloadValue(values, "MAINTENANCE_RETRY_AFTER", &c.Maintenance.RetryAfter, time.ParseDuration),
+	)
+}
+
+// reloadValue parses values[key] into dst. Empty and missing keys leave dst unchanged,
+// since getEnv ignores empty values too.
+func reloadValue[T any](values map[string]string, key string, dst *T, parse func(string) (T, error)) error {
+	value := values[key]
+	if value == "" {
+		return nil
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*dst = parsed
+	return nil
+}
+
+func parseString(value string) (string, error) {
+	return value, nil
+}
+
+// readEnvFile parses KEY=VALUE lines, skipping blank lines and # comments. Values may be
+// wrapped in matching single or double quotes.
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config reload file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config reload file: %w", err)
+	}
+	return values, nil
+}
+
+// Reloadable reports the settings Reload can change
+func (c *Config) Reloadable() map[string]any {
+	return map[string]any{
+		"log_level":            c.LogLevel,
+		"log_body_sample_rate": c.LogBodySampleRate,
+		"cors_origins":         c.CORSOrigins,
+		"maintenance_forced":   c.Maintenance.Enabled,
+		"maintenance_retry":    c.Maintenance.RetryAfter.String(),
+	}
+}
@@ -0,0 +1,7 @@
+package entities
+
+// BulkDeleteResult reports the outcome of deleting several products at once
+type BulkDeleteResult struct {
+	Deleted  int64    `json:"deleted"`
+	NotFound []string `json:"not_found"`
+}
@@ -0,0 +1,28 @@
+package entities
+
+import "math"
+
+// PriceAdjustment changes the price of a store's products, selected by category and/or ID,
+// by either a percentage or a fixed amount
+type PriceAdjustment struct {
+	StoreID    string
+	CategoryID string
+	ProductIDs []string
+	Percent    *float64
+	Amount     *float64
+}
+
+// Apply returns price after the adjustment, rounded to cents
+func (a *PriceAdjustment) Apply(price float64) float64 {
+	if a.Percent != nil {
+		price += price * *a.Percent / 100
+	} else if a.Amount != nil {
+		price += *a.Amount
+	}
+	return math.Round(price*100) / 100
+}
+
+// PriceAdjustmentResult reports how many products a PriceAdjustment repriced
+type PriceAdjustmentResult struct {
+	Affected int64 `json:"affected"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "testing"
+
+func TestPriceAdjustmentApplyRoundsToCents(t *testing.T) {
+	percent := func(p float64) *PriceAdjustment { return &PriceAdjustment{Percent: &p} }
+	amount := func(a float64) *PriceAdjustment { return &PriceAdjustment{Amount: &a} }
+
+	tests := []struct {
+		name       string
+		adjustment *PriceAdjustment
+		price      float64
+		want       float64
+	}{
+		{"percent rounds up", percent(10), 19.99, 21.99},
+		{"percent rounds down", percent(-15), 9.99, 8.49},
+		{"fractional percent", percent(0.5), 3.33, 3.35},
+		{"amount", amount(-0.1), 0.3, 0.2},
+		{"amount of fractions of a cent", amount(0.004), 5, 5},
+		{"no adjustment", &PriceAdjustment{}, 12.34, 12.34},
+	}
+	for _, tt := range tests {
+		if got := tt.adjustment.Apply(tt.price); got != tt.want {
+			t.Errorf("%s: Apply(%v) = %v, want %v", tt.name, tt.price, got, tt.want)
+		}
+	}
+}
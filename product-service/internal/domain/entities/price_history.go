@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Sources of a recorded price change
+const (
+	PriceSourceAdjustment = "adjustment"
+	// PriceSourceManual marks a price set directly on one product
+	PriceSourceManual = "manual"
+	// PriceSourceScheduleStart and PriceSourceScheduleEnd mark a scheduled change being
+	// applied and reverted
+	PriceSourceScheduleStart = "schedule_start"
+	PriceSourceScheduleEnd   = "schedule_end"
+)
+
+// PriceHistory records one change to a product's price
+type PriceHistory struct {
+	ID         string    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ProductID  string    `json:"product_id" gorm:"type:uuid;not null;index:idx_price_history_product"`
+	OldPrice   float64   `json:"old_price" gorm:"not null"`
+	NewPrice   float64   `json:"new_price" gorm:"not null"`
+	Source     string    `json:"source" gorm:"type:varchar(32);not null"`
+	ChangedBy  string    `json:"changed_by,omitempty" gorm:"type:varchar(36)"`
+	ScheduleID string    `json:"schedule_id,omitempty" gorm:"type:varchar(36);index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index:idx_price_history_product"`
+}
+
+func (PriceHistory) TableName() string {
+	return "price_history"
+}
+
+// BeforeCreate hook to set default values
+func (h *PriceHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == "" {
+		h.ID = uuid.NewString()
+	}
+	return nil
+}
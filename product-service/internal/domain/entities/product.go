@@ -18,6 +18,10 @@ type Product struct {
 	StoreID     string         `json:"store_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_store_sku"`
 	SKU         string         `json:"sku" gorm:"not null;uniqueIndex:idx_store_sku"`
 	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	ViewCount   int64          `json:"view_count" gorm:"not null;default:0;index"`
+	Version     int64          `json:"version" gorm:"not null;default:1"`
+	CreatedBy   string         `json:"created_by,omitempty" gorm:"type:varchar(36)"`
+	UpdatedBy   string         `json:"updated_by,omitempty" gorm:"type:varchar(36)"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,10 @@
+package entities
+
+// ProductAvailability is the stock-related projection of a product, used for quick
+// availability checks without loading the full product
+type ProductAvailability struct {
+	ProductID string `json:"id" gorm:"column:id"`
+	Stock     int    `json:"stock" gorm:"column:stock"`
+	IsActive  bool   `json:"is_active" gorm:"column:is_active"`
+	Available bool   `json:"available" gorm:"-"`
+}
@@ -0,0 +1,59 @@
+package entities
+
+// ProductUpdate carries the fields of a partial product update; nil fields are left untouched
+type ProductUpdate struct {
+	Name        *string
+	Description *string
+	Price       *float64
+	Stock       *int
+	CategoryID  *string
+	IsActive    *bool
+	// Version, when set, must match the product's current version for the update to apply
+	Version *int64
+}
+
+// Fields returns the column values to write, keyed by column name
+func (u *ProductUpdate) Fields() map[string]any {
+	fields := make(map[string]any)
+	if u.Name != nil {
+		fields["name"] = *u.Name
+	}
+	if u.Description != nil {
+		fields["description"] = *u.Description
+	}
+	if u.Price != nil {
+		fields["price"] = *u.Price
+	}
+	if u.Stock != nil {
+		fields["stock"] = *u.Stock
+	}
+	if u.CategoryID != nil {
+		fields["category_id"] = *u.CategoryID
+	}
+	if u.IsActive != nil {
+		fields["is_active"] = *u.IsActive
+	}
+	return fields
+}
+
+// ApplyTo copies the provided fields onto product in memory
+func (u *ProductUpdate) ApplyTo(product *Product) {
+	if u.Name != nil {
+		product.Name = *u.Name
+	}
+	if u.Description != nil {
+		product.Description = *u.Description
+	}
+	if u.Price != nil {
+		product.Price = *u.Price
+	}
+	if u.Stock != nil {
+		product.Stock = *u.Stock
+	}
+	if u.CategoryID != nil {
+		product.CategoryID = *u.CategoryID
+	}
+	if u.IsActive != nil {
+		product.IsActive = *u.IsActive
+	}
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a ScheduledPriceChange
+const (
+	// PriceScheduleStatusPending changes wait for StartsAt
+	PriceScheduleStatusPending = "pending"
+	// PriceScheduleStatusActive changes have been applied and wait for EndsAt
+	PriceScheduleStatusActive = "active"
+	// PriceScheduleStatusCompleted changes have ended, or were applied with no end
+	PriceScheduleStatusCompleted = "completed"
+	PriceScheduleStatusCancelled = "cancelled"
+)
+
+// ScheduledPriceChange sets the price of one product, or of a store's products in one
+// category, to NewPrice from StartsAt until EndsAt, when the previous prices are restored.
+// A nil EndsAt makes the change permanent.
+type ScheduledPriceChange struct {
+	ID         string     `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	StoreID    string     `json:"store_id" gorm:"type:uuid;not null;index"`
+	ProductID  string     `json:"product_id,omitempty" gorm:"type:varchar(36);index"`
+	CategoryID string     `json:"category_id,omitempty" gorm:"type:varchar(36)"`
+	NewPrice   float64    `json:"new_price" gorm:"not null"`
+	StartsAt   time.Time  `json:"starts_at" gorm:"not null"`
+	EndsAt     *time.Time `json:"ends_at,omitempty"`
+	Status     string     `json:"status" gorm:"type:varchar(16);not null;index"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty" gorm:"type:varchar(36)"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (ScheduledPriceChange) TableName() string {
+	return "scheduled_price_changes"
+}
+
+// BeforeCreate hook to set default values
+func (s *ScheduledPriceChange) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	if s.Status == "" {
+		s.Status = PriceScheduleStatusPending
+	}
+	return nil
+}
+
+// Overlaps reports whether the windows of c and other share any moment. An open-ended
+// window runs forever.
+func (c *ScheduledPriceChange) Overlaps(other *ScheduledPriceChange) bool {
+	if c.EndsAt != nil && !other.StartsAt.Before(*c.EndsAt) {
+		return false
+	}
+	return other.EndsAt == nil || other.EndsAt.After(c.StartsAt)
+}
+
+// PriceScheduleUpdate carries the fields of a pending change to replace; nil fields are kept.
+// ClearEndsAt removes the end instead, making the change permanent.
+type PriceScheduleUpdate struct {
+	NewPrice    *float64
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	ClearEndsAt bool
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledPriceChangeOverlaps(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	window := func(start int, end *int) *ScheduledPriceChange {
+		change := &ScheduledPriceChange{StartsAt: base.Add(time.Duration(start) * time.Hour)}
+		if end != nil {
+			endsAt := base.Add(time.Duration(*end) * time.Hour)
+			change.EndsAt = &endsAt
+		}
+		return change
+	}
+	at := func(hour int) *int { return &hour }
+
+	tests := []struct {
+		name   string
+		change *ScheduledPriceChange
+		other  *ScheduledPriceChange
+		want   bool
+	}{
+		{"ends as the other starts", window(10, at(20)), window(0, at(10)), false},
+		{"starts as the other ends", window(10, at(20)), window(20, at(30)), false},
+		{"other starts inside", window(10, at(20)), window(15, at(30)), true},
+		{"other ends inside", window(10, at(20)), window(0, at(11)), true},
+		{"other contains it", window(10, at(20)), window(0, at(30)), true},
+		{"open-ended other started before", window(10, at(20)), window(0, nil), true},
+		{"open-ended other starts after", window(10, at(20)), window(25, nil), false},
+		{"open-ended change and a later window", window(10, nil), window(100, at(110)), true},
+		{"both open-ended", window(10, nil), window(0, nil), true},
+	}
+	for _, tt := range tests {
+		if got := tt.change.Overlaps(tt.other); got != tt.want {
+			t.Errorf("%s: Overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.other.Overlaps(tt.change); got != tt.want {
+			t.Errorf("%s: reversed Overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package entities
+
+// StockUpdate sets the stock level of a single product as part of a bulk update
+type StockUpdate struct {
+	ProductID string
+	Stock     int
+}
+
+// StockUpdateResult reports the outcome of a single StockUpdate
+type StockUpdateResult struct {
+	ProductID string `json:"id"`
+	Stock     int    `json:"stock"`
+	Updated   bool   `json:"updated"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// JobLock keeps a background job running on one service instance at a time
+type JobLock interface {
+	// Acquire takes the named lock for ttl and reports whether it was free
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	// Release frees the lock if this instance still holds it
+	Release(ctx context.Context, name string) error
+}
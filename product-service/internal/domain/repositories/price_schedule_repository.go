@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+)
+
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, change *entities.ScheduledPriceChange) error
+	GetByID(ctx context.Context, id string) (*entities.ScheduledPriceChange, error)
+	GetByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.ScheduledPriceChange, error)
+	// UpdatePending saves the price and window of change if it is still pending, and reports
+	// whether it was
+	UpdatePending(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error)
+	// HasOverlap reports whether another pending or active change for the same product or
+	// category overlaps change's window
+	HasOverlap(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error)
+	// GetDue returns pending changes whose start has passed and active changes whose end has
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledPriceChange, error)
+	// Start applies a pending change's price and records it in the price history; Finish ends
+	// a change with the given status, restoring the prices an active change replaced. Both
+	// return how many products changed price and do nothing if the change has moved on.
+	Start(ctx context.Context, id string, now time.Time) (int64, error)
+	Finish(ctx context.Context, id, status string, now time.Time) (int64, error)
+}
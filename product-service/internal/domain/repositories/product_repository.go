@@ -4,19 +4,41 @@ import (
 	"context"
 
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils/pagination"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *entities.Product) error
 	GetByID(ctx context.Context, id string) (*entities.Product, error)
-	GetBySKU(ctx context.Context, sku string) (*entities.Product, error)
+	GetBySKU(ctx context.Context, storeID, sku string) (*entities.Product, error)
 	GetAll(ctx context.Context, limit, offset int) ([]*entities.Product, error)
+	GetAllByCursor(ctx context.Context, cursor *pagination.Cursor, limit int) ([]*entities.Product, error)
 	GetByCategory(ctx context.Context, categoryID string, limit, offset int) ([]*entities.Product, error)
+	GetByCategories(ctx context.Context, categoryIDs []string, limit, offset int) ([]*entities.Product, error)
+	GetPopular(ctx context.Context, limit, offset int) ([]*entities.Product, error)
+	GetRelated(ctx context.Context, product *entities.Product, minPrice, maxPrice float64, limit int) ([]*entities.Product, error)
+	GetByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.Product, error)
 	GetByIDs(ctx context.Context, ids []string) ([]*entities.Product, error)
+	GetAvailability(ctx context.Context, ids []string) ([]entities.ProductAvailability, error)
+	// Update and UpdatePartial bump the product's version and refuse, with a version conflict
+	// error, to write over a newer one; UpdatePartial skips the check when version is 0
 	Update(ctx context.Context, product *entities.Product) error
+	// UpdatePartial writes only the given columns, keyed by column name, and records a
+	// changed price in the price history
+	UpdatePartial(ctx context.Context, id string, version int64, fields map[string]any) error
 	Delete(ctx context.Context, id string) error
+	// DeleteByIDs soft-deletes the given products in one statement and returns how many it removed
+	DeleteByIDs(ctx context.Context, ids []string) (int64, error)
 	UpdateStock(ctx context.Context, id string, stock int) error
-	Search(ctx context.Context, query string, limit, offset int) ([]*entities.Product, error)
+	// IncrementViewCounts adds each count to its product's view_count in one transaction
+	IncrementViewCounts(ctx context.Context, counts map[string]int64) error
+	UpdateStockBatch(ctx context.Context, updates []entities.StockUpdate) ([]entities.StockUpdateResult, error)
+	// AdjustPrices applies the adjustment and records price history atomically, returning how
+	// many products changed price
+	AdjustPrices(ctx context.Context, adjustment *entities.PriceAdjustment, changedBy string) (int64, error)
+	GetPriceHistory(ctx context.Context, productID string, limit, offset int) ([]*entities.PriceHistory, error)
+	// Search matches name and description across the catalog, or within categoryID when it is set
+	Search(ctx context.Context, query, categoryID string, limit, offset int) ([]*entities.Product, error)
 }
 
 type CategoryRepository interface {
@@ -25,5 +47,9 @@ type CategoryRepository interface {
 	GetByName(ctx context.Context, name string) (*entities.Category, error)
 	GetAll(ctx context.Context, limit, offset int) ([]*entities.Category, error)
 	Update(ctx context.Context, category *entities.Category) error
+	// Delete soft-deletes; DeleteAndReassign also moves the category's products to fallbackID
 	Delete(ctx context.Context, id string) error
+	DeleteAndReassign(ctx context.Context, id, fallbackID string) error
+	Restore(ctx context.Context, id string) error
+	CountProducts(ctx context.Context, id string) (int64, error)
 }
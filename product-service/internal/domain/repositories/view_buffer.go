@@ -0,0 +1,12 @@
+package repositories
+
+import "context"
+
+// ViewBuffer accumulates product views outside Postgres so reads never wait on a row update
+type ViewBuffer interface {
+	Add(ctx context.Context, productID string) error
+	// Drain atomically takes every buffered count, leaving the buffer empty
+	Drain(ctx context.Context) (map[string]int64, error)
+	// Requeue puts drained counts back, for when writing them out failed
+	Requeue(ctx context.Context, counts map[string]int64) error
+}
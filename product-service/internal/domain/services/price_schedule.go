@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+)
+
+// PriceScheduleService manages scheduled price changes and applies and reverts them as
+// their windows open and close
+type PriceScheduleService interface {
+	CreateSchedule(ctx context.Context, userID string, change *entities.ScheduledPriceChange) error
+	GetSchedule(ctx context.Context, userID, id string) (*entities.ScheduledPriceChange, error)
+	GetSchedulesByStore(ctx context.Context, userID, storeID string, limit, offset int) ([]*entities.ScheduledPriceChange, error)
+	// UpdateSchedule changes the price and window of a change that has not started
+	UpdateSchedule(ctx context.Context, userID, id string, update *entities.PriceScheduleUpdate) (*entities.ScheduledPriceChange, error)
+	// CancelSchedule stops a change, restoring the previous prices if it is active
+	CancelSchedule(ctx context.Context, userID, id string) (*entities.ScheduledPriceChange, error)
+	// RunDue starts and ends every change whose time has come
+	RunDue(ctx context.Context) error
+	// Run calls RunDue every interval, on one instance at a time, until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// ErrPriceScheduleNotFound is returned for an unknown scheduled price change
+var ErrPriceScheduleNotFound = errors.New("scheduled price change not found")
+
+// ErrPriceScheduleOverlap is returned when a change's window overlaps another pending or
+// active change for the same product or category
+var ErrPriceScheduleOverlap = errors.New("another scheduled price change for this product or category overlaps this window")
+
+// ErrPriceScheduleStarted is returned when editing a change that is no longer pending
+var ErrPriceScheduleStarted = errors.New("only pending scheduled price changes can be edited")
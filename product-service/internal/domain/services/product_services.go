@@ -2,28 +2,83 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
 )
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, product *entities.Product) error
+	// CreateProduct and UpdateProduct run every check but skip the write when dryRun is set
+	CreateProduct(ctx context.Context, userID string, product *entities.Product, dryRun bool) error
 	GetProduct(ctx context.Context, id string) (*entities.Product, error)
-	GetProductBySKU(ctx context.Context, sku string) (*entities.Product, error)
+	GetProductBySKU(ctx context.Context, storeID, sku string) (*entities.Product, error)
 	GetProducts(ctx context.Context, limit, offset int) ([]*entities.Product, error)
+	GetProductsByCursor(ctx context.Context, cursor string, limit int) ([]*entities.Product, string, error)
 	GetProductsByIds(ctx context.Context, ids []string) ([]*entities.Product, error)
+	GetProductAvailability(ctx context.Context, id string) (*entities.ProductAvailability, error)
+	GetProductsAvailability(ctx context.Context, ids []string) ([]entities.ProductAvailability, error)
 	GetProductsByCategory(ctx context.Context, categoryID string, limit, offset int) ([]*entities.Product, error)
-	UpdateProduct(ctx context.Context, product *entities.Product) error
-	DeleteProduct(ctx context.Context, id string) error
+	GetProductsByCategories(ctx context.Context, categoryIDs []string, limit, offset int) ([]*entities.Product, error)
+	// GetRelatedProducts returns active products in the same category; a positive priceBand
+	// also keeps them within that fraction of the product's price
+	GetPopularProducts(ctx context.Context, limit, offset int) ([]*entities.Product, error)
+	GetRelatedProducts(ctx context.Context, id string, limit int, priceBand float64) ([]*entities.Product, error)
+	GetProductsByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.Product, error)
+	// UpdateProduct writes only the fields set in update and returns the resulting product
+	UpdateProduct(ctx context.Context, userID, id string, update *entities.ProductUpdate, dryRun bool) (*entities.Product, error)
+	DeleteProduct(ctx context.Context, userID, id string) error
+	// DeleteProducts deletes every known product in ids, provided the user may delete products
+	// in all of their stores, and reports the IDs it did not find
+	DeleteProducts(ctx context.Context, userID string, ids []string) (*entities.BulkDeleteResult, error)
 	UpdateProductStock(ctx context.Context, id string, stock int) error
-	SearchProducts(ctx context.Context, query string, limit, offset int) ([]*entities.Product, error)
+	UpdateStockBatch(ctx context.Context, userID string, updates []entities.StockUpdate) ([]entities.StockUpdateResult, error)
+	SearchProducts(ctx context.Context, query, categoryID string, limit, offset int) ([]*entities.Product, error)
+	// AdjustPrices reprices a store's products by category or ID; it fails without changing
+	// anything if a price would become negative
+	AdjustPrices(ctx context.Context, userID string, adjustment *entities.PriceAdjustment) (*entities.PriceAdjustmentResult, error)
+	GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]*entities.PriceHistory, error)
 }
 
+// ErrForbidden is returned when the caller is not allowed to modify the product's store
+var ErrForbidden = errors.New("you do not have access to this store")
+
+// SKUConflictError is returned when another product already uses the SKU, compared case-insensitively
+type SKUConflictError struct {
+	SKU               string
+	ExistingProductID string
+}
+
+func (e *SKUConflictError) Error() string {
+	return fmt.Sprintf("product with SKU %s already exists", e.SKU)
+}
+
+// ErrInvalidSearchQuery is returned when a search query is shorter or longer than allowed
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// ErrProductNotFound is returned when the product being updated does not exist
+var ErrProductNotFound = errors.New("product not found")
+
+// ErrVersionConflict is returned when an update names a version the product has moved past
+var ErrVersionConflict = errors.New("product was modified by another request; reload it and retry")
+
+// ErrInsufficientStoreRole is returned when the caller's store role lacks the needed product permission
+var ErrInsufficientStoreRole = errors.New("your store role does not allow this action")
+
 type CategoryService interface {
 	CreateCategory(ctx context.Context, category *entities.Category) error
 	GetCategory(ctx context.Context, id string) (*entities.Category, error)
 	GetCategoryByName(ctx context.Context, name string) (*entities.Category, error)
 	GetCategories(ctx context.Context, limit, offset int) ([]*entities.Category, error)
 	UpdateCategory(ctx context.Context, category *entities.Category) error
-	DeleteCategory(ctx context.Context, id string) error
+	// DeleteCategory refuses while products reference the category unless force is set, in
+	// which case they are moved to the configured fallback category
+	DeleteCategory(ctx context.Context, id string, force bool) error
+	RestoreCategory(ctx context.Context, id string) error
 }
+
+// ErrCategoryInUse is returned when deleting a category that products still reference without force
+var ErrCategoryInUse = errors.New("category still has products; delete with force to move them to the fallback category")
+
+// ErrNoFallbackCategory is returned by a forced delete when no usable fallback category is configured
+var ErrNoFallbackCategory = errors.New("no fallback category is configured to receive the products")
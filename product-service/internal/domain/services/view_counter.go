@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// ViewCounter records product views without blocking the request that caused them and
+// periodically writes the buffered totals to the products
+type ViewCounter interface {
+	RecordView(productID string)
+	Flush(ctx context.Context) error
+	// Run flushes every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
@@ -16,15 +16,29 @@ func Migrate(db *gorm.DB, resetDb bool) error {
 
 	if resetDb {
 		// Drop existing tables if they exist
-		err = db.Migrator().DropTable(&entities.Product{}, &entities.Category{})
+		err = db.Migrator().DropTable(&entities.ScheduledPriceChange{}, &entities.PriceHistory{}, &entities.Product{}, &entities.Category{})
 		if err != nil {
 			return fmt.Errorf("failed to drop tables: %w", err)
 		}
 	}
 
 	// Create tables with new schema
-	return db.AutoMigrate(
+	err = db.AutoMigrate(
 		&entities.Category{},
 		&entities.Product{},
+		&entities.PriceHistory{},
+		&entities.ScheduledPriceChange{},
 	)
+	if err != nil {
+		return fmt.Errorf("failed to migrate tables: %w", err)
+	}
+
+	// SKUs are unique per store regardless of case; existing rows that differ only in case
+	// must be renamed manually before this index can be created
+	err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_products_store_sku_lower ON products (store_id, LOWER(sku)) WHERE deleted_at IS NULL").Error
+	if err != nil {
+		return fmt.Errorf("failed to create case-insensitive SKU index: %w", err)
+	}
+
+	return nil
 }
@@ -52,6 +52,8 @@ func ConnectWithoutMigration(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	SetQueryTimeout(cfg.Database.QueryTimeout)
+
 	log.Println("Database connected successfully")
 	return db, nil
 }
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// queryTimeout bounds every repository operation; it is set from config on connect
+var queryTimeout = 5 * time.Second
+
+// SetQueryTimeout sets the per-operation database timeout. A non-positive value disables it.
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout = timeout
+}
+
+// WithTimeout derives a context bounded by the per-operation database timeout.
+// Cancellation of the parent context still propagates to the returned context.
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
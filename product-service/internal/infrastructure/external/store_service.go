@@ -0,0 +1,86 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrNotStoreMember = errors.New("user is not a member of this store")
+
+type StoreServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type StoreMembership struct {
+	StoreID     string          `json:"store_id"`
+	UserID      string          `json:"user_id"`
+	Role        string          `json:"role"`
+	Permissions RolePermissions `json:"permissions"`
+}
+
+type RolePermissions struct {
+	CanCreateProducts bool `json:"can_create_products"`
+	CanEditProducts   bool `json:"can_edit_products"`
+	CanDeleteProducts bool `json:"can_delete_products"`
+}
+
+type ServiceResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func NewStoreServiceClient(baseURL string) *StoreServiceClient {
+	return &StoreServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetMembership returns the user's role in the store, or ErrNotStoreMember when they have none
+func (c *StoreServiceClient) GetMembership(ctx context.Context, storeID, userID string) (*StoreMembership, error) {
+	url := fmt.Sprintf("%s/api/internal/stores/%s/members/%s", c.baseURL, storeID, userID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Internal-Service", "product-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch store membership: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNotStoreMember
+		}
+		return nil, fmt.Errorf("store service returned status %d", resp.StatusCode)
+	}
+
+	var serviceResp ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&serviceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !serviceResp.Success {
+		return nil, fmt.Errorf("store service error: %s", serviceResp.Error)
+	}
+
+	var membership StoreMembership
+	if err := json.Unmarshal(serviceResp.Data, &membership); err != nil {
+		return nil, fmt.Errorf("failed to decode membership data: %w", err)
+	}
+
+	return &membership, nil
+}
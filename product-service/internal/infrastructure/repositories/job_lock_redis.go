@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+)
+
+// releaseJobLock deletes the lock only while it still holds this instance's token, so an
+// instance whose lock expired mid-run cannot free a lock another instance has since taken
+var releaseJobLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type redisJobLock struct {
+	client *redis.Client
+	// token identifies this instance as the holder
+	token string
+}
+
+func NewRedisJobLock(client *redis.Client) repositories.JobLock {
+	return &redisJobLock{client: client, token: uuid.NewString()}
+}
+
+func jobLockKey(name string) string {
+	return "job_lock:" + name
+}
+
+func (l *redisJobLock) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, jobLockKey(name), l.token, ttl).Result()
+}
+
+func (l *redisJobLock) Release(ctx context.Context, name string) error {
+	return releaseJobLock.Run(ctx, l.client, []string{jobLockKey(name)}, l.token).Err()
+}
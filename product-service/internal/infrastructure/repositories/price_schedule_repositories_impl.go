@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPriceScheduleNotFound = errors.New("scheduled price change not found")
+
+type priceScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceScheduleRepository(db *gorm.DB) repositories.PriceScheduleRepository {
+	return &priceScheduleRepository{db: db}
+}
+
+func (r *priceScheduleRepository) Create(ctx context.Context, change *entities.ScheduledPriceChange) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *priceScheduleRepository) GetByID(ctx context.Context, id string) (*entities.ScheduledPriceChange, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var change entities.ScheduledPriceChange
+	if err := r.db.WithContext(ctx).First(&change, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPriceScheduleNotFound
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *priceScheduleRepository) GetByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.ScheduledPriceChange, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var changes []*entities.ScheduledPriceChange
+	query := r.db.WithContext(ctx).Where("store_id = ?", storeID).Order("starts_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&changes).Error
+	return changes, err
+}
+
+func (r *priceScheduleRepository) UpdatePending(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&entities.ScheduledPriceChange{}).
+		Where("id = ? AND status = ?", change.ID, entities.PriceScheduleStatusPending).
+		Updates(map[string]any{
+			"new_price": change.NewPrice,
+			"starts_at": change.StartsAt,
+			"ends_at":   change.EndsAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// HasOverlap loads the other pending and active changes for the same product or category,
+// of which there are few, and compares their windows with change's
+func (r *priceScheduleRepository) HasOverlap(ctx context.Context, change *entities.ScheduledPriceChange) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	query := r.db.WithContext(ctx).
+		Where("store_id = ? AND status IN ?", change.StoreID, []string{
+			entities.PriceScheduleStatusPending,
+			entities.PriceScheduleStatusActive,
+		})
+	if change.ID != "" {
+		query = query.Where("id <> ?", change.ID)
+	}
+	if change.ProductID != "" {
+		query = query.Where("product_id = ?", change.ProductID)
+	} else {
+		query = query.Where("category_id = ?", change.CategoryID)
+	}
+
+	var others []*entities.ScheduledPriceChange
+	if err := query.Find(&others).Error; err != nil {
+		return false, err
+	}
+	for _, other := range others {
+		if change.Overlaps(other) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *priceScheduleRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*entities.ScheduledPriceChange, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var changes []*entities.ScheduledPriceChange
+	err := r.db.WithContext(ctx).
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)",
+			entities.PriceScheduleStatusPending, now, entities.PriceScheduleStatusActive, now).
+		Order("starts_at").
+		Limit(limit).
+		Find(&changes).Error
+	return changes, err
+}
+
+// lockChange reads the change for the rest of tx, so concurrent Start and Finish calls
+// for it run one after the other
+func lockChange(tx *gorm.DB, id string) (*entities.ScheduledPriceChange, error) {
+	var change entities.ScheduledPriceChange
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&change, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPriceScheduleNotFound
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *priceScheduleRepository) Start(ctx context.Context, id string, now time.Time) (int64, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		change, err := lockChange(tx, id)
+		if err != nil {
+			return err
+		}
+		if change.Status != entities.PriceScheduleStatusPending {
+			return nil
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("store_id = ?", change.StoreID)
+		if change.ProductID != "" {
+			query = query.Where("id = ?", change.ProductID)
+		} else {
+			query = query.Where("category_id = ?", change.CategoryID)
+		}
+		var products []entities.Product
+		if err := query.Find(&products).Error; err != nil {
+			return err
+		}
+
+		history := startedPrices(change, products)
+		for _, entry := range history {
+			if err := setProductPrice(tx, entry.ProductID, entry.NewPrice, change.CreatedBy); err != nil {
+				return err
+			}
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		affected = int64(len(history))
+
+		// A change without an end has nothing left to do once applied
+		status := entities.PriceScheduleStatusActive
+		if change.EndsAt == nil {
+			status = entities.PriceScheduleStatusCompleted
+		}
+		return tx.Model(change).Updates(map[string]any{
+			"status":     status,
+			"applied_at": now,
+		}).Error
+	})
+	return affected, err
+}
+
+// Finish restores each price the change set, unless the product has been repriced since,
+// in which case the newer price wins
+func (r *priceScheduleRepository) Finish(ctx context.Context, id, status string, now time.Time) (int64, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		change, err := lockChange(tx, id)
+		if err != nil {
+			return err
+		}
+		if change.Status != entities.PriceScheduleStatusPending && change.Status != entities.PriceScheduleStatusActive {
+			return nil
+		}
+
+		if change.Status == entities.PriceScheduleStatusActive {
+			var applied []entities.PriceHistory
+			err := tx.Where("schedule_id = ? AND source = ?", change.ID, entities.PriceSourceScheduleStart).
+				Find(&applied).Error
+			if err != nil {
+				return err
+			}
+
+			ids := make([]string, 0, len(applied))
+			for _, entry := range applied {
+				ids = append(ids, entry.ProductID)
+			}
+			// Locked in ID order so concurrent changes to the same products cannot deadlock
+			var products []entities.Product
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&products).Error
+			if err != nil {
+				return err
+			}
+
+			history := restoredPrices(change, applied, products)
+			for _, entry := range history {
+				if err := setProductPrice(tx, entry.ProductID, entry.NewPrice, change.CreatedBy); err != nil {
+					return err
+				}
+			}
+			if len(history) > 0 {
+				if err := tx.Create(&history).Error; err != nil {
+					return err
+				}
+			}
+			affected = int64(len(history))
+		}
+
+		return tx.Model(change).Updates(map[string]any{
+			"status":   status,
+			"ended_at": now,
+		}).Error
+	})
+	return affected, err
+}
+
+// startedPrices returns the price history entries starting change makes to products,
+// leaving out products already at the new price
+func startedPrices(change *entities.ScheduledPriceChange, products []entities.Product) []entities.PriceHistory {
+	history := make([]entities.PriceHistory, 0, len(products))
+	for _, product := range products {
+		if product.Price == change.NewPrice {
+			continue
+		}
+		history = append(history, entities.PriceHistory{
+			ProductID:  product.ID,
+			OldPrice:   product.Price,
+			NewPrice:   change.NewPrice,
+			Source:     entities.PriceSourceScheduleStart,
+			ChangedBy:  change.CreatedBy,
+			ScheduleID: change.ID,
+		})
+	}
+	return history
+}
+
+// restoredPrices returns the price history entries ending change makes, putting back the
+// price each applied entry replaced. Products deleted since, or repriced since so they no
+// longer carry the change's price, are left alone.
+func restoredPrices(change *entities.ScheduledPriceChange, applied []entities.PriceHistory, products []entities.Product) []entities.PriceHistory {
+	current := make(map[string]float64, len(products))
+	for _, product := range products {
+		current[product.ID] = product.Price
+	}
+
+	history := make([]entities.PriceHistory, 0, len(applied))
+	for _, entry := range applied {
+		price, ok := current[entry.ProductID]
+		if !ok || price != entry.NewPrice {
+			continue
+		}
+		history = append(history, entities.PriceHistory{
+			ProductID:  entry.ProductID,
+			OldPrice:   price,
+			NewPrice:   entry.OldPrice,
+			Source:     entities.PriceSourceScheduleEnd,
+			ChangedBy:  change.CreatedBy,
+			ScheduleID: change.ID,
+		})
+	}
+	return history
+}
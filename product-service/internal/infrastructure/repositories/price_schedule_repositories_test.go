@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+)
+
+func TestStartedPricesSkipsProductsAlreadyAtThePrice(t *testing.T) {
+	change := &entities.ScheduledPriceChange{ID: "sale", NewPrice: 8, CreatedBy: "staff"}
+	products := []entities.Product{
+		{ID: "full", Price: 10},
+		{ID: "already", Price: 8},
+	}
+
+	history := startedPrices(change, products)
+
+	want := entities.PriceHistory{ProductID: "full", OldPrice: 10, NewPrice: 8, Source: entities.PriceSourceScheduleStart, ChangedBy: "staff", ScheduleID: "sale"}
+	if len(history) != 1 || history[0] != want {
+		t.Errorf("history = %+v, want only %+v", history, want)
+	}
+}
+
+func TestRestoredPricesLeavesRepricedAndDeletedProducts(t *testing.T) {
+	change := &entities.ScheduledPriceChange{ID: "sale", NewPrice: 8, CreatedBy: "staff"}
+	applied := []entities.PriceHistory{
+		{ProductID: "untouched", OldPrice: 10, NewPrice: 8},
+		{ProductID: "repriced", OldPrice: 12, NewPrice: 8},
+		{ProductID: "deleted", OldPrice: 9, NewPrice: 8},
+	}
+	products := []entities.Product{
+		{ID: "untouched", Price: 8},
+		// Edited by hand while the sale ran; the newer price wins
+		{ID: "repriced", Price: 11},
+	}
+
+	history := restoredPrices(change, applied, products)
+
+	want := entities.PriceHistory{ProductID: "untouched", OldPrice: 8, NewPrice: 10, Source: entities.PriceSourceScheduleEnd, ChangedBy: "staff", ScheduleID: "sale"}
+	if len(history) != 1 || history[0] != want {
+		t.Errorf("history = %+v, want only %+v", history, want)
+	}
+}
@@ -3,14 +3,20 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/db"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils/pagination"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var ErrProductNotFound = errors.New("product not found")
+var ErrProductVersionConflict = errors.New("product was modified by another request")
+var ErrNegativePrice = errors.New("price adjustment would make a price negative")
 var ErrCategoryNotFound = errors.New("category not found")
 
 type productRepository struct {
@@ -22,10 +28,16 @@ func NewProductRepository(db *gorm.DB) repositories.ProductRepository {
 }
 
 func (r *productRepository) Create(ctx context.Context, product *entities.Product) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Create(product).Error
 }
 
 func (r *productRepository) GetByID(ctx context.Context, id string) (*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var product entities.Product
 	err := r.db.WithContext(ctx).Preload("Category").Where("id = ?", id).First(&product).Error
 	if err != nil {
@@ -37,9 +49,19 @@ func (r *productRepository) GetByID(ctx context.Context, id string) (*entities.P
 	return &product, nil
 }
 
-func (r *productRepository) GetBySKU(ctx context.Context, sku string) (*entities.Product, error) {
+// GetBySKU matches SKUs case-insensitively within the store, or across all stores when
+// storeID is empty
+func (r *productRepository) GetBySKU(ctx context.Context, storeID, sku string) (*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	query := r.db.WithContext(ctx).Preload("Category").Where("LOWER(sku) = LOWER(?)", sku)
+	if storeID != "" {
+		query = query.Where("store_id = ?", storeID)
+	}
+
 	var product entities.Product
-	err := r.db.WithContext(ctx).Preload("Category").Where("sku = ?", sku).First(&product).Error
+	err := query.First(&product).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProductNotFound
@@ -50,6 +72,9 @@ func (r *productRepository) GetBySKU(ctx context.Context, sku string) (*entities
 }
 
 func (r *productRepository) GetAll(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var products []*entities.Product
 	query := r.db.WithContext(ctx).Preload("Category").Where("is_active = ?", true)
 
@@ -64,7 +89,27 @@ func (r *productRepository) GetAll(ctx context.Context, limit, offset int) ([]*e
 	return products, err
 }
 
+// GetAllByCursor returns up to limit active products ordered by created_at DESC, id DESC,
+// starting after the given cursor. A nil cursor starts from the newest product.
+func (r *productRepository) GetAllByCursor(ctx context.Context, cursor *pagination.Cursor, limit int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var products []*entities.Product
+	query := r.db.WithContext(ctx).Preload("Category").Where("is_active = ?", true)
+
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	err := query.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&products).Error
+	return products, err
+}
+
 func (r *productRepository) GetByCategory(ctx context.Context, categoryID string, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var products []*entities.Product
 	query := r.db.WithContext(ctx).Preload("Category").Where("category_id = ? AND is_active = ?", categoryID, true)
 
@@ -79,19 +124,336 @@ func (r *productRepository) GetByCategory(ctx context.Context, categoryID string
 	return products, err
 }
 
+// GetPopular returns active products ordered by view count, most viewed first
+func (r *productRepository) GetPopular(ctx context.Context, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var products []*entities.Product
+	query := r.db.WithContext(ctx).Preload("Category").Where("is_active = ?", true).
+		Order("view_count DESC").Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&products).Error
+	return products, err
+}
+
+// GetRelated returns the most recent active products sharing the product's category, excluding
+// the product itself. A positive maxPrice also restricts them to the [minPrice, maxPrice] band.
+func (r *productRepository) GetRelated(ctx context.Context, product *entities.Product, minPrice, maxPrice float64, limit int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var products []*entities.Product
+	query := r.db.WithContext(ctx).Preload("Category").
+		Where("category_id = ? AND is_active = ? AND id <> ?", product.CategoryID, true, product.ID)
+
+	if maxPrice > 0 {
+		query = query.Where("price BETWEEN ? AND ?", minPrice, maxPrice)
+	}
+
+	err := query.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+// GetByCategories returns active products belonging to any of the given categories, newest
+// first with the ID as a tie-breaker so offset pages do not overlap
+func (r *productRepository) GetByCategories(ctx context.Context, categoryIDs []string, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var products []*entities.Product
+	query := r.db.WithContext(ctx).Preload("Category").
+		Where("category_id IN ? AND is_active = ?", categoryIDs, true).
+		Order("created_at DESC").Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) GetByStore(ctx context.Context, storeID string, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var products []*entities.Product
+	query := r.db.WithContext(ctx).Preload("Category").
+		Where("store_id = ? AND is_active = ?", storeID, true).
+		Order("created_at DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&products).Error
+	return products, err
+}
+
+// Update saves every column of the product only if it is still at the version it was read
+// at, and bumps the version. Selecting "*" explicitly stops Save from falling back to an
+// upsert when no row matches.
 func (r *productRepository) Update(ctx context.Context, product *entities.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	version := product.Version
+	product.Version++
+
+	result := r.db.WithContext(ctx).Select("*").Where("version = ?", version).Save(product)
+	if result.Error != nil {
+		product.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		product.Version = version
+		return ErrProductVersionConflict
+	}
+	return nil
+}
+
+// UpdatePartial writes only the given columns, so fields changed concurrently by other
+// requests are not overwritten with stale values. Every write bumps the version; a positive
+// version must match the stored one, otherwise ErrProductVersionConflict is returned. A new
+// price is recorded in the price history as a manual change by fields["updated_by"].
+func (r *productRepository) UpdatePartial(ctx context.Context, id string, version int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	fields["version"] = gorm.Expr("version + 1")
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The old price is read under a row lock so the recorded change matches the write
+		newPrice, repriced := fields["price"].(float64)
+		var current entities.Product
+		if repriced {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "price").Where("id = ?", id).Take(&current).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		query := tx.Model(&entities.Product{}).Where("id = ?", id)
+		if version > 0 {
+			query = query.Where("version = ?", version)
+		}
+		result := query.Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if version > 0 {
+				return ErrProductVersionConflict
+			}
+			return ErrProductNotFound
+		}
+
+		if !repriced || newPrice == current.Price {
+			return nil
+		}
+		changedBy, _ := fields["updated_by"].(string)
+		return tx.Create(&entities.PriceHistory{
+			ProductID: id,
+			OldPrice:  current.Price,
+			NewPrice:  newPrice,
+			Source:    entities.PriceSourceManual,
+			ChangedBy: changedBy,
+		}).Error
+	})
 }
 
 func (r *productRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Delete(&entities.Product{}, "id = ?", id).Error
 }
 
+func (r *productRepository) DeleteByIDs(ctx context.Context, ids []string) (int64, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&entities.Product{})
+	return result.RowsAffected, result.Error
+}
+
+// AdjustPrices reprices the selected products and records each change in the price history,
+// all in one transaction. The rows are locked while they are read, and nothing is written if
+// any price would drop below zero.
+func (r *productRepository) AdjustPrices(ctx context.Context, adjustment *entities.PriceAdjustment, changedBy string) (int64, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("store_id = ?", adjustment.StoreID)
+		if adjustment.CategoryID != "" {
+			query = query.Where("category_id = ?", adjustment.CategoryID)
+		}
+		if len(adjustment.ProductIDs) > 0 {
+			query = query.Where("id IN ?", adjustment.ProductIDs)
+		}
+
+		var products []entities.Product
+		if err := query.Find(&products).Error; err != nil {
+			return err
+		}
+
+		history, err := adjustedPrices(products, adjustment, changedBy)
+		if err != nil {
+			return err
+		}
+		for _, change := range history {
+			if err := setProductPrice(tx, change.ProductID, change.NewPrice, changedBy); err != nil {
+				return err
+			}
+		}
+
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		affected = int64(len(history))
+		return nil
+	})
+	return affected, err
+}
+
+// adjustedPrices returns the price history entries adjustment makes to products, leaving out
+// products whose rounded price does not change. It fails with ErrNegativePrice before any
+// price is written if one would drop below zero.
+func adjustedPrices(products []entities.Product, adjustment *entities.PriceAdjustment, changedBy string) ([]entities.PriceHistory, error) {
+	history := make([]entities.PriceHistory, 0, len(products))
+	for _, product := range products {
+		price := adjustment.Apply(product.Price)
+		if price < 0 {
+			return nil, fmt.Errorf("%w: product %s would cost %.2f", ErrNegativePrice, product.ID, price)
+		}
+		if price == product.Price {
+			continue
+		}
+
+		history = append(history, entities.PriceHistory{
+			ProductID: product.ID,
+			OldPrice:  product.Price,
+			NewPrice:  price,
+			Source:    entities.PriceSourceAdjustment,
+			ChangedBy: changedBy,
+		})
+	}
+	return history, nil
+}
+
+// setProductPrice writes a new price the way every price change does, bumping the version
+// so optimistic updates based on the old price fail
+func setProductPrice(tx *gorm.DB, productID string, price float64, changedBy string) error {
+	return tx.Model(&entities.Product{}).Where("id = ?", productID).Updates(map[string]any{
+		"price":      price,
+		"updated_by": changedBy,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *productRepository) GetPriceHistory(ctx context.Context, productID string, limit, offset int) ([]*entities.PriceHistory, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var history []*entities.PriceHistory
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&history).Error
+	return history, err
+}
+
+// IncrementViewCounts uses UpdateColumn so flushing views leaves updated_at, and with it
+// the product's ETag, untouched
+func (r *productRepository) IncrementViewCounts(ctx context.Context, counts map[string]int64) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for productID, count := range counts {
+			err := tx.Model(&entities.Product{}).Where("id = ?", productID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *productRepository) UpdateStock(ctx context.Context, id string, stock int) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Model(&entities.Product{}).Where("id = ?", id).Update("stock", stock).Error
 }
 
-func (r *productRepository) Search(ctx context.Context, query string, limit, offset int) ([]*entities.Product, error) {
+// UpdateStockBatch applies all stock updates in a single transaction. Unknown product IDs
+// are reported in the results rather than failing the batch; any database error rolls it back.
+func (r *productRepository) UpdateStockBatch(ctx context.Context, updates []entities.StockUpdate) ([]entities.StockUpdateResult, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	results := make([]entities.StockUpdateResult, 0, len(updates))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, update := range updates {
+			result := tx.Model(&entities.Product{}).Where("id = ?", update.ProductID).Update("stock", update.Stock)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			entry := entities.StockUpdateResult{
+				ProductID: update.ProductID,
+				Stock:     update.Stock,
+				Updated:   result.RowsAffected > 0,
+			}
+			if !entry.Updated {
+				entry.Error = ErrProductNotFound.Error()
+			}
+			results = append(results, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *productRepository) Search(ctx context.Context, query, categoryID string, limit, offset int) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var products []*entities.Product
 	searchQuery := r.db.WithContext(ctx).Preload("Category").Where("is_active = ?", true)
 
@@ -99,6 +461,10 @@ func (r *productRepository) Search(ctx context.Context, query string, limit, off
 	searchTerm := "%" + strings.ToLower(query) + "%"
 	searchQuery = searchQuery.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
 
+	if categoryID != "" {
+		searchQuery = searchQuery.Where("category_id = ?", categoryID)
+	}
+
 	if limit > 0 {
 		searchQuery = searchQuery.Limit(limit)
 	}
@@ -119,10 +485,16 @@ func NewCategoryRepository(db *gorm.DB) *categoryRepository {
 }
 
 func (r *categoryRepository) Create(ctx context.Context, category *entities.Category) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Create(category).Error
 }
 
 func (r *categoryRepository) GetByID(ctx context.Context, id string) (*entities.Category, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var category entities.Category
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&category).Error
 	if err != nil {
@@ -135,6 +507,9 @@ func (r *categoryRepository) GetByID(ctx context.Context, id string) (*entities.
 }
 
 func (r *categoryRepository) GetByName(ctx context.Context, name string) (*entities.Category, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var category entities.Category
 	err := r.db.WithContext(ctx).Where("name = ?", name).First(&category).Error
 	if err != nil {
@@ -147,6 +522,9 @@ func (r *categoryRepository) GetByName(ctx context.Context, name string) (*entit
 }
 
 func (r *categoryRepository) GetAll(ctx context.Context, limit, offset int) ([]*entities.Category, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var categories []*entities.Category
 	query := r.db.WithContext(ctx).Where("is_active = ?", true)
 
@@ -162,15 +540,81 @@ func (r *categoryRepository) GetAll(ctx context.Context, limit, offset int) ([]*
 }
 
 func (r *categoryRepository) Update(ctx context.Context, category *entities.Category) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Save(category).Error
 }
 
+// Delete soft-deletes the category; default queries exclude it from then on
 func (r *categoryRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Delete(&entities.Category{}, "id = ?", id).Error
 }
 
+// DeleteAndReassign moves the category's products to fallbackID and soft-deletes the
+// category in one transaction
+func (r *categoryRepository) DeleteAndReassign(ctx context.Context, id, fallbackID string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Product{}).Where("category_id = ?", id).Update("category_id", fallbackID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Category{}, "id = ?", id).Error
+	})
+}
+
+// Restore clears the soft-delete marker of a deleted category
+func (r *categoryRepository) Restore(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Unscoped().Model(&entities.Category{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// CountProducts counts the products, excluding deleted ones, that reference the category
+func (r *categoryRepository) CountProducts(ctx context.Context, id string) (int64, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.Product{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
 func (r *productRepository) GetByIDs(ctx context.Context, ids []string) ([]*entities.Product, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var products []*entities.Product
 	err := r.db.WithContext(ctx).Where("id IN (?)", ids).Find(&products).Error
 	return products, err
 }
+
+// GetAvailability selects only the stock-related columns of the given products. Unknown
+// IDs are left out of the result.
+func (r *productRepository) GetAvailability(ctx context.Context, ids []string) ([]entities.ProductAvailability, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var availability []entities.ProductAvailability
+	err := r.db.WithContext(ctx).
+		Model(&entities.Product{}).
+		Select("id", "stock", "is_active").
+		Where("id IN ?", ids).
+		Scan(&availability).Error
+	return availability, err
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+)
+
+func TestAdjustedPricesRejectsWholeBatchOnNegativePrice(t *testing.T) {
+	amount := -5.0
+	products := []entities.Product{
+		{ID: "expensive", Price: 20},
+		{ID: "cheap", Price: 3},
+		{ID: "mid", Price: 10},
+	}
+
+	history, err := adjustedPrices(products, &entities.PriceAdjustment{Amount: &amount}, "staff")
+
+	if !errors.Is(err, ErrNegativePrice) {
+		t.Fatalf("err = %v, want ErrNegativePrice", err)
+	}
+	// AdjustPrices writes nothing unless every change is returned
+	if history != nil {
+		t.Errorf("history = %+v, want no changes to write", history)
+	}
+}
+
+func TestAdjustedPricesSkipsUnchangedPrices(t *testing.T) {
+	percent := 0.1
+	products := []entities.Product{
+		{ID: "cent", Price: 1},
+		{ID: "free", Price: 0},
+		{ID: "large", Price: 250},
+	}
+
+	history, err := adjustedPrices(products, &entities.PriceAdjustment{Percent: &percent}, "staff")
+	if err != nil {
+		t.Fatalf("adjustedPrices: %v", err)
+	}
+
+	// Only the large price moves by at least a cent once rounded
+	if len(history) != 1 {
+		t.Fatalf("history = %+v, want only the large product", history)
+	}
+	want := entities.PriceHistory{ProductID: "large", OldPrice: 250, NewPrice: 250.25, Source: entities.PriceSourceAdjustment, ChangedBy: "staff"}
+	if history[0] != want {
+		t.Errorf("change = %+v, want %+v", history[0], want)
+	}
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/repositories"
+)
+
+// pendingViewsKey is the Redis hash of product ID to views not yet written to Postgres
+const pendingViewsKey = "product_views:pending"
+
+type redisViewBuffer struct {
+	client *redis.Client
+}
+
+func NewRedisViewBuffer(client *redis.Client) repositories.ViewBuffer {
+	return &redisViewBuffer{client: client}
+}
+
+func (b *redisViewBuffer) Add(ctx context.Context, productID string) error {
+	return b.client.HIncrBy(ctx, pendingViewsKey, productID, 1).Err()
+}
+
+// Drain renames the pending hash to a key unique to this call, so views recorded while it
+// is being read land in a fresh hash and concurrent drains from other instances never
+// see the same counts
+func (b *redisViewBuffer) Drain(ctx context.Context) (map[string]int64, error) {
+	drainKey := pendingViewsKey + ":draining:" + uuid.NewString()
+	if err := b.client.Rename(ctx, pendingViewsKey, drainKey).Err(); err != nil {
+		// Nothing has been viewed since the last drain
+		if err.Error() == "ERR no such key" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	raw, err := b.client.HGetAll(ctx, drainKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	b.client.Del(ctx, drainKey)
+
+	counts := make(map[string]int64, len(raw))
+	for productID, value := range raw {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil && n > 0 {
+			counts[productID] = n
+		}
+	}
+	return counts, nil
+}
+
+func (b *redisViewBuffer) Requeue(ctx context.Context, counts map[string]int64) error {
+	pipe := b.client.Pipeline()
+	for productID, count := range counts {
+		pipe.HIncrBy(ctx, pendingViewsKey, productID, count)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
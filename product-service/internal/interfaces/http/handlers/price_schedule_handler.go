@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils"
+)
+
+// priceScheduleError maps a scheduled price change failure to a response
+func priceScheduleError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrPriceScheduleNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, services.ErrPriceScheduleOverlap) {
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, "PRICE_SCHEDULE_OVERLAP", err.Error())
+	}
+	if errors.Is(err, services.ErrPriceScheduleStarted) {
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, "PRICE_SCHEDULE_STARTED", err.Error())
+	}
+	return productMutationError(c, err)
+}
+
+func (h *ProductHandler) CreatePriceSchedule(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	var req dto.CreatePriceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.NewPrice == nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "new_price is required")
+	}
+
+	change := &entities.ScheduledPriceChange{
+		StoreID:    req.StoreID,
+		ProductID:  req.ProductID,
+		CategoryID: req.CategoryID,
+		NewPrice:   *req.NewPrice,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+	}
+	if err := h.priceSchedules.CreateSchedule(c.UserContext(), userID, change); err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return utils.CreatedResponse(c, "Price change scheduled successfully", change)
+}
+
+// GetPriceSchedules lists a store's scheduled price changes, latest start first
+func (h *ProductHandler) GetPriceSchedules(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	changes, err := h.priceSchedules.GetSchedulesByStore(c.UserContext(), userID, c.Query("store_id"), limit, offset)
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scheduled price changes retrieved successfully", changes)
+}
+
+func (h *ProductHandler) GetPriceSchedule(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	change, err := h.priceSchedules.GetSchedule(c.UserContext(), userID, c.Params("scheduleId"))
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scheduled price change retrieved successfully", change)
+}
+
+func (h *ProductHandler) UpdatePriceSchedule(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	var req dto.UpdatePriceScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	change, err := h.priceSchedules.UpdateSchedule(c.UserContext(), userID, c.Params("scheduleId"), &entities.PriceScheduleUpdate{
+		NewPrice:    req.NewPrice,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		ClearEndsAt: req.ClearEndsAt,
+	})
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scheduled price change updated successfully", change)
+}
+
+// CancelPriceSchedule stops a scheduled price change, restoring the previous prices if it
+// has already started. The change is kept, with status cancelled, for the record.
+func (h *ProductHandler) CancelPriceSchedule(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	change, err := h.priceSchedules.CancelSchedule(c.UserContext(), userID, c.Params("scheduleId"))
+	if err != nil {
+		return priceScheduleError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Scheduled price change cancelled successfully", change)
+}
@@ -1,29 +1,123 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils/pagination"
 )
 
 type ProductHandler struct {
 	productService  services.ProductService
 	categoryService services.CategoryService
+	viewCounter     services.ViewCounter
+	priceSchedules  services.PriceScheduleService
 }
 
-func NewProductHandler(productService services.ProductService, categoryService services.CategoryService) *ProductHandler {
+func NewProductHandler(productService services.ProductService, categoryService services.CategoryService, viewCounter services.ViewCounter, priceSchedules services.PriceScheduleService) *ProductHandler {
 	return &ProductHandler{
 		productService:  productService,
 		categoryService: categoryService,
+		viewCounter:     viewCounter,
+		priceSchedules:  priceSchedules,
 	}
 }
 
+// productMutationError maps a product mutation failure to a response
+func productMutationError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrProductNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
+	}
+	if errors.Is(err, services.ErrForbidden) || errors.Is(err, services.ErrInsufficientStoreRole) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, services.ErrVersionConflict) {
+		return utils.ErrorResponseWithData(c, fiber.StatusConflict, "VERSION_CONFLICT", err.Error(), nil)
+	}
+	var conflict *services.SKUConflictError
+	if errors.As(err, &conflict) {
+		return utils.ErrorResponseWithData(c, fiber.StatusConflict, "SKU_CONFLICT", err.Error(), fiber.Map{
+			"existing_product_id": conflict.ExistingProductID,
+		})
+	}
+	return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+}
+
+// maxCategoryFilter caps how many categories a single product listing can filter on
+const maxCategoryFilter = 50
+
+// parseCategoryIDs splits a comma-separated list of category IDs, dropping blanks and
+// duplicates and rejecting anything that is not a UUID
+func parseCategoryIDs(raw string) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid category ID: %s", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("at least one category ID is required")
+	}
+	if len(ids) > maxCategoryFilter {
+		return nil, fmt.Errorf("at most %d categories can be filtered on at once", maxCategoryFilter)
+	}
+	return ids, nil
+}
+
+// productETag identifies a product representation; the category is included because it is
+// embedded in the response and can change independently. The view count is included too,
+// since flushing views updates it without touching updated_at.
+func productETag(product *entities.Product) string {
+	return fmt.Sprintf(`W/"%s-%d-%d-%d"`, product.ID, product.UpdatedAt.UnixNano(), product.Category.UpdatedAt.UnixNano(), product.ViewCount)
+}
+
+// productLastModified is the later of the product's and its embedded category's update times.
+// It does not move with the view count, so clients revalidating by date may see a stale one.
+func productLastModified(product *entities.Product) time.Time {
+	if product.Category.UpdatedAt.After(product.UpdatedAt) {
+		return product.Category.UpdatedAt
+	}
+	return product.UpdatedAt
+}
+
+// productListETag hashes the identity, version and view count of every product in a page,
+// so additions, removals, edits and new views all change it. Lists carry no Last-Modified
+// since a removal would not advance any remaining product's update time.
+func productListETag(products []*entities.Product, extra string) string {
+	h := sha256.New()
+	for _, product := range products {
+		fmt.Fprintf(h, "%s:%d:%d:%d;", product.ID, product.UpdatedAt.UnixNano(), product.Category.UpdatedAt.UnixNano(), product.ViewCount)
+	}
+	h.Write([]byte(extra))
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
 // Product Handlers
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
 	var req dto.CreateProductRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
@@ -35,29 +129,45 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		Price:       req.Price,
 		Stock:       req.Stock,
 		CategoryID:  req.CategoryID,
+		StoreID:     req.StoreID,
 		SKU:         req.SKU,
 	}
 
-	if err := h.productService.CreateProduct(c.Context(), product); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	// ?dryRun=true validates the payload, including category, SKU and store checks, without saving it
+	dryRun := c.QueryBool("dryRun", false)
+	if err := h.productService.CreateProduct(c.UserContext(), userID, product, dryRun); err != nil {
+		return productMutationError(c, err)
+	}
+	if dryRun {
+		return utils.SuccessResponse(c, "Product is valid", product)
 	}
 
-	return utils.SuccessResponse(c, "Product created successfully", product)
+	c.Set(fiber.HeaderLocation, "/api/products/"+product.ID)
+	return utils.CreatedResponse(c, "Product created successfully", product)
 }
 
 func (h *ProductHandler) GetProduct(c *fiber.Ctx) error {
 	id := c.Params("id")
-	product, err := h.productService.GetProduct(c.Context(), id)
+	product, err := h.productService.GetProduct(c.UserContext(), id)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
 	}
 
+	// Lookups by other services are not shopper views
+	if c.Get("X-Internal-Service") == "" {
+		h.viewCounter.RecordView(product.ID)
+	}
+
+	if utils.NotModified(c, productETag(product), productLastModified(product)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return utils.SuccessResponse(c, "Product retrieved successfully", product)
 }
 
 func (h *ProductHandler) GetProductBySKU(c *fiber.Ctx) error {
 	sku := c.Params("sku")
-	product, err := h.productService.GetProductBySKU(c.Context(), sku)
+	product, err := h.productService.GetProductBySKU(c.UserContext(), c.Query("store_id"), sku)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
 	}
@@ -69,11 +179,66 @@ func (h *ProductHandler) GetProducts(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	products, err := h.productService.GetProducts(c.Context(), limit, offset)
+	// Multi-select category filter: products in any of the comma-separated categories
+	if categories := c.Query("categories"); categories != "" {
+		categoryIDs, err := parseCategoryIDs(categories)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
+		if limit < 1 || limit > 100 {
+			limit = 10
+		}
+		if offset < 0 {
+			offset = 0
+		}
+
+		products, err := h.productService.GetProductsByCategories(c.UserContext(), categoryIDs, limit, offset)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
+		}
+
+		if utils.NotModified(c, productListETag(products, ""), time.Time{}) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		return utils.SuccessResponse(c, "Products retrieved successfully", products)
+	}
+
+	// Cursor mode is selected by the presence of the cursor param; an empty value starts from the first page
+	if c.Context().QueryArgs().Has("cursor") {
+		if limit < 1 || limit > 100 {
+			limit = 10
+		}
+
+		products, nextCursor, err := h.productService.GetProductsByCursor(c.UserContext(), c.Query("cursor"), limit)
+		if err != nil {
+			if errors.Is(err, pagination.ErrInvalidCursor) {
+				return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+			}
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
+		}
+
+		if utils.NotModified(c, productListETag(products, nextCursor), time.Time{}) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		return utils.SuccessResponse(c, "Products retrieved successfully", dto.CursorPaginatedResponse{
+			Data:       products,
+			Limit:      limit,
+			NextCursor: nextCursor,
+			HasNext:    nextCursor != "",
+		})
+	}
+
+	products, err := h.productService.GetProducts(c.UserContext(), limit, offset)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
 	}
 
+	if utils.NotModified(c, productListETag(products, ""), time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return utils.SuccessResponse(c, "Products retrieved successfully", products)
 }
 
@@ -82,7 +247,7 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	products, err := h.productService.GetProductsByCategory(c.Context(), categoryID, limit, offset)
+	products, err := h.productService.GetProductsByCategory(c.UserContext(), categoryID, limit, offset)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -90,42 +255,77 @@ func (h *ProductHandler) GetProductsByCategory(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Products retrieved successfully", products)
 }
 
-func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
-	id := c.Params("id")
+func (h *ProductHandler) GetPopularProducts(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	var req dto.UpdateProductRequest
-	if err := c.BodyParser(&req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	products, err := h.productService.GetPopularProducts(c.UserContext(), limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
 	}
 
-	// Get existing product
-	product, err := h.productService.GetProduct(c.Context(), id)
+	return utils.SuccessResponse(c, "Products retrieved successfully", products)
+}
+
+func (h *ProductHandler) GetRelatedProducts(c *fiber.Ctx) error {
+	id := c.Params("id")
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	priceBand, err := strconv.ParseFloat(c.Query("priceBand", "0"), 64)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "priceBand must be a number")
 	}
 
-	// Update fields if provided
-	if req.Name != nil {
-		product.Name = *req.Name
-	}
-	if req.Description != nil {
-		product.Description = *req.Description
+	products, err := h.productService.GetRelatedProducts(c.UserContext(), id, limit, priceBand)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
-	if req.Price != nil {
-		product.Price = *req.Price
+
+	return utils.SuccessResponse(c, "Related products retrieved successfully", products)
+}
+
+func (h *ProductHandler) GetProductsByStore(c *fiber.Ctx) error {
+	storeID := c.Params("storeId")
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	products, err := h.productService.GetProductsByStore(c.UserContext(), storeID, limit, offset)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
 	}
-	if req.Stock != nil {
-		product.Stock = *req.Stock
+
+	return utils.SuccessResponse(c, "Products retrieved successfully", products)
+}
+
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
 	}
-	if req.CategoryID != nil {
-		product.CategoryID = *req.CategoryID
+
+	id := c.Params("id")
+
+	var req dto.UpdateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
-	if req.IsActive != nil {
-		product.IsActive = *req.IsActive
+
+	update := &entities.ProductUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		CategoryID:  req.CategoryID,
+		IsActive:    req.IsActive,
+		Version:     req.Version,
 	}
 
-	if err := h.productService.UpdateProduct(c.Context(), product); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	dryRun := c.QueryBool("dryRun", false)
+	product, err := h.productService.UpdateProduct(c.UserContext(), userID, id, update, dryRun)
+	if err != nil {
+		return productMutationError(c, err)
+	}
+	if dryRun {
+		return utils.SuccessResponse(c, "Product update is valid", product)
 	}
 
 	return utils.SuccessResponse(c, "Product updated successfully", product)
@@ -139,25 +339,122 @@ func (h *ProductHandler) UpdateProductStock(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if err := h.productService.UpdateProductStock(c.Context(), id, req.Stock); err != nil {
+	if err := h.productService.UpdateProductStock(c.UserContext(), id, req.Stock); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	return utils.SuccessResponse(c, "Product stock updated successfully", nil)
 }
 
+func (h *ProductHandler) UpdateStockBatch(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	var req []dto.BulkStockUpdateItem
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	updates := make([]entities.StockUpdate, 0, len(req))
+	for _, item := range req {
+		updates = append(updates, entities.StockUpdate{
+			ProductID: item.ID,
+			Stock:     item.Stock,
+		})
+	}
+
+	results, err := h.productService.UpdateStockBatch(c.UserContext(), userID, updates)
+	if err != nil {
+		return productMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Product stock updated successfully", results)
+}
+
 func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
 	id := c.Params("id")
 
-	if err := h.productService.DeleteProduct(c.Context(), id); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	if err := h.productService.DeleteProduct(c.UserContext(), userID, id); err != nil {
+		return productMutationError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "Product deleted successfully", nil)
 }
 
+func (h *ProductHandler) DeleteProducts(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	var req dto.BulkDeleteProductsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.productService.DeleteProducts(c.UserContext(), userID, req.Ids)
+	if err != nil {
+		return productMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Products deleted successfully", result)
+}
+
+// AdjustPrices reprices a store's products by category or ID in one transaction
+func (h *ProductHandler) AdjustPrices(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	var req dto.AdjustPricesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.productService.AdjustPrices(c.UserContext(), userID, &entities.PriceAdjustment{
+		StoreID:    req.StoreID,
+		CategoryID: req.CategoryID,
+		ProductIDs: req.ProductIDs,
+		Percent:    req.Percent,
+		Amount:     req.Amount,
+	})
+	if err != nil {
+		return productMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Prices adjusted successfully", result)
+}
+
+func (h *ProductHandler) GetPriceHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	history, err := h.productService.GetPriceHistory(c.UserContext(), id, limit, offset)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "Price history retrieved successfully", dto.NewPriceHistoryResponse(history))
+}
+
 func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
-	query := c.Query("q")
+	query := strings.TrimSpace(c.Query("q"))
 	if query == "" {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Search query is required")
 	}
@@ -165,8 +462,19 @@ func (h *ProductHandler) SearchProducts(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	products, err := h.productService.SearchProducts(c.Context(), query, limit, offset)
+	// Optionally narrow the search to a single category
+	categoryID := c.Query("category_id")
+	if categoryID != "" {
+		if _, err := h.categoryService.GetCategory(c.UserContext(), categoryID); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Category not found")
+		}
+	}
+
+	products, err := h.productService.SearchProducts(c.UserContext(), query, categoryID, limit, offset)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearchQuery) {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		}
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to search products")
 	}
 
@@ -185,16 +493,17 @@ func (h *ProductHandler) CreateCategory(c *fiber.Ctx) error {
 		Description: req.Description,
 	}
 
-	if err := h.categoryService.CreateCategory(c.Context(), category); err != nil {
+	if err := h.categoryService.CreateCategory(c.UserContext(), category); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	return utils.SuccessResponse(c, "Category created successfully", category)
+	c.Set(fiber.HeaderLocation, "/api/categories/"+category.ID)
+	return utils.CreatedResponse(c, "Category created successfully", category)
 }
 
 func (h *ProductHandler) GetCategory(c *fiber.Ctx) error {
 	id := c.Params("id")
-	category, err := h.categoryService.GetCategory(c.Context(), id)
+	category, err := h.categoryService.GetCategory(c.UserContext(), id)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Category not found")
 	}
@@ -206,7 +515,7 @@ func (h *ProductHandler) GetCategories(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	categories, err := h.categoryService.GetCategories(c.Context(), limit, offset)
+	categories, err := h.categoryService.GetCategories(c.UserContext(), limit, offset)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve categories")
 	}
@@ -223,7 +532,7 @@ func (h *ProductHandler) UpdateCategory(c *fiber.Ctx) error {
 	}
 
 	// Get existing category
-	category, err := h.categoryService.GetCategory(c.Context(), id)
+	category, err := h.categoryService.GetCategory(c.UserContext(), id)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "Category not found")
 	}
@@ -239,7 +548,7 @@ func (h *ProductHandler) UpdateCategory(c *fiber.Ctx) error {
 		category.IsActive = *req.IsActive
 	}
 
-	if err := h.categoryService.UpdateCategory(c.Context(), category); err != nil {
+	if err := h.categoryService.UpdateCategory(c.UserContext(), category); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
@@ -249,23 +558,62 @@ func (h *ProductHandler) UpdateCategory(c *fiber.Ctx) error {
 func (h *ProductHandler) DeleteCategory(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	if err := h.categoryService.DeleteCategory(c.Context(), id); err != nil {
+	if err := h.categoryService.DeleteCategory(c.UserContext(), id, c.QueryBool("force", false)); err != nil {
+		if errors.Is(err, services.ErrCategoryInUse) {
+			return utils.ErrorResponseWithCode(c, fiber.StatusConflict, "CATEGORY_IN_USE", err.Error())
+		}
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	return utils.SuccessResponse(c, "Category deleted successfully", nil)
 }
 
+func (h *ProductHandler) RestoreCategory(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.categoryService.RestoreCategory(c.UserContext(), id); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Deleted category not found")
+	}
+
+	return utils.SuccessResponse(c, "Category restored successfully", nil)
+}
+
 func (h *ProductHandler) GetProductsByIds(c *fiber.Ctx) error {
 	var req dto.GetProductsByIdsRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	products, err := h.productService.GetProductsByIds(c.Context(), req.Ids)
+	products, err := h.productService.GetProductsByIds(c.UserContext(), req.Ids)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve products")
 	}
 
 	return utils.SuccessResponse(c, "Products retrieved successfully", products)
 }
+
+func (h *ProductHandler) GetProductAvailability(c *fiber.Ctx) error {
+	id := c.Params("id")
+	availability, err := h.productService.GetProductAvailability(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Product not found")
+	}
+
+	return utils.SuccessResponse(c, "Product availability retrieved successfully", availability)
+}
+
+// GetProductsAvailability returns the availability of several products. Unknown IDs are
+// omitted from the result.
+func (h *ProductHandler) GetProductsAvailability(c *fiber.Ctx) error {
+	var req dto.GetProductsByIdsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	availability, err := h.productService.GetProductsAvailability(c.UserContext(), req.Ids)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "Product availability retrieved successfully", availability)
+}
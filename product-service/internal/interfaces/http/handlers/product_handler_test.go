@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/domain/services"
+)
+
+func TestProductETagsChangeWithViewCount(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	product := &entities.Product{ID: "product", UpdatedAt: updatedAt, ViewCount: 10}
+	viewed := &entities.Product{ID: "product", UpdatedAt: updatedAt, ViewCount: 11}
+
+	if productETag(product) == productETag(viewed) {
+		t.Errorf("product ETag %s did not change with the view count", productETag(product))
+	}
+	if productListETag([]*entities.Product{product}, "") == productListETag([]*entities.Product{viewed}, "") {
+		t.Error("list ETag did not change with a view count")
+	}
+
+	same := &entities.Product{ID: "product", UpdatedAt: updatedAt, ViewCount: 10}
+	if productETag(product) != productETag(same) {
+		t.Errorf("ETags %s and %s differ for the same representation", productETag(product), productETag(same))
+	}
+}
+
+// priceHistoryStub serves a fixed price history; other ProductService methods panic
+type priceHistoryStub struct {
+	services.ProductService
+	history []*entities.PriceHistory
+}
+
+func (s priceHistoryStub) GetPriceHistory(ctx context.Context, id string, limit, offset int) ([]*entities.PriceHistory, error) {
+	return s.history, nil
+}
+
+func TestGetPriceHistoryOmitsChangedBy(t *testing.T) {
+	staffID := "6f1c1e4a-3b1d-4c55-9d43-2f5e6a7b8c9d"
+	handler := NewProductHandler(priceHistoryStub{history: []*entities.PriceHistory{{
+		ID:        "entry",
+		ProductID: "product",
+		OldPrice:  10,
+		NewPrice:  12,
+		Source:    entities.PriceSourceAdjustment,
+		ChangedBy: staffID,
+	}}}, nil, nil, nil)
+
+	app := fiber.New()
+	app.Get("/products/:id/price-history", handler.GetPriceHistory)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/products/product/price-history", nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", resp.StatusCode, body)
+	}
+	if strings.Contains(string(body), "changed_by") || strings.Contains(string(body), staffID) {
+		t.Errorf("public price history exposes who made the change: %s", body)
+	}
+	if !strings.Contains(string(body), `"new_price":12`) {
+		t.Errorf("price change missing from response: %s", body)
+	}
+}
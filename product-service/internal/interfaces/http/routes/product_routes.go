@@ -1,10 +1,14 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/application/services"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/external"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/middleware"
 )
 
 func SetupProductRoutes(api fiber.Router, deps RoutesDependencies) {
@@ -12,23 +16,58 @@ func SetupProductRoutes(api fiber.Router, deps RoutesDependencies) {
 	productRepo := repositories.NewProductRepository(deps.Db)
 	categoryRepo := repositories.NewCategoryRepository(deps.Db)
 
+	// Initialize external clients
+	storeService := external.NewStoreServiceClient(deps.Config.StoreServiceURL)
+
 	// Initialize services
-	productService := services.NewProductService(productRepo, categoryRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
+	productService := services.NewProductService(productRepo, categoryRepo, storeService, deps.Config.Search)
+	categoryService := services.NewCategoryService(categoryRepo, deps.Config.FallbackCategoryID)
+
+	// Views are buffered in Redis and written to the products in the background
+	viewCounter := services.NewViewCounter(repositories.NewRedisViewBuffer(deps.RedisClient), productRepo)
+	go viewCounter.Run(context.Background(), deps.Config.ViewFlushInterval)
+
+	// Scheduled price changes start and end in the background, on one instance at a time
+	priceSchedules := services.NewPriceScheduleService(
+		repositories.NewPriceScheduleRepository(deps.Db),
+		productRepo,
+		categoryRepo,
+		storeService,
+		repositories.NewRedisJobLock(deps.RedisClient),
+	)
+	go priceSchedules.Run(context.Background(), deps.Config.PriceScheduleInterval)
 
 	// Initialize handlers
-	productHandler := handlers.NewProductHandler(productService, categoryService)
+	productHandler := handlers.NewProductHandler(productService, categoryService, viewCounter, priceSchedules)
+
+	// Requests from other services are HMAC-signed; stock writes are only accepted from them
+	signing := deps.Config.InternalSigning
+	verifyInternal := middleware.InternalSignature(signing.Secret, signing.MaxSkew, false)
+	requireInternal := middleware.InternalSignature(signing.Secret, signing.MaxSkew, true)
 
 	// Product routes
-	products := api.Group("/products")
+	products := api.Group("/products", verifyInternal)
 	products.Post("/", productHandler.CreateProduct)
 	products.Post("/ids", productHandler.GetProductsByIds)
+	products.Post("/availability", productHandler.GetProductsAvailability)
+	products.Post("/batch-delete", productHandler.DeleteProducts)
+	products.Post("/price-adjust", productHandler.AdjustPrices)
+	products.Post("/price-schedules", productHandler.CreatePriceSchedule)
+	products.Get("/price-schedules", productHandler.GetPriceSchedules)
+	products.Get("/price-schedules/:scheduleId", productHandler.GetPriceSchedule)
+	products.Put("/price-schedules/:scheduleId", productHandler.UpdatePriceSchedule)
+	products.Delete("/price-schedules/:scheduleId", productHandler.CancelPriceSchedule)
+	products.Post("/stock/bulk", requireInternal, productHandler.UpdateStockBatch)
 	products.Get("/", productHandler.GetProducts)
 	products.Get("/search", productHandler.SearchProducts)
+	products.Get("/popular", productHandler.GetPopularProducts)
 	products.Get("/sku/:sku", productHandler.GetProductBySKU)
 	products.Get("/:id", productHandler.GetProduct)
+	products.Get("/:id/availability", productHandler.GetProductAvailability)
+	products.Get("/:id/related", productHandler.GetRelatedProducts)
+	products.Get("/:id/price-history", productHandler.GetPriceHistory)
 	products.Put("/:id", productHandler.UpdateProduct)
-	products.Patch("/:id/stock", productHandler.UpdateProductStock)
+	products.Patch("/:id/stock", requireInternal, productHandler.UpdateProductStock)
 	products.Delete("/:id", productHandler.DeleteProduct)
 
 	// Category routes
@@ -38,7 +77,11 @@ func SetupProductRoutes(api fiber.Router, deps RoutesDependencies) {
 	categories.Get("/:id", productHandler.GetCategory)
 	categories.Put("/:id", productHandler.UpdateCategory)
 	categories.Delete("/:id", productHandler.DeleteCategory)
+	categories.Post("/:id/restore", productHandler.RestoreCategory)
 
 	// Products by category
 	products.Get("/category/:categoryId", productHandler.GetProductsByCategory)
+
+	// Products by store
+	api.Get("/stores/:storeId/products", productHandler.GetProductsByStore)
 }
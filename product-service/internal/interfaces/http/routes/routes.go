@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/docs"
 	"github.com/tasiuskenways/scalable-ecommerce/product-service/internal/utils"
 	"gorm.io/gorm"
 )
@@ -15,6 +16,9 @@ type RoutesDependencies struct {
 }
 
 func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
+	// OpenAPI description and Swagger UI
+	docs.Register(app)
+
 	api := app.Group("/api")
 
 	api.Get("/health", func(c *fiber.Ctx) error {
@@ -11,19 +11,30 @@ type AddItemRequest struct {
 	Quantity  int    `json:"quantity" validate:"required,min=1"`
 }
 
+// UpdateItemRequest sets an item's quantity; a quantity of 0 removes the item
 type UpdateItemRequest struct {
-	Quantity int `json:"quantity" validate:"required,min=1"`
+	Quantity *int `json:"quantity" validate:"required,min=0"`
+}
+
+// ReorderItemsRequest lists every item in the cart in the order they should be shown
+type ReorderItemsRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"required"`
 }
 
 type CartItemResponse struct {
 	ID          string          `json:"id"`
 	ProductID   string          `json:"product_id"`
+	ProductName string          `json:"product_name"`
+	ProductSKU  string          `json:"product_sku"`
 	Quantity    int             `json:"quantity"`
 	PriceAtTime decimal.Decimal `json:"price_at_time"`
+	Currency    string          `json:"currency"`
 	Subtotal    decimal.Decimal `json:"subtotal"`
 	Product     *ProductInfo    `json:"product"`
 	Available   bool            `json:"available"`
 	StockStatus string          `json:"stock_status"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
 
 type ProductInfo struct {
@@ -36,35 +47,69 @@ type ProductInfo struct {
 	StoreID  string          `json:"store_id"`
 }
 
+// StoreCartItems groups a store's items; ShippingEstimate and TaxEstimate are
+// omitted when the store's settings could not be fetched
 type StoreCartItems struct {
-	StoreID    string             `json:"store_id"`
-	Items      []CartItemResponse `json:"items"`
-	ItemCount  int                `json:"item_count"`
-	StoreTotal decimal.Decimal    `json:"store_total"`
+	StoreID          string             `json:"store_id"`
+	Currency         string             `json:"currency"`
+	Items            []CartItemResponse `json:"items"`
+	ItemCount        int                `json:"item_count"`
+	StoreTotal       decimal.Decimal    `json:"store_total"`
+	ShippingEstimate *decimal.Decimal   `json:"shipping_estimate,omitempty"`
+	TaxEstimate      *decimal.Decimal   `json:"tax_estimate,omitempty"`
 }
 
+// CartResponse totals cover items in the cart currency only; GrandTotal adds the
+// shipping and tax estimates of every store to TotalPrice
 type CartResponse struct {
-	ID         string             `json:"id"`
-	UserID     string             `json:"user_id"`
-	Items      []CartItemResponse `json:"items"`
-	Stores     []StoreCartItems   `json:"stores"`
-	TotalItems int                `json:"total_items"`
-	TotalPrice decimal.Decimal    `json:"total_price"`
-	CreatedAt  time.Time          `json:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at"`
+	ID            string             `json:"id"`
+	UserID        string             `json:"user_id"`
+	Currency      string             `json:"currency"`
+	Items         []CartItemResponse `json:"items"`
+	Stores        []StoreCartItems   `json:"stores"`
+	TotalItems    int                `json:"total_items"`
+	TotalPrice    decimal.Decimal    `json:"total_price"`
+	ShippingTotal decimal.Decimal    `json:"shipping_total"`
+	TaxTotal      decimal.Decimal    `json:"tax_total"`
+	GrandTotal    decimal.Decimal    `json:"grand_total"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	// QuantityAdjustment is set when an add was capped to the available stock
+	QuantityAdjustment *QuantityAdjustment `json:"quantity_adjustment,omitempty"`
+	// LockedUntil is set while a checkout holds the cart and its items cannot change
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// QuantityAdjustment reports that fewer units were added than requested
+type QuantityAdjustment struct {
+	ProductID string `json:"product_id"`
+	Requested int    `json:"requested"`
+	Added     int    `json:"added"`
+	Warning   string `json:"warning"`
+}
+
+// CartLockResponse reports the checkout lock taken on a cart
+type CartLockResponse struct {
+	Locked    bool       `json:"locked"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 type CartValidationResponse struct {
-	Valid         bool                  `json:"valid"`
-	TotalItems    int                   `json:"total_items"`
-	TotalPrice    decimal.Decimal       `json:"total_price"`
-	InvalidItems  []InvalidItemResponse `json:"invalid_items,omitempty"`
-	UpdatedPrices []PriceUpdateResponse `json:"updated_prices,omitempty"`
+	Valid    bool   `json:"valid"`
+	Currency string `json:"currency"`
+	// ValidationIncomplete is set when some items could not be checked against the product service
+	ValidationIncomplete bool                  `json:"validation_incomplete"`
+	TotalItems           int                   `json:"total_items"`
+	TotalPrice           decimal.Decimal       `json:"total_price"`
+	InvalidItems         []InvalidItemResponse `json:"invalid_items,omitempty"`
+	UpdatedPrices        []PriceUpdateResponse `json:"updated_prices,omitempty"`
 }
 
 type InvalidItemResponse struct {
 	ProductID string `json:"product_id"`
 	Reason    string `json:"reason"`
+	// Retryable marks items that could not be verified rather than found invalid
+	Retryable bool `json:"retryable,omitempty"`
 }
 
 type PriceUpdateResponse struct {
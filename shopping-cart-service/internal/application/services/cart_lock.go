@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/services"
+)
+
+// LockCart freezes the cart's items for the checkout lock TTL so the order being created
+// matches what the user saw. Locking again while locked restarts the TTL.
+func (s *cartService) LockCart(ctx *fiber.Ctx, userID string) (*dto.CartLockResponse, error) {
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, services.ErrCartEmpty
+	}
+	items, err := s.cartItemRepo.GetByCartID(ctx.UserContext(), cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, services.ErrCartEmpty
+	}
+
+	if err := s.cartLocks.Lock(ctx.UserContext(), userID, s.config.CheckoutLockTTL); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.config.CheckoutLockTTL)
+	return &dto.CartLockResponse{Locked: true, ExpiresAt: &expiresAt}, nil
+}
+
+func (s *cartService) UnlockCart(ctx *fiber.Ctx, userID string) error {
+	return s.cartLocks.Unlock(ctx.UserContext(), userID)
+}
+
+// ensureUnlocked fails with ErrCartLocked while a checkout holds the user's cart. When the
+// lock cannot be read the change is let through, so a Redis outage does not freeze carts.
+func (s *cartService) ensureUnlocked(ctx context.Context, userID string) error {
+	lockedUntil, err := s.cartLocks.LockedUntil(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to check checkout lock of cart for user %s: %v", userID, err)
+		return nil
+	}
+	if !lockedUntil.IsZero() {
+		return services.ErrCartLocked
+	}
+	return nil
+}
+
+// cartLockedUntil returns when the cart's checkout lock expires, or nil when it is not locked
+func (s *cartService) cartLockedUntil(ctx context.Context, userID string) *time.Time {
+	lockedUntil, err := s.cartLocks.LockedUntil(ctx, userID)
+	if err != nil || lockedUntil.IsZero() {
+		return nil
+	}
+	return &lockedUntil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -17,27 +18,37 @@ import (
 type cartService struct {
 	cartRepo       repositories.CartRepository
 	cartItemRepo   repositories.CartItemRepository
+	cartLocks      repositories.CartLockRepository
 	productService *external.ProductServiceClient
+	storeService   *external.StoreServiceClient
 	config         *config.Config
 }
 
+// defaultCurrency matches the store service default and applies to carts and
+// items created before currencies were recorded
+const defaultCurrency = "USD"
+
 func NewCartService(
 	cartRepo repositories.CartRepository,
 	cartItemRepo repositories.CartItemRepository,
+	cartLocks repositories.CartLockRepository,
 	productService *external.ProductServiceClient,
+	storeService *external.StoreServiceClient,
 	config *config.Config,
 ) services.CartService {
 	return &cartService{
 		cartRepo:       cartRepo,
 		cartItemRepo:   cartItemRepo,
+		cartLocks:      cartLocks,
 		productService: productService,
+		storeService:   storeService,
 		config:         config,
 	}
 }
 
 func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse, error) {
 	// Get cart from database
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +57,7 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 		// Return empty cart if not found
 		return &dto.CartResponse{
 			UserID:     userID,
+			Currency:   defaultCurrency,
 			Items:      []dto.CartItemResponse{},
 			Stores:     []dto.StoreCartItems{},
 			TotalItems: 0,
@@ -54,15 +66,18 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 	}
 
 	// Get cart items
-	items, err := s.cartItemRepo.GetByCartID(ctx.Context(), cart.ID)
+	items, err := s.cartItemRepo.GetByCartID(ctx.UserContext(), cart.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	cartCurrency := cartCurrency(cart, items)
+
 	// Fetch product details for all items
 	cartResponse := &dto.CartResponse{
 		ID:         cart.ID,
 		UserID:     cart.UserID,
+		Currency:   cartCurrency,
 		Items:      []dto.CartItemResponse{},
 		Stores:     []dto.StoreCartItems{},
 		TotalItems: 0,
@@ -80,12 +95,14 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 		return cartResponse, nil
 	}
 
-	products, err := s.productService.GetProducts(ctx.Context(), productIDs)
+	products, err := s.productService.GetProducts(ctx.UserContext(), productIDs)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, item := range items {
+		itemCurrency := itemCurrency(item, cartCurrency)
+
 		// Find product details
 		var product *external.ProductResponse
 		for _, p := range products {
@@ -100,11 +117,16 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 			cartItem := dto.CartItemResponse{
 				ID:          item.ID,
 				ProductID:   item.ProductID,
+				ProductName: item.ProductName,
+				ProductSKU:  item.ProductSKU,
 				Quantity:    item.Quantity,
 				PriceAtTime: item.PriceAtTime,
+				Currency:    itemCurrency,
 				Subtotal:    item.GetSubtotal(),
 				Available:   false,
 				StockStatus: "Product not found",
+				CreatedAt:   item.CreatedAt,
+				UpdatedAt:   item.UpdatedAt,
 			}
 			cartResponse.Items = append(cartResponse.Items, cartItem)
 			continue
@@ -125,11 +147,21 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 			stockStatus = "Product unavailable"
 		}
 
+		// Items priced in another currency are listed but kept out of the cart total
+		currencyMatches := itemCurrency == cartCurrency
+		if !currencyMatches {
+			available = false
+			stockStatus = fmt.Sprintf("Priced in %s, cart is in %s", itemCurrency, cartCurrency)
+		}
+
 		cartItem := dto.CartItemResponse{
 			ID:          item.ID,
 			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			ProductSKU:  item.ProductSKU,
 			Quantity:    item.Quantity,
 			PriceAtTime: item.PriceAtTime,
+			Currency:    itemCurrency,
 			Subtotal:    item.GetSubtotal(),
 			Product: &dto.ProductInfo{
 				Name:     product.Name,
@@ -142,25 +174,35 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 			},
 			Available:   available,
 			StockStatus: stockStatus,
+			CreatedAt:   item.CreatedAt,
+			UpdatedAt:   item.UpdatedAt,
 		}
 
 		cartResponse.Items = append(cartResponse.Items, cartItem)
+		if !currencyMatches {
+			continue
+		}
 		cartResponse.TotalItems += item.Quantity
 		cartResponse.TotalPrice = cartResponse.TotalPrice.Add(item.GetSubtotal())
 	}
 
-	// Group items by store
+	// Group items by store and currency so no group mixes currencies. Groups appear in the
+	// order of their first item and keep the cart's item order.
 	storeGroups := make(map[string]*dto.StoreCartItems)
+	var groupKeys []string
 	for _, item := range cartResponse.Items {
 		if item.Product != nil {
 			storeID := item.Product.StoreID
-			if storeGroup, exists := storeGroups[storeID]; exists {
+			groupKey := storeID + ":" + item.Currency
+			if storeGroup, exists := storeGroups[groupKey]; exists {
 				storeGroup.Items = append(storeGroup.Items, item)
 				storeGroup.ItemCount += item.Quantity
 				storeGroup.StoreTotal = storeGroup.StoreTotal.Add(item.Subtotal)
 			} else {
-				storeGroups[storeID] = &dto.StoreCartItems{
+				groupKeys = append(groupKeys, groupKey)
+				storeGroups[groupKey] = &dto.StoreCartItems{
 					StoreID:    storeID,
+					Currency:   item.Currency,
 					Items:      []dto.CartItemResponse{item},
 					ItemCount:  item.Quantity,
 					StoreTotal: item.Subtotal,
@@ -169,116 +211,179 @@ func (s *cartService) GetCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse,
 		}
 	}
 
-	// Convert map to slice
-	for _, storeGroup := range storeGroups {
-		cartResponse.Stores = append(cartResponse.Stores, *storeGroup)
+	for _, groupKey := range groupKeys {
+		cartResponse.Stores = append(cartResponse.Stores, *storeGroups[groupKey])
 	}
 
+	s.applyStoreEstimates(ctx.UserContext(), cartResponse)
+	cartResponse.LockedUntil = s.cartLockedUntil(ctx.UserContext(), userID)
+
 	return cartResponse, nil
 }
 
 func (s *cartService) AddItemToCart(ctx *fiber.Ctx, userID string, req *dto.AddItemRequest) (*dto.CartResponse, error) {
+	if req.Quantity < 1 {
+		return nil, fmt.Errorf("%w: must be at least 1", services.ErrInvalidQuantity)
+	}
+
+	if err := s.ensureUnlocked(ctx.UserContext(), userID); err != nil {
+		return nil, err
+	}
+
 	// Validate product and check stock
-	product, err := s.productService.GetProduct(ctx.Context(), req.ProductID)
+	product, err := s.productService.GetProduct(ctx.UserContext(), req.ProductID)
 	if err != nil {
-		return nil, errors.New("product not found")
+		if errors.Is(err, external.ErrCircuitOpen) {
+			return nil, services.ErrProductServiceUnavailable
+		}
+		return nil, services.ErrProductNotFound
 	}
 
 	if !product.IsActive {
-		return nil, errors.New("product is not available")
+		return nil, services.ErrProductNotAvailable
 	}
 
-	if product.Stock < req.Quantity {
-		return nil, fmt.Errorf("insufficient stock. Only %d available", product.Stock)
+	// In soft mode an add beyond the stock is capped below instead of rejected
+	capToStock := ctx.QueryBool("cap_to_stock", s.config.CapQuantityToStock)
+	if product.Stock < 1 || (!capToStock && product.Stock < req.Quantity) {
+		return nil, &services.InsufficientStockError{Available: product.Stock}
 	}
 
-	// Get or create cart
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	currency, err := s.storeCurrency(ctx.UserContext(), product.StoreID)
 	if err != nil {
 		return nil, err
 	}
 
-	if cart == nil {
-		// Create new cart
-		cart = &entities.Cart{
-			UserID: userID,
-		}
-		if err := s.cartRepo.Create(ctx.Context(), cart); err != nil {
-			return nil, err
-		}
+	cart, err := s.cartRepo.GetOrCreateByUserID(ctx.UserContext(), userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if item already exists in cart
-	existingItem, err := s.cartItemRepo.GetByCartAndProduct(ctx.Context(), cart.ID, req.ProductID)
-	if err != nil {
+	if err := s.ensureCartCurrency(ctx.UserContext(), cart, currency); err != nil {
 		return nil, err
 	}
 
-	if existingItem != nil {
-		// Update quantity
-		newQuantity := existingItem.Quantity + req.Quantity
-		if product.Stock < newQuantity {
-			return nil, fmt.Errorf("insufficient stock. Only %d available", product.Stock)
-		}
-		existingItem.Quantity = newQuantity
-		existingItem.PriceAtTime = decimal.NewFromFloat(product.Price)
-		if err := s.cartItemRepo.Update(ctx.Context(), existingItem); err != nil {
+	quantity := req.Quantity
+	var adjustment *dto.QuantityAdjustment
+	if capToStock {
+		existing, err := s.cartItemRepo.GetByCartAndProduct(ctx.UserContext(), cart.ID, req.ProductID)
+		if err != nil {
 			return nil, err
 		}
-	} else {
-		// Create new cart item
-		cartItem := &entities.CartItem{
-			CartID:      cart.ID,
-			ProductID:   req.ProductID,
-			Quantity:    req.Quantity,
-			PriceAtTime: decimal.NewFromFloat(product.Price),
+		inCart := 0
+		if existing != nil {
+			inCart = existing.Quantity
 		}
-		if err := s.cartItemRepo.Create(ctx.Context(), cartItem); err != nil {
+
+		if available := product.Stock - inCart; quantity > available {
+			added := max(available, 0)
+			adjustment = &dto.QuantityAdjustment{
+				ProductID: req.ProductID,
+				Requested: req.Quantity,
+				Added:     added,
+				Warning:   fmt.Sprintf("Only %d available; added %d of the %d requested", product.Stock, added, req.Quantity),
+			}
+			quantity = added
+		}
+	}
+
+	if quantity < 1 {
+		// Everything in stock is already in the cart
+		cartResponse, err := s.GetCart(ctx, userID)
+		if err != nil {
 			return nil, err
 		}
+		cartResponse.QuantityAdjustment = adjustment
+		return cartResponse, nil
+	}
+
+	// Insert the item, or add to the quantity already in the cart for this product.
+	// Done as a single upsert so concurrent adds cannot create duplicate rows.
+	cartItem := &entities.CartItem{
+		CartID:      cart.ID,
+		ProductID:   req.ProductID,
+		Quantity:    quantity,
+		PriceAtTime: decimal.NewFromFloat(product.Price),
+		Currency:    currency,
+	}
+	cartItem.SnapshotProduct(product.Name, product.SKU)
+	if err := s.cartItemRepo.AddQuantity(ctx.UserContext(), cartItem, product.Stock); err != nil {
+		if errors.Is(err, repositories.ErrQuantityExceedsLimit) {
+			return nil, &services.InsufficientStockError{Available: product.Stock}
+		}
+		return nil, err
 	}
 
 	// Return updated cart
-	return s.GetCart(ctx, userID)
+	cartResponse, err := s.GetCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cartResponse.QuantityAdjustment = adjustment
+	return cartResponse, nil
 }
 
 func (s *cartService) UpdateCartItem(ctx *fiber.Ctx, userID string, itemID string, req *dto.UpdateItemRequest) (*dto.CartResponse, error) {
-	// Get cart
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	if req.Quantity == nil || *req.Quantity < 0 {
+		return nil, fmt.Errorf("%w: must be 0 or more", services.ErrInvalidQuantity)
+	}
+
+	if err := s.ensureUnlocked(ctx.UserContext(), userID); err != nil {
+		return nil, err
+	}
+
+	// Setting the quantity to zero removes the item
+	if *req.Quantity == 0 {
+		return s.RemoveItemFromCart(ctx, userID, itemID)
+	}
+	quantity := *req.Quantity
+
+	// A user without a cart has no items, so there is nothing to create
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
 	if cart == nil {
-		return nil, errors.New("cart not found")
+		return nil, services.ErrCartItemNotFound
 	}
 
 	// Get cart item
-	item, err := s.cartItemRepo.GetByID(ctx.Context(), itemID)
+	item, err := s.cartItemRepo.GetByID(ctx.UserContext(), itemID)
 	if err != nil {
 		return nil, err
 	}
 	if item == nil || item.CartID != cart.ID {
-		return nil, errors.New("cart item not found")
+		return nil, services.ErrCartItemNotFound
 	}
 
 	// Validate product and check stock
-	product, err := s.productService.GetProduct(ctx.Context(), item.ProductID)
+	product, err := s.productService.GetProduct(ctx.UserContext(), item.ProductID)
 	if err != nil {
-		return nil, errors.New("product not found")
+		if errors.Is(err, external.ErrCircuitOpen) {
+			return nil, services.ErrProductServiceUnavailable
+		}
+		return nil, services.ErrProductNotFound
 	}
 
 	if !product.IsActive {
-		return nil, errors.New("product is not available")
+		return nil, services.ErrProductNotAvailable
+	}
+
+	if product.Stock < quantity {
+		return nil, &services.InsufficientStockError{Available: product.Stock}
 	}
 
-	if product.Stock < req.Quantity {
-		return nil, fmt.Errorf("insufficient stock. Only %d available", product.Stock)
+	currency, err := s.storeCurrency(ctx.UserContext(), product.StoreID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update quantity and price
-	item.Quantity = req.Quantity
+	item.Quantity = quantity
 	item.PriceAtTime = decimal.NewFromFloat(product.Price)
-	if err := s.cartItemRepo.Update(ctx.Context(), item); err != nil {
+	item.Currency = currency
+	item.SnapshotProduct(product.Name, product.SKU)
+	if err := s.cartItemRepo.Update(ctx.UserContext(), item); err != nil {
 		return nil, err
 	}
 
@@ -287,26 +392,29 @@ func (s *cartService) UpdateCartItem(ctx *fiber.Ctx, userID string, itemID strin
 }
 
 func (s *cartService) RemoveItemFromCart(ctx *fiber.Ctx, userID string, itemID string) (*dto.CartResponse, error) {
-	// Get cart
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	if err := s.ensureUnlocked(ctx.UserContext(), userID); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
 	if cart == nil {
-		return nil, errors.New("cart not found")
+		return nil, services.ErrCartItemNotFound
 	}
 
 	// Get cart item
-	item, err := s.cartItemRepo.GetByID(ctx.Context(), itemID)
+	item, err := s.cartItemRepo.GetByID(ctx.UserContext(), itemID)
 	if err != nil {
 		return nil, err
 	}
 	if item == nil || item.CartID != cart.ID {
-		return nil, errors.New("cart item not found")
+		return nil, services.ErrCartItemNotFound
 	}
 
 	// Delete item
-	if err := s.cartItemRepo.Delete(ctx.Context(), itemID); err != nil {
+	if err := s.cartItemRepo.Delete(ctx.UserContext(), itemID); err != nil {
 		return nil, err
 	}
 
@@ -314,23 +422,76 @@ func (s *cartService) RemoveItemFromCart(ctx *fiber.Ctx, userID string, itemID s
 	return s.GetCart(ctx, userID)
 }
 
-func (s *cartService) ClearCart(ctx *fiber.Ctx, userID string) error {
+// ReorderCartItems sets the display order of the cart's items. The request must list
+// each item in the cart exactly once.
+func (s *cartService) ReorderCartItems(ctx *fiber.Ctx, userID string, req *dto.ReorderItemsRequest) (*dto.CartResponse, error) {
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, services.ErrCartEmpty
+	}
+
+	items, err := s.cartItemRepo.GetByCartID(ctx.UserContext(), cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ItemIDs) != len(items) {
+		return nil, services.ErrInvalidReorder
+	}
+
+	remaining := make(map[string]bool, len(items))
+	for _, item := range items {
+		remaining[item.ID] = true
+	}
+	for _, itemID := range req.ItemIDs {
+		if !remaining[itemID] {
+			return nil, services.ErrInvalidReorder
+		}
+		delete(remaining, itemID)
+	}
+
+	if err := s.cartItemRepo.Reorder(ctx.UserContext(), cart.ID, req.ItemIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, userID)
+}
+
+// ClearCart removes every item and returns the now-empty cart. Like the other item changes it
+// fails with ErrCartLocked while a checkout holds the cart. When configured to, it also deletes
+// the cart row so cleared carts do not linger.
+func (s *cartService) ClearCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse, error) {
+	if err := s.ensureUnlocked(ctx.UserContext(), userID); err != nil {
+		return nil, err
+	}
+
 	// Get cart
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if cart == nil {
-		return nil // No cart to clear
+		return s.GetCart(ctx, userID) // No cart to clear
 	}
 
-	// Delete all cart items
-	return s.cartItemRepo.DeleteByCartID(ctx.Context(), cart.ID)
+	if s.config.DeleteClearedCarts {
+		// Deletes the cart's items along with it
+		err = s.cartRepo.Delete(ctx.UserContext(), cart.ID)
+	} else {
+		err = s.cartItemRepo.DeleteByCartID(ctx.UserContext(), cart.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, userID)
 }
 
 func (s *cartService) ValidateCart(ctx *fiber.Ctx, userID string) (*dto.CartValidationResponse, error) {
 	// Get cart
-	cart, err := s.cartRepo.GetByUserID(ctx.Context(), userID)
+	cart, err := s.cartRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -338,29 +499,69 @@ func (s *cartService) ValidateCart(ctx *fiber.Ctx, userID string) (*dto.CartVali
 	if cart == nil {
 		return &dto.CartValidationResponse{
 			Valid:      true,
+			Currency:   defaultCurrency,
 			TotalItems: 0,
 			TotalPrice: decimal.NewFromFloat(0),
 		}, nil
 	}
 
 	// Get cart items
-	items, err := s.cartItemRepo.GetByCartID(ctx.Context(), cart.ID)
+	items, err := s.cartItemRepo.GetByCartID(ctx.UserContext(), cart.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	cartCurrency := cartCurrency(cart, items)
+
 	response := &dto.CartValidationResponse{
 		Valid:         true,
+		Currency:      cartCurrency,
 		TotalItems:    0,
 		TotalPrice:    decimal.NewFromFloat(0),
 		InvalidItems:  []dto.InvalidItemResponse{},
 		UpdatedPrices: []dto.PriceUpdateResponse{},
 	}
 
+	// Fetch every product in one call. If that call fails we cannot tell a missing product
+	// from an unreachable product service, so those items are reported as unverified.
+	productIDs := make([]string, 0, len(items))
 	for _, item := range items {
-		// Fetch product details
-		product, err := s.productService.GetProduct(ctx.Context(), item.ProductID)
-		if err != nil {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products := make(map[string]*external.ProductResponse, len(productIDs))
+	var fetchErr error
+	if len(productIDs) > 0 {
+		var fetched []*external.ProductResponse
+		fetched, fetchErr = s.productService.GetProducts(ctx.UserContext(), productIDs)
+		for _, product := range fetched {
+			products[product.ID] = product
+		}
+	}
+
+	for _, item := range items {
+		if currency := itemCurrency(item, cartCurrency); currency != cartCurrency {
+			response.Valid = false
+			response.InvalidItems = append(response.InvalidItems, dto.InvalidItemResponse{
+				ProductID: item.ProductID,
+				Reason:    fmt.Sprintf("Priced in %s, cart is in %s", currency, cartCurrency),
+			})
+			continue
+		}
+
+		if fetchErr != nil {
+			response.Valid = false
+			response.ValidationIncomplete = true
+			response.InvalidItems = append(response.InvalidItems, dto.InvalidItemResponse{
+				ProductID: item.ProductID,
+				Reason:    "Could not verify product, please try again",
+				Retryable: true,
+			})
+			continue
+		}
+
+		product, found := products[item.ProductID]
+		if !found {
 			response.Valid = false
 			response.InvalidItems = append(response.InvalidItems, dto.InvalidItemResponse{
 				ProductID: item.ProductID,
@@ -400,7 +601,7 @@ func (s *cartService) ValidateCart(ctx *fiber.Ctx, userID string) (*dto.CartVali
 			})
 			// Update price in cart
 			item.PriceAtTime = currentPrice
-			s.cartItemRepo.Update(ctx.Context(), item)
+			s.cartItemRepo.Update(ctx.UserContext(), item)
 		}
 
 		response.TotalItems += item.Quantity
@@ -409,3 +610,56 @@ func (s *cartService) ValidateCart(ctx *fiber.Ctx, userID string) (*dto.CartVali
 
 	return response, nil
 }
+
+// storeCurrency returns the currency the store prices its products in
+func (s *cartService) storeCurrency(ctx context.Context, storeID string) (string, error) {
+	store, err := s.storeService.GetStore(ctx, storeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve store currency: %w", err)
+	}
+	if store.Settings.Currency == "" {
+		return defaultCurrency, nil
+	}
+	return store.Settings.Currency, nil
+}
+
+// ensureCartCurrency rejects items in a different currency from the rest of the cart.
+// An empty cart adopts the currency of the item being added.
+func (s *cartService) ensureCartCurrency(ctx context.Context, cart *entities.Cart, currency string) error {
+	if cart.Currency == currency {
+		return nil
+	}
+
+	items, err := s.cartItemRepo.GetByCartID(ctx, cart.ID)
+	if err != nil {
+		return err
+	}
+	if len(items) > 0 && cartCurrency(cart, items) != currency {
+		return &services.CurrencyMismatchError{ProductCurrency: currency, CartCurrency: cartCurrency(cart, items)}
+	}
+
+	cart.Currency = currency
+	return s.cartRepo.Update(ctx, cart)
+}
+
+// cartCurrency returns the cart's currency, falling back to its first item's for carts
+// created before currencies were recorded
+func cartCurrency(cart *entities.Cart, items []*entities.CartItem) string {
+	if cart.Currency != "" {
+		return cart.Currency
+	}
+	for _, item := range items {
+		if item.Currency != "" {
+			return item.Currency
+		}
+	}
+	return defaultCurrency
+}
+
+// itemCurrency returns the item's currency, treating items without one as priced in the cart currency
+func itemCurrency(item *entities.CartItem, cartCurrency string) string {
+	if item.Currency == "" {
+		return cartCurrency
+	}
+	return item.Currency
+}
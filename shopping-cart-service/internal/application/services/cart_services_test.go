@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shopspring/decimal"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/external"
+)
+
+// addCartItem puts quantity of productID into the user's cart directly through the repositories
+func addCartItem(t *testing.T, carts *fakeCartRepo, items *fakeCartItemRepo, userID, productID string, quantity int) *entities.CartItem {
+	t.Helper()
+
+	cart, err := carts.GetOrCreateByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("create cart: %v", err)
+	}
+	item := &entities.CartItem{CartID: cart.ID, ProductID: productID, Quantity: quantity, PriceAtTime: decimal.NewFromInt(10)}
+	if err := items.Create(context.Background(), item); err != nil {
+		t.Fatalf("add %s: %v", productID, err)
+	}
+	return item
+}
+
+func TestAddItemRejectsNonPositiveQuantity(t *testing.T) {
+	for _, quantity := range []int{0, -1} {
+		service, carts, _ := newTestCartService()
+
+		var err error
+		withCtx(t, func(c *fiber.Ctx) {
+			_, err = service.AddItemToCart(c, "user", &dto.AddItemRequest{ProductID: "product", Quantity: quantity})
+		})
+
+		if !errors.Is(err, services.ErrInvalidQuantity) {
+			t.Errorf("quantity %d: err = %v, want ErrInvalidQuantity", quantity, err)
+		}
+		if cart, _ := carts.GetByUserID(context.Background(), "user"); cart != nil {
+			t.Errorf("quantity %d: a cart was created for a rejected add", quantity)
+		}
+	}
+}
+
+func TestUpdateItemRejectsNegativeOrMissingQuantity(t *testing.T) {
+	negative := -1
+	for _, quantity := range []*int{nil, &negative} {
+		service, carts, items := newTestCartService()
+		item := addCartItem(t, carts, items, "user", "product", 2)
+
+		var err error
+		withCtx(t, func(c *fiber.Ctx) {
+			_, err = service.UpdateCartItem(c, "user", item.ID, &dto.UpdateItemRequest{Quantity: quantity})
+		})
+
+		if !errors.Is(err, services.ErrInvalidQuantity) {
+			t.Errorf("quantity %v: err = %v, want ErrInvalidQuantity", quantity, err)
+		}
+		if stored, _ := items.GetByID(context.Background(), item.ID); stored == nil || stored.Quantity != 2 {
+			t.Errorf("quantity %v: item = %+v, want it unchanged", quantity, stored)
+		}
+	}
+}
+
+func TestUpdateItemToZeroRemovesIt(t *testing.T) {
+	service, carts, items := newTestCartService()
+	item := addCartItem(t, carts, items, "user", "product", 2)
+	zero := 0
+
+	var (
+		response *dto.CartResponse
+		err      error
+	)
+	withCtx(t, func(c *fiber.Ctx) {
+		response, err = service.UpdateCartItem(c, "user", item.ID, &dto.UpdateItemRequest{Quantity: &zero})
+	})
+
+	if err != nil {
+		t.Fatalf("UpdateCartItem: %v", err)
+	}
+	if stored, _ := items.GetByID(context.Background(), item.ID); stored != nil {
+		t.Errorf("item still stored: %+v", stored)
+	}
+	if len(response.Items) != 0 || response.TotalItems != 0 {
+		t.Errorf("response = %+v, want an empty cart", response)
+	}
+}
+
+func TestUpdateItemOfAnotherCartIsNotFound(t *testing.T) {
+	service, carts, items := newTestCartService()
+	addCartItem(t, carts, items, "user", "product", 1)
+	other := addCartItem(t, carts, items, "other", "product", 1)
+	zero := 0
+
+	var err error
+	withCtx(t, func(c *fiber.Ctx) {
+		_, err = service.UpdateCartItem(c, "user", other.ID, &dto.UpdateItemRequest{Quantity: &zero})
+	})
+
+	if !errors.Is(err, services.ErrCartItemNotFound) {
+		t.Fatalf("err = %v, want ErrCartItemNotFound", err)
+	}
+	if stored, _ := items.GetByID(context.Background(), other.ID); stored == nil {
+		t.Error("another user's item was removed")
+	}
+}
+
+func TestChangingItemWithoutCartIsNotFound(t *testing.T) {
+	service, carts, _ := newTestCartService()
+	one := 1
+
+	var updateErr, removeErr error
+	withCtx(t, func(c *fiber.Ctx) {
+		_, updateErr = service.UpdateCartItem(c, "user", "item", &dto.UpdateItemRequest{Quantity: &one})
+		_, removeErr = service.RemoveItemFromCart(c, "user", "item")
+	})
+
+	if !errors.Is(updateErr, services.ErrCartItemNotFound) {
+		t.Errorf("update err = %v, want ErrCartItemNotFound", updateErr)
+	}
+	if !errors.Is(removeErr, services.ErrCartItemNotFound) {
+		t.Errorf("remove err = %v, want ErrCartItemNotFound", removeErr)
+	}
+	if cart, _ := carts.GetByUserID(context.Background(), "user"); cart != nil {
+		t.Error("a cart was created by changing an item")
+	}
+}
+
+// addConcurrently adds one of productID to the user's cart from n requests at once and
+// returns their errors
+func addConcurrently(t *testing.T, service *cartService, userID, productID string, n int) []error {
+	t.Helper()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			withCtx(t, func(c *fiber.Ctx) {
+				_, errs[i] = service.AddItemToCart(c, userID, &dto.AddItemRequest{ProductID: productID, Quantity: 1})
+			})
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestConcurrentAddsOfSameProductShareOneItem(t *testing.T) {
+	service, carts, items := newTestCartService()
+	serveCatalog(t, service, external.ProductResponse{ID: "product", Name: "Mug", Price: 10, Stock: 100, StoreID: "store", IsActive: true})
+
+	const adds = 20
+	for i, err := range addConcurrently(t, service, "user", "product", adds) {
+		if err != nil {
+			t.Errorf("add %d: %v", i, err)
+		}
+	}
+
+	cart, _ := carts.GetByUserID(context.Background(), "user")
+	if cart == nil {
+		t.Fatal("no cart was created")
+	}
+	stored, _ := items.GetByCartID(context.Background(), cart.ID)
+	if len(stored) != 1 {
+		t.Fatalf("cart has %d items, want the adds merged into 1", len(stored))
+	}
+	if stored[0].Quantity != adds {
+		t.Errorf("quantity = %d, want %d", stored[0].Quantity, adds)
+	}
+}
+
+func TestConcurrentAddsDoNotExceedStock(t *testing.T) {
+	service, carts, items := newTestCartService()
+	const stock = 5
+	serveCatalog(t, service, external.ProductResponse{ID: "product", Name: "Mug", Price: 10, Stock: stock, StoreID: "store", IsActive: true})
+
+	succeeded := 0
+	for i, err := range addConcurrently(t, service, "user", "product", 2*stock) {
+		var stockErr *services.InsufficientStockError
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.As(err, &stockErr):
+			t.Errorf("add %d: err = %v, want insufficient stock", i, err)
+		}
+	}
+	if succeeded != stock {
+		t.Errorf("%d adds succeeded, want %d", succeeded, stock)
+	}
+
+	cart, _ := carts.GetByUserID(context.Background(), "user")
+	stored, _ := items.GetByCartID(context.Background(), cart.ID)
+	if len(stored) != 1 || stored[0].Quantity != stock {
+		t.Errorf("items = %s, want one item of quantity %d", describeItems(stored), stock)
+	}
+}
+
+func TestConcurrentUpdatesLeaveOneOfTheRequestedQuantities(t *testing.T) {
+	service, carts, items := newTestCartService()
+	serveCatalog(t, service, external.ProductResponse{ID: "product", Name: "Mug", Price: 10, Stock: 100, StoreID: "store", IsActive: true})
+	item := addCartItem(t, carts, items, "user", "product", 1)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quantity := i + 1
+			withCtx(t, func(c *fiber.Ctx) {
+				_, errs[i] = service.UpdateCartItem(c, "user", item.ID, &dto.UpdateItemRequest{Quantity: &quantity})
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("update %d: %v", i, err)
+		}
+	}
+	stored, _ := items.GetByCartID(context.Background(), item.CartID)
+	if len(stored) != 1 || stored[0].Quantity < 1 || stored[0].Quantity > len(errs) {
+		t.Errorf("items = %s, want the one item at a requested quantity", describeItems(stored))
+	}
+}
+
+func describeItems(items []*entities.CartItem) string {
+	description := ""
+	for _, item := range items {
+		description += fmt.Sprintf("[%s x%d]", item.ProductID, item.Quantity)
+	}
+	return description
+}
+
+// newLockedCartService returns a cart service whose user has one item and whose checkout
+// locks live in memory, with the cart locked for a minute
+func newLockedCartService(t *testing.T) (*cartService, *memoryCartLocks, *fakeCartItemRepo, *entities.CartItem) {
+	t.Helper()
+
+	service, carts, items := newTestCartService()
+	locks := newMemoryCartLocks()
+	service.cartLocks = locks
+	service.config.CheckoutLockTTL = time.Minute
+	item := addCartItem(t, carts, items, "user", "product", 2)
+
+	var err error
+	withCtx(t, func(c *fiber.Ctx) {
+		_, err = service.LockCart(c, "user")
+	})
+	if err != nil {
+		t.Fatalf("LockCart: %v", err)
+	}
+	return service, locks, items, item
+}
+
+func TestCartMutationsRejectedWhileLocked(t *testing.T) {
+	three := 3
+	mutations := map[string]func(s *cartService, c *fiber.Ctx, itemID string) error{
+		"add": func(s *cartService, c *fiber.Ctx, itemID string) error {
+			_, err := s.AddItemToCart(c, "user", &dto.AddItemRequest{ProductID: "other", Quantity: 1})
+			return err
+		},
+		"update": func(s *cartService, c *fiber.Ctx, itemID string) error {
+			_, err := s.UpdateCartItem(c, "user", itemID, &dto.UpdateItemRequest{Quantity: &three})
+			return err
+		},
+		"remove": func(s *cartService, c *fiber.Ctx, itemID string) error {
+			_, err := s.RemoveItemFromCart(c, "user", itemID)
+			return err
+		},
+		"clear": func(s *cartService, c *fiber.Ctx, itemID string) error {
+			_, err := s.ClearCart(c, "user")
+			return err
+		},
+	}
+
+	for name, mutate := range mutations {
+		service, locks, items, item := newLockedCartService(t)
+
+		var err error
+		withCtx(t, func(c *fiber.Ctx) {
+			err = mutate(service, c, item.ID)
+		})
+
+		if !errors.Is(err, services.ErrCartLocked) {
+			t.Errorf("%s: err = %v, want ErrCartLocked", name, err)
+		}
+		if stored, _ := items.GetByID(context.Background(), item.ID); stored == nil || stored.Quantity != 2 {
+			t.Errorf("%s: item = %+v, want it unchanged", name, stored)
+		}
+		if until, _ := locks.LockedUntil(context.Background(), "user"); until.IsZero() {
+			t.Errorf("%s: the checkout lock was released", name)
+		}
+	}
+}
+
+func TestCartLockExpires(t *testing.T) {
+	service, locks, items, item := newLockedCartService(t)
+	locks.advance(time.Minute + time.Second)
+
+	var err error
+	withCtx(t, func(c *fiber.Ctx) {
+		_, err = service.RemoveItemFromCart(c, "user", item.ID)
+	})
+
+	if err != nil {
+		t.Fatalf("remove after the lock expired: %v", err)
+	}
+	if stored, _ := items.GetByID(context.Background(), item.ID); stored != nil {
+		t.Errorf("item still stored: %+v", stored)
+	}
+}
+
+func TestUnreadableCartLockLetsChangesThrough(t *testing.T) {
+	service, locks, items, _ := newLockedCartService(t)
+	locks.readErr = errors.New("redis: connection refused")
+
+	var (
+		response *dto.CartResponse
+		err      error
+	)
+	withCtx(t, func(c *fiber.Ctx) {
+		response, err = service.ClearCart(c, "user")
+	})
+
+	if err != nil {
+		t.Fatalf("ClearCart: %v", err)
+	}
+	if len(response.Items) != 0 {
+		t.Errorf("response = %+v, want an empty cart", response)
+	}
+	if remaining, _ := items.GetByCartID(context.Background(), response.ID); len(remaining) != 0 {
+		t.Errorf("%d items left after clearing", len(remaining))
+	}
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/external"
+)
+
+// fakeCartRepo keeps carts in memory, one per user like the real unique index
+type fakeCartRepo struct {
+	mu     sync.Mutex
+	carts  map[string]entities.Cart
+	nextID int
+}
+
+func newFakeCartRepo() *fakeCartRepo {
+	return &fakeCartRepo{carts: make(map[string]entities.Cart)}
+}
+
+func (r *fakeCartRepo) create(cart *entities.Cart) error {
+	if _, exists := r.carts[cart.UserID]; exists {
+		return errors.New("duplicate key value violates unique constraint \"idx_carts_user\"")
+	}
+	r.nextID++
+	cart.ID = "cart-" + strconv.Itoa(r.nextID)
+	r.carts[cart.UserID] = *cart
+	return nil
+}
+
+func (r *fakeCartRepo) Create(ctx context.Context, cart *entities.Cart) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.create(cart)
+}
+
+func (r *fakeCartRepo) GetByID(ctx context.Context, id string) (*entities.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, cart := range r.carts {
+		if cart.ID == id {
+			return &cart, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeCartRepo) GetByUserID(ctx context.Context, userID string) (*entities.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cart, ok := r.carts[userID]; ok {
+		return &cart, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeCartRepo) GetOrCreateByUserID(ctx context.Context, userID string) (*entities.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cart, ok := r.carts[userID]; ok {
+		return &cart, nil
+	}
+	cart := &entities.Cart{UserID: userID}
+	if err := r.create(cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+func (r *fakeCartRepo) Update(ctx context.Context, cart *entities.Cart) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.carts[cart.UserID] = *cart
+	return nil
+}
+
+func (r *fakeCartRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for userID, cart := range r.carts {
+		if cart.ID == id {
+			delete(r.carts, userID)
+		}
+	}
+	return nil
+}
+
+func (r *fakeCartRepo) DeleteByUserID(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.carts, userID)
+	return nil
+}
+
+// fakeCartItemRepo keeps items in memory. Like the real table it allows one item per cart
+// and product, so an add that raced past a lookup fails instead of duplicating the row.
+type fakeCartItemRepo struct {
+	mu     sync.Mutex
+	items  map[string]entities.CartItem
+	nextID int
+}
+
+func newFakeCartItemRepo() *fakeCartItemRepo {
+	return &fakeCartItemRepo{items: make(map[string]entities.CartItem)}
+}
+
+func (r *fakeCartItemRepo) find(cartID, productID string) *entities.CartItem {
+	for _, item := range r.items {
+		if item.CartID == cartID && item.ProductID == productID {
+			return &item
+		}
+	}
+	return nil
+}
+
+func (r *fakeCartItemRepo) create(item *entities.CartItem) error {
+	if r.find(item.CartID, item.ProductID) != nil {
+		return errors.New("duplicate key value violates unique constraint \"idx_cart_items_cart_product\"")
+	}
+	r.nextID++
+	item.ID = "item-" + strconv.Itoa(r.nextID)
+	item.SortOrder = r.nextID
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *fakeCartItemRepo) Create(ctx context.Context, item *entities.CartItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.create(item)
+}
+
+func (r *fakeCartItemRepo) AddQuantity(ctx context.Context, item *entities.CartItem, maxQuantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := r.find(item.CartID, item.ProductID)
+	if existing == nil {
+		if item.Quantity > maxQuantity {
+			return repositories.ErrQuantityExceedsLimit
+		}
+		return r.create(item)
+	}
+	if existing.Quantity+item.Quantity > maxQuantity {
+		return repositories.ErrQuantityExceedsLimit
+	}
+	existing.Quantity += item.Quantity
+	existing.PriceAtTime = item.PriceAtTime
+	existing.Currency = item.Currency
+	existing.SnapshotProduct(item.ProductName, item.ProductSKU)
+	r.items[existing.ID] = *existing
+	*item = *existing
+	return nil
+}
+
+func (r *fakeCartItemRepo) GetByID(ctx context.Context, id string) (*entities.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, ok := r.items[id]; ok {
+		return &item, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeCartItemRepo) GetByCartID(ctx context.Context, cartID string) ([]*entities.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var items []*entities.CartItem
+	for _, item := range r.items {
+		if item.CartID == cartID {
+			items = append(items, &item)
+		}
+	}
+	slices.SortFunc(items, func(a, b *entities.CartItem) int { return a.SortOrder - b.SortOrder })
+	return items, nil
+}
+
+func (r *fakeCartItemRepo) GetByCartAndProduct(ctx context.Context, cartID, productID string) (*entities.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.find(cartID, productID), nil
+}
+
+func (r *fakeCartItemRepo) Update(ctx context.Context, item *entities.CartItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[item.ID]; !ok {
+		return errors.New("cart item not found")
+	}
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *fakeCartItemRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items, id)
+	return nil
+}
+
+func (r *fakeCartItemRepo) DeleteByCartID(ctx context.Context, cartID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, item := range r.items {
+		if item.CartID == cartID {
+			delete(r.items, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeCartItemRepo) DeleteByCartAndProduct(ctx context.Context, cartID, productID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item := r.find(cartID, productID); item != nil {
+		delete(r.items, item.ID)
+	}
+	return nil
+}
+
+func (r *fakeCartItemRepo) Reorder(ctx context.Context, cartID string, itemIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for position, id := range itemIDs {
+		item := r.items[id]
+		item.SortOrder = position
+		r.items[id] = item
+	}
+	return nil
+}
+
+// unlockedCarts reports every cart as unlocked
+type unlockedCarts struct{}
+
+func (unlockedCarts) Lock(ctx context.Context, userID string, ttl time.Duration) error { return nil }
+func (unlockedCarts) Unlock(ctx context.Context, userID string) error                  { return nil }
+func (unlockedCarts) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// memoryCartLocks keeps checkout locks in memory against a clock the test moves by hand.
+// When readErr is set LockedUntil fails with it.
+type memoryCartLocks struct {
+	mu      sync.Mutex
+	now     time.Time
+	until   map[string]time.Time
+	readErr error
+}
+
+func newMemoryCartLocks() *memoryCartLocks {
+	return &memoryCartLocks{now: time.Now(), until: make(map[string]time.Time)}
+}
+
+func (l *memoryCartLocks) Lock(ctx context.Context, userID string, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.until[userID] = l.now.Add(ttl)
+	return nil
+}
+
+func (l *memoryCartLocks) Unlock(ctx context.Context, userID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.until, userID)
+	return nil
+}
+
+func (l *memoryCartLocks) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.readErr != nil {
+		return time.Time{}, l.readErr
+	}
+	if until := l.until[userID]; until.After(l.now) {
+		return until, nil
+	}
+	return time.Time{}, nil
+}
+
+// advance moves the clock forward by d
+func (l *memoryCartLocks) advance(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.now = l.now.Add(d)
+}
+
+// newTestCartService returns a cart service over in-memory repositories. It has no product
+// or store clients until serveCatalog is called, so only paths that never reach them can
+// be exercised before that.
+func newTestCartService() (*cartService, *fakeCartRepo, *fakeCartItemRepo) {
+	carts := newFakeCartRepo()
+	items := newFakeCartItemRepo()
+	service := &cartService{
+		cartRepo:     carts,
+		cartItemRepo: items,
+		cartLocks:    unlockedCarts{},
+		config:       &config.Config{},
+	}
+	return service, carts, items
+}
+
+// serveCatalog points the service at fake product and store services that know only product,
+// which belongs to a USD store
+func serveCatalog(t *testing.T, service *cartService, product external.ProductResponse) {
+	t.Helper()
+
+	respond := func(w http.ResponseWriter, data any) {
+		raw, _ := json.Marshal(data)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(external.ServiceResponse{Success: true, Data: raw})
+	}
+
+	products := http.NewServeMux()
+	products.HandleFunc("GET /api/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != product.ID {
+			http.NotFound(w, r)
+			return
+		}
+		respond(w, product)
+	})
+	products.HandleFunc("POST /api/products/ids", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, []external.ProductResponse{product})
+	})
+	productServer := httptest.NewServer(products)
+	t.Cleanup(productServer.Close)
+
+	stores := http.NewServeMux()
+	stores.HandleFunc("GET /api/internal/stores/{id}", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, external.StoreResponse{ID: r.PathValue("id"), IsActive: true, Settings: external.StoreSettings{Currency: "USD"}})
+	})
+	storeServer := httptest.NewServer(stores)
+	t.Cleanup(storeServer.Close)
+
+	breaker := external.NewCircuitBreaker(5, time.Minute)
+	service.productService = external.NewProductServiceClient(productServer.URL, 5*time.Second, breaker, "", prometheus.NewRegistry())
+	service.storeService = external.NewStoreServiceClient(storeServer.URL)
+}
+
+// withCtx runs fn inside a request so it gets a *fiber.Ctx like the handlers pass the service
+func withCtx(t *testing.T, fn func(c *fiber.Ctx)) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		fn(c)
+		return nil
+	})
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/shopspring/decimal"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/external"
+)
+
+// ShippingEstimator estimates shipping for one store's share of the cart
+type ShippingEstimator interface {
+	Estimate(settings external.StoreSettings, subtotal decimal.Decimal) decimal.Decimal
+}
+
+// ShippingEstimatorFunc adapts a plain function to ShippingEstimator
+type ShippingEstimatorFunc func(settings external.StoreSettings, subtotal decimal.Decimal) decimal.Decimal
+
+func (f ShippingEstimatorFunc) Estimate(settings external.StoreSettings, subtotal decimal.Decimal) decimal.Decimal {
+	return f(settings, subtotal)
+}
+
+// shippingEstimators maps a store's shipping_method setting to its estimator. Stores
+// with an unknown or empty method are estimated at zero shipping.
+var shippingEstimators = map[string]ShippingEstimator{
+	"flat_rate": ShippingEstimatorFunc(func(settings external.StoreSettings, subtotal decimal.Decimal) decimal.Decimal {
+		return decimal.NewFromFloat(settings.ShippingFlatRate)
+	}),
+	"free_over_threshold": ShippingEstimatorFunc(func(settings external.StoreSettings, subtotal decimal.Decimal) decimal.Decimal {
+		if subtotal.GreaterThanOrEqual(decimal.NewFromFloat(settings.FreeShippingThreshold)) {
+			return decimal.Zero
+		}
+		return decimal.NewFromFloat(settings.ShippingFlatRate)
+	}),
+}
+
+// RegisterShippingEstimator makes a shipping method available to stores that select it
+// in their settings, replacing any estimator already registered under that name
+func RegisterShippingEstimator(method string, estimator ShippingEstimator) {
+	shippingEstimators[method] = estimator
+}
+
+// applyStoreEstimates fills in each store group's shipping and tax estimates and adds
+// those in the cart currency to the cart's grand total
+func (s *cartService) applyStoreEstimates(ctx context.Context, cart *dto.CartResponse) {
+	cart.ShippingTotal = decimal.Zero
+	cart.TaxTotal = decimal.Zero
+
+	for i := range cart.Stores {
+		group := &cart.Stores[i]
+
+		store, err := s.storeService.GetStore(ctx, group.StoreID)
+		if err != nil {
+			log.Printf("Failed to fetch settings for store %s: %v", group.StoreID, err)
+			continue
+		}
+
+		shipping := decimal.Zero
+		if estimator, ok := shippingEstimators[store.Settings.ShippingMethod]; ok {
+			shipping = estimator.Estimate(store.Settings, group.StoreTotal).Round(2)
+		}
+		tax := group.StoreTotal.Mul(decimal.NewFromFloat(store.Settings.TaxRate)).Round(2)
+
+		group.ShippingEstimate = &shipping
+		group.TaxEstimate = &tax
+
+		if group.Currency == cart.Currency {
+			cart.ShippingTotal = cart.ShippingTotal.Add(shipping)
+			cart.TaxTotal = cart.TaxTotal.Add(tax)
+		}
+	}
+
+	cart.GrandTotal = cart.TotalPrice.Add(cart.ShippingTotal).Add(cart.TaxTotal)
+}
@@ -1,17 +1,60 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
-	Database          DatabaseConfig
-	Redis             RedisConfig
-	AppEnv            string
-	AppPort           string
-	ProductServiceURL string
-	UserServiceURL    string
+	Database           DatabaseConfig
+	Redis              RedisConfig
+	AppEnv             string
+	AppPort            string
+	LogLevel           string
+	LogBodySampleRate  int
+	Maintenance        MaintenanceConfig
+	RequestTimeout     time.Duration
+	CORSOrigins        string
+	AdminToken         string
+	ReloadFile         string
+	ProductServiceURL  string
+	UserServiceURL     string
+	StoreServiceURL    string
+	DeleteClearedCarts bool
+	// CapQuantityToStock makes adding more than is in stock add what is available with a
+	// warning instead of failing; requests can override it with ?cap_to_stock=
+	CapQuantityToStock bool
+
+	// ProductServiceTimeout bounds each call to the product service
+	ProductServiceTimeout time.Duration
+	ProductServiceBreaker BreakerConfig
+	// InternalSigningSecret signs requests to other services; it must match the
+	// product service's INTERNAL_SIGNING_SECRET
+	InternalSigningSecret string
+	// ProductServiceHealthCheck pings the product service at startup and refuses to
+	// start if it is unreachable
+	ProductServiceHealthCheck bool
+	// CheckoutLockTTL is how long a checkout lock keeps a cart's items from changing
+	CheckoutLockTTL time.Duration
+}
+
+// MaintenanceConfig controls maintenance mode. Enabled forces it on; otherwise it
+// can be toggled at runtime through the admin endpoint.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
+}
+
+// BreakerConfig controls a circuit breaker around a downstream service. It opens after
+// FailureThreshold consecutive failures and probes again after Cooldown; a threshold
+// of zero disables it.
+type BreakerConfig struct {
+	FailureThreshold int
+	Cooldown         time.Duration
 }
 
 type DatabaseConfig struct {
@@ -34,6 +77,17 @@ func Load() *Config {
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	logBodySampleRate, _ := strconv.Atoi(getEnv("LOG_BODY_SAMPLE_RATE", "1"))
+	deleteClearedCarts, _ := strconv.ParseBool(getEnv("CART_DELETE_CLEARED", "false"))
+	capQuantityToStock, _ := strconv.ParseBool(getEnv("CART_CAP_QUANTITY_TO_STOCK", "false"))
+	maintenanceEnabled, _ := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	maintenanceRetryAfter, _ := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	requestTimeout, _ := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
+	productServiceTimeout, _ := time.ParseDuration(getEnv("PRODUCT_SERVICE_TIMEOUT", "10s"))
+	productServiceHealthCheck, _ := strconv.ParseBool(getEnv("PRODUCT_SERVICE_HEALTH_CHECK", "false"))
+	productBreakerThreshold, _ := strconv.Atoi(getEnv("PRODUCT_SERVICE_BREAKER_THRESHOLD", "5"))
+	productBreakerCooldown, _ := time.ParseDuration(getEnv("PRODUCT_SERVICE_BREAKER_COOLDOWN", "30s"))
+	checkoutLockTTL, _ := time.ParseDuration(getEnv("CART_CHECKOUT_LOCK_TTL", "15m"))
 
 	return &Config{
 		Database: DatabaseConfig{
@@ -52,9 +106,67 @@ func Load() *Config {
 		},
 		AppEnv:            getEnv("APP_ENV", "development"),
 		AppPort:           getEnv("APP_PORT", "3005"),
-		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://product-service:3004"),
-		UserServiceURL:    getEnv("USER_SERVICE_URL", "http://user-service:3003"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogBodySampleRate: logBodySampleRate,
+		Maintenance: MaintenanceConfig{
+			Enabled:    maintenanceEnabled,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		RequestTimeout:     requestTimeout,
+		CORSOrigins:        getEnv("CORS_ALLOW_ORIGINS", "*"),
+		AdminToken:         getEnv("ADMIN_TOKEN", ""),
+		ReloadFile:         getEnv("CONFIG_RELOAD_FILE", ""),
+		ProductServiceURL:  getEnv("PRODUCT_SERVICE_URL", "http://product-service:3004"),
+		UserServiceURL:     getEnv("USER_SERVICE_URL", "http://user-service:3003"),
+		StoreServiceURL:    getEnv("STORE_SERVICE_URL", "http://store-service:3006"),
+		DeleteClearedCarts: deleteClearedCarts,
+		CapQuantityToStock: capQuantityToStock,
+
+		ProductServiceTimeout:     productServiceTimeout,
+		ProductServiceHealthCheck: productServiceHealthCheck,
+		ProductServiceBreaker: BreakerConfig{
+			FailureThreshold: productBreakerThreshold,
+			Cooldown:         productBreakerCooldown,
+		},
+		InternalSigningSecret: getEnv("INTERNAL_SIGNING_SECRET", ""),
+		CheckoutLockTTL:       checkoutLockTTL,
+	}
+}
+
+// Validate reports settings that would otherwise only fail on the first request, such
+// as a malformed product service URL or a non-positive timeout.
+func (c *Config) Validate() error {
+	if err := validateServiceURL("PRODUCT_SERVICE_URL", c.ProductServiceURL); err != nil {
+		return err
+	}
+	if c.ProductServiceTimeout <= 0 {
+		return fmt.Errorf("PRODUCT_SERVICE_TIMEOUT must be a positive duration such as 10s, got %s", c.ProductServiceTimeout)
+	}
+	if c.CheckoutLockTTL <= 0 {
+		return fmt.Errorf("CART_CHECKOUT_LOCK_TTL must be a positive duration such as 15m, got %s", c.CheckoutLockTTL)
+	}
+	if c.InternalSigningSecret == "" {
+		return errors.New("INTERNAL_SIGNING_SECRET is required")
+	}
+	return nil
+}
+
+// validateServiceURL checks that raw is an absolute http(s) URL with a host
+func validateServiceURL(name, raw string) error {
+	if raw == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid URL: %w", name, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%s must use http or https, got %q", name, raw)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("%s must include a host, got %q", name, raw)
 	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateRequiresInternalSigningSecret(t *testing.T) {
+	cfg := &Config{
+		ProductServiceURL:     "http://product-service:3004",
+		ProductServiceTimeout: 10 * time.Second,
+		CheckoutLockTTL:       15 * time.Minute,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate accepted a config without INTERNAL_SIGNING_SECRET")
+	}
+
+	cfg.InternalSigningSecret = "shared"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
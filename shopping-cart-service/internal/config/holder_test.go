@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeReloadFile writes content to an env file and returns a holder configured to reload from it
+func writeReloadFile(t *testing.T, content string) *Holder {
+	t.Helper()
+
+	// Cleared so Load starts from the defaults
+	for _, key := range []string{"LOG_LEVEL", "CORS_ALLOW_ORIGINS"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("ADMIN_TOKEN", "startup-token")
+
+	path := filepath.Join(t.TempDir(), "reload.env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write reload file: %v", err)
+	}
+	cfg := Load()
+	cfg.ReloadFile = path
+	return NewHolder(cfg)
+}
+
+func TestReloadAppliesReloadFile(t *testing.T) {
+	holder := writeReloadFile(t, `
+# Comments and blank lines are skipped
+LOG_LEVEL=debug
+export CORS_ALLOW_ORIGINS="https://shop.example.com"
+ADMIN_TOKEN=changed
+`)
+
+	reloaded, err := holder.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if reloaded.LogLevel != "debug" {
+		t.Errorf("log level = %q, want debug", reloaded.LogLevel)
+	}
+	if reloaded.CORSOrigins != "https://shop.example.com" {
+		t.Errorf("CORS origins = %q, want https://shop.example.com", reloaded.CORSOrigins)
+	}
+	if reloaded.AdminToken != "startup-token" {
+		t.Errorf("admin token = %q, want the startup value kept", reloaded.AdminToken)
+	}
+	if holder.Get() != reloaded {
+		t.Error("Get does not return the reloaded configuration")
+	}
+}
+
+func TestReloadWithoutFile(t *testing.T) {
+	holder := NewHolder(Load())
+
+	if _, err := holder.Reload(); !errors.Is(err, ErrNoReloadFile) {
+		t.Fatalf("err = %v, want ErrNoReloadFile", err)
+	}
+}
+
+func TestReloadRejectsMalformedFile(t *testing.T) {
+	holder := writeReloadFile(t, "LOG_LEVEL=debug\nnot a setting\n")
+	before := holder.Get()
+
+	if _, err := holder.Reload(); err == nil {
+		t.Fatal("malformed reload file accepted")
+	}
+	if holder.Get() != before {
+		t.Error("configuration changed by a failed reload")
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		t.Errorf("LOG_LEVEL = %q, want the malformed file not applied", level)
+	}
+}
+
+func TestReloadIgnoresSettingsThatAreNotReloadable(t *testing.T) {
+	holder := writeReloadFile(t, "LOG_LEVEL=warn\nDB_PASSWORD=from-file\nADMIN_TOKEN=from-file\n")
+	t.Setenv("DB_PASSWORD", "startup-password")
+	startup := *holder.Get()
+
+	reloaded, err := holder.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if reloaded.LogLevel != "warn" {
+		t.Errorf("log level = %q, want warn", reloaded.LogLevel)
+	}
+	if reloaded.Database != startup.Database || reloaded.AdminToken != startup.AdminToken {
+		t.Error("reload changed connection settings or secrets")
+	}
+	for key, want := range map[string]string{"LOG_LEVEL": "", "DB_PASSWORD": "startup-password", "ADMIN_TOKEN": "startup-token"} {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q in the environment, want %q", key, got, want)
+		}
+	}
+}
+
+func TestReloadRejectsInvalidValue(t *testing.T) {
+	holder := writeReloadFile(t, "LOG_LEVEL=debug\nMAINTENANCE_RETRY_AFTER=soon\n")
+	before := holder.Get()
+
+	if _, err := holder.Reload(); err == nil {
+		t.Fatal("invalid MAINTENANCE_RETRY_AFTER accepted")
+	}
+	if holder.Get() != before || before.LogLevel == "debug" {
+		t.Error("configuration changed by a failed reload")
+	}
+}
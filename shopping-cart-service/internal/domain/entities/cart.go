@@ -10,7 +10,8 @@ import (
 
 type Cart struct {
 	ID        string         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID    string         `json:"user_id" gorm:"type:uuid;not null;index"`
+	UserID    string         `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_carts_user,where:deleted_at IS NULL"`
+	Currency  string         `json:"currency" gorm:"type:varchar(3)"`
 	Items     []CartItem     `json:"items,omitempty" gorm:"foreignKey:CartID"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -10,11 +10,15 @@ import (
 
 type CartItem struct {
 	ID          string          `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	CartID      string          `json:"cart_id" gorm:"type:uuid;not null;index"`
+	CartID      string          `json:"cart_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_cart_items_cart_product,where:deleted_at IS NULL"`
 	Cart        Cart            `json:"-" gorm:"foreignKey:CartID"`
-	ProductID   string          `json:"product_id" gorm:"type:uuid;not null;index"`
+	ProductID   string          `json:"product_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_cart_items_cart_product,where:deleted_at IS NULL"`
+	ProductName string          `json:"product_name" gorm:"type:varchar(255)"`
+	ProductSKU  string          `json:"product_sku" gorm:"type:varchar(100)"`
 	Quantity    int             `json:"quantity" gorm:"not null;check:quantity > 0"`
 	PriceAtTime decimal.Decimal `json:"price_at_time" gorm:"type:decimal(10,2);not null"`
+	Currency    string          `json:"currency" gorm:"type:varchar(3)"`
+	SortOrder   int             `json:"sort_order" gorm:"not null;default:0"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt  `json:"-" gorm:"index"`
@@ -35,3 +39,11 @@ func (ci *CartItem) BeforeCreate(tx *gorm.DB) error {
 func (ci *CartItem) GetSubtotal() decimal.Decimal {
 	return ci.PriceAtTime.Mul(decimal.NewFromInt(int64(ci.Quantity)))
 }
+
+// SnapshotProduct records the product name and SKU as they were when the item
+// was added or updated, so the cart can still show them if the product is
+// later renamed or deleted
+func (ci *CartItem) SnapshotProduct(name, sku string) {
+	ci.ProductName = name
+	ci.ProductSKU = sku
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// CartLockRepository holds the checkout locks of users' carts. Locks expire on their own
+// so a checkout that never finishes cannot leave a cart stuck.
+type CartLockRepository interface {
+	// Lock locks the user's cart for ttl, extending any lock already held
+	Lock(ctx context.Context, userID string, ttl time.Duration) error
+	Unlock(ctx context.Context, userID string) error
+	// LockedUntil returns when the user's cart lock expires, or the zero time when it is not locked
+	LockedUntil(ctx context.Context, userID string) (time.Time, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/entities"
 )
@@ -10,18 +11,29 @@ type CartRepository interface {
 	Create(ctx context.Context, cart *entities.Cart) error
 	GetByID(ctx context.Context, id string) (*entities.Cart, error)
 	GetByUserID(ctx context.Context, userID string) (*entities.Cart, error)
+	// GetOrCreateByUserID returns the user's cart, atomically creating an empty one if they have none
+	GetOrCreateByUserID(ctx context.Context, userID string) (*entities.Cart, error)
 	Update(ctx context.Context, cart *entities.Cart) error
 	Delete(ctx context.Context, id string) error
 	DeleteByUserID(ctx context.Context, userID string) error
 }
 
+// ErrQuantityExceedsLimit is returned when adding to a cart item would take it past the allowed quantity
+var ErrQuantityExceedsLimit = errors.New("cart item quantity exceeds limit")
+
 type CartItemRepository interface {
 	Create(ctx context.Context, item *entities.CartItem) error
+	// AddQuantity inserts item, or adds its quantity to the cart's existing item for the same
+	// product, refreshing the price and product snapshot. item is updated to the stored row.
+	AddQuantity(ctx context.Context, item *entities.CartItem, maxQuantity int) error
 	GetByID(ctx context.Context, id string) (*entities.CartItem, error)
+	// GetByCartID returns the cart's items ordered by sort order, then creation time and ID
 	GetByCartID(ctx context.Context, cartID string) ([]*entities.CartItem, error)
 	GetByCartAndProduct(ctx context.Context, cartID, productID string) (*entities.CartItem, error)
 	Update(ctx context.Context, item *entities.CartItem) error
 	Delete(ctx context.Context, id string) error
 	DeleteByCartID(ctx context.Context, cartID string) error
 	DeleteByCartAndProduct(ctx context.Context, cartID, productID string) error
+	// Reorder sets the sort order of the cart's items to their position in itemIDs
+	Reorder(ctx context.Context, cartID string, itemIDs []string) error
 }
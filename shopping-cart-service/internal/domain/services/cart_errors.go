@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidQuantity is returned when a requested item quantity is out of range
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	// ErrCartItemNotFound is returned when the item is not in the user's cart
+	ErrCartItemNotFound = errors.New("cart item not found")
+	// ErrProductNotFound is returned when the product service does not know the product
+	ErrProductNotFound = errors.New("product not found")
+	// ErrProductNotAvailable is returned when the product exists but is not for sale
+	ErrProductNotAvailable = errors.New("product is not available")
+	// ErrProductServiceUnavailable is returned while the product service circuit is open
+	ErrProductServiceUnavailable = errors.New("product service is temporarily unavailable")
+	// ErrCartEmpty is returned when an operation needs items but the cart has none
+	ErrCartEmpty = errors.New("cart is empty")
+	// ErrInvalidReorder is returned when a reorder does not list every cart item exactly once
+	ErrInvalidReorder = errors.New("item_ids must list every item in the cart exactly once")
+	// ErrCartLocked is returned when changing items while a checkout holds the cart lock
+	ErrCartLocked = errors.New("cart is locked during checkout")
+)
+
+// InsufficientStockError is returned when the product has fewer units than requested
+type InsufficientStockError struct {
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock. Only %d available", e.Available)
+}
+
+// CurrencyMismatchError is returned when a product's currency differs from the one the
+// cart is already priced in
+type CurrencyMismatchError struct {
+	ProductCurrency string
+	CartCurrency    string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("product is priced in %s but your cart is in %s", e.ProductCurrency, e.CartCurrency)
+}
@@ -10,6 +10,10 @@ type CartService interface {
 	AddItemToCart(ctx *fiber.Ctx, userID string, req *dto.AddItemRequest) (*dto.CartResponse, error)
 	UpdateCartItem(ctx *fiber.Ctx, userID string, itemID string, req *dto.UpdateItemRequest) (*dto.CartResponse, error)
 	RemoveItemFromCart(ctx *fiber.Ctx, userID string, itemID string) (*dto.CartResponse, error)
-	ClearCart(ctx *fiber.Ctx, userID string) error
+	ReorderCartItems(ctx *fiber.Ctx, userID string, req *dto.ReorderItemsRequest) (*dto.CartResponse, error)
+	ClearCart(ctx *fiber.Ctx, userID string) (*dto.CartResponse, error)
 	ValidateCart(ctx *fiber.Ctx, userID string) (*dto.CartValidationResponse, error)
+	// LockCart blocks item changes until UnlockCart, ClearCart or the lock's expiry
+	LockCart(ctx *fiber.Ctx, userID string) (*dto.CartLockResponse, error)
+	UnlockCart(ctx *fiber.Ctx, userID string) error
 }
@@ -22,9 +22,88 @@ func Migrate(db *gorm.DB, resetDb bool) error {
 		}
 	}
 
+	// Duplicate carts would block the unique user_id index
+	err = mergeDuplicateCarts(db)
+	if err != nil {
+		return fmt.Errorf("failed to merge duplicate carts: %w", err)
+	}
+
+	// Duplicate items would block the unique (cart_id, product_id) index
+	err = mergeDuplicateCartItems(db)
+	if err != nil {
+		return fmt.Errorf("failed to merge duplicate cart items: %w", err)
+	}
+
 	// Create tables with new schema
 	return db.AutoMigrate(
 		&entities.Cart{},
 		&entities.CartItem{},
 	)
 }
+
+// mergeDuplicateCarts moves the items of every duplicate cart for a user onto the
+// earliest cart and soft-deletes the rest. Items that end up duplicated are merged
+// by mergeDuplicateCartItems.
+func mergeDuplicateCarts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entities.Cart{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		duplicates := `
+			SELECT id, FIRST_VALUE(id) OVER (PARTITION BY user_id ORDER BY created_at ASC, id ASC) AS keep_id
+			FROM carts
+			WHERE deleted_at IS NULL`
+
+		err := tx.Exec(`
+			UPDATE cart_items SET cart_id = ranked.keep_id
+			FROM (` + duplicates + `) ranked
+			WHERE cart_items.cart_id = ranked.id AND ranked.id <> ranked.keep_id`).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE carts SET deleted_at = NOW()
+			WHERE id IN (
+				SELECT id FROM (` + duplicates + `) ranked
+				WHERE ranked.id <> ranked.keep_id
+			)`).Error
+	})
+}
+
+// mergeDuplicateCartItems folds the quantity of duplicate items for the same cart and
+// product into the earliest one and soft-deletes the rest
+func mergeDuplicateCartItems(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entities.CartItem{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			UPDATE cart_items SET quantity = totals.quantity
+			FROM (
+				SELECT cart_id, product_id, SUM(quantity) AS quantity,
+					(ARRAY_AGG(id ORDER BY created_at ASC, id ASC))[1] AS keep_id
+				FROM cart_items
+				WHERE deleted_at IS NULL
+				GROUP BY cart_id, product_id
+				HAVING COUNT(*) > 1
+			) totals
+			WHERE cart_items.id = totals.keep_id`).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE cart_items SET deleted_at = NOW()
+			WHERE deleted_at IS NULL AND id IN (
+				SELECT id FROM (
+					SELECT id, ROW_NUMBER() OVER (PARTITION BY cart_id, product_id ORDER BY created_at ASC, id ASC) AS rn
+					FROM cart_items
+					WHERE deleted_at IS NULL
+				) ranked
+				WHERE rn > 1
+			)`).Error
+	})
+}
@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type ProductServiceClient struct {
 	baseURL    string
 	httpClient *http.Client
+	latency    *prometheus.HistogramVec
+	breaker    *CircuitBreaker
+	// signingSecret signs every request so the product service can verify its origin
+	signingSecret string
 }
 
 type ProductResponse struct {
@@ -30,6 +37,14 @@ type ProductResponse struct {
 	UpdatedAt   string   `json:"updated_at"`
 }
 
+// ProductAvailability is the lightweight stock view returned by the product service
+type ProductAvailability struct {
+	ID        string `json:"id"`
+	Stock     int    `json:"stock"`
+	IsActive  bool   `json:"is_active"`
+	Available bool   `json:"available"`
+}
+
 type Category struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -44,16 +59,64 @@ type ServiceResponse struct {
 	Error   string          `json:"error,omitempty"`
 }
 
-func NewProductServiceClient(baseURL string) *ProductServiceClient {
+func NewProductServiceClient(baseURL string, timeout time.Duration, breaker *CircuitBreaker, signingSecret string, registerer prometheus.Registerer) *ProductServiceClient {
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "product_service_request_duration_seconds",
+		Help:    "Latency of calls to the product service in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "outcome"})
+	breakerState := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "product_service_circuit_breaker_state",
+		Help: "State of the product service circuit breaker: 0 closed, 1 open, 2 half-open.",
+	})
+	registerer.MustRegister(latency, breakerState)
+	breaker.OnStateChange(func(state BreakerState) { breakerState.Set(float64(state)) })
+
 	return &ProductServiceClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: timeout,
 		},
+		latency:       latency,
+		breaker:       breaker,
+		signingSecret: signingSecret,
+	}
+}
+
+// observe records the latency of a product service call started at start
+func (c *ProductServiceClient) observe(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		outcome = "short_circuit"
+	}
+	c.latency.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+// do signs req and sends it through the circuit breaker. Transport errors and 5xx responses count
+// as failures; other statuses mean the product service is up.
+func (c *ProductServiceClient) do(req *http.Request) (*http.Response, error) {
+	if err := signRequest(req, c.signingSecret); err != nil {
+		return nil, err
+	}
+
+	if err := c.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("product service unavailable: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	c.breaker.Record(err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
 	}
+	return resp, nil
 }
 
-func (c *ProductServiceClient) GetProducts(ctx context.Context, productIDs []string) ([]*ProductResponse, error) {
+func (c *ProductServiceClient) GetProducts(ctx context.Context, productIDs []string) (_ []*ProductResponse, err error) {
+	defer func(start time.Time) { c.observe("get_products", start, err) }(time.Now())
+
 	url := fmt.Sprintf("%s/api/products/ids", c.baseURL)
 
 	payload, err := json.Marshal(map[string][]string{"ids": productIDs})
@@ -70,9 +133,9 @@ func (c *ProductServiceClient) GetProducts(ctx context.Context, productIDs []str
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -100,7 +163,9 @@ func (c *ProductServiceClient) GetProducts(ctx context.Context, productIDs []str
 	return product, nil
 }
 
-func (c *ProductServiceClient) GetProduct(ctx context.Context, productID string) (*ProductResponse, error) {
+func (c *ProductServiceClient) GetProduct(ctx context.Context, productID string) (_ *ProductResponse, err error) {
+	defer func(start time.Time) { c.observe("get_product", start, err) }(time.Now())
+
 	url := fmt.Sprintf("%s/api/products/%s", c.baseURL, productID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -108,9 +173,9 @@ func (c *ProductServiceClient) GetProduct(ctx context.Context, productID string)
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch product: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -138,11 +203,87 @@ func (c *ProductServiceClient) GetProduct(ctx context.Context, productID string)
 	return &product, nil
 }
 
+// GetAvailability fetches only the stock and active state of the given products. Unknown
+// products are missing from the result.
+func (c *ProductServiceClient) GetAvailability(ctx context.Context, productIDs []string) (_ []ProductAvailability, err error) {
+	defer func(start time.Time) { c.observe("get_availability", start, err) }(time.Now())
+
+	url := fmt.Sprintf("%s/api/products/availability", c.baseURL)
+
+	payload, err := json.Marshal(map[string][]string{"ids": productIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	var serviceResp ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&serviceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !serviceResp.Success {
+		return nil, fmt.Errorf("product service error: %s", serviceResp.Error)
+	}
+
+	var availability []ProductAvailability
+	if err := json.Unmarshal(serviceResp.Data, &availability); err != nil {
+		return nil, fmt.Errorf("failed to decode availability data: %w", err)
+	}
+
+	return availability, nil
+}
+
+// CheckStock reports whether the product is active and has at least quantity in stock
 func (c *ProductServiceClient) CheckStock(ctx context.Context, productID string, quantity int) (bool, error) {
-	product, err := c.GetProduct(ctx, productID)
+	availability, err := c.GetAvailability(ctx, []string{productID})
 	if err != nil {
 		return false, err
 	}
+	if len(availability) == 0 {
+		return false, fmt.Errorf("product not found")
+	}
+
+	return availability[0].IsActive && availability[0].Stock >= quantity, nil
+}
+
+// CheckProductServiceHealth calls the product service health endpoint at baseURL and
+// returns an error unless it answers 200 within timeout
+func CheckProductServiceHealth(ctx context.Context, baseURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/api/health", baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("product service at %s is unreachable: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("product service health check at %s returned status %d", url, resp.StatusCode)
+	}
 
-	return product.Stock >= quantity && product.IsActive, nil
+	return nil
 }
@@ -0,0 +1,81 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var ErrStoreNotFound = errors.New("store not found")
+
+type StoreServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type StoreResponse struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	IsActive bool          `json:"is_active"`
+	Settings StoreSettings `json:"settings"`
+}
+
+type StoreSettings struct {
+	Currency              string  `json:"currency"`
+	ShippingMethod        string  `json:"shipping_method"`
+	ShippingFlatRate      float64 `json:"shipping_flat_rate"`
+	FreeShippingThreshold float64 `json:"free_shipping_threshold"`
+	TaxRate               float64 `json:"tax_rate"`
+}
+
+func NewStoreServiceClient(baseURL string) *StoreServiceClient {
+	return &StoreServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetStore returns the store and its settings, or ErrStoreNotFound when it does not exist
+func (c *StoreServiceClient) GetStore(ctx context.Context, storeID string) (*StoreResponse, error) {
+	url := fmt.Sprintf("%s/api/internal/stores/%s", c.baseURL, storeID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Internal-Service", "shopping-cart-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch store: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, ErrStoreNotFound
+		}
+		return nil, fmt.Errorf("store service returned status %d", resp.StatusCode)
+	}
+
+	var serviceResp ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&serviceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !serviceResp.Success {
+		return nil, fmt.Errorf("store service error: %s", serviceResp.Error)
+	}
+
+	var store StoreResponse
+	if err := json.Unmarshal(serviceResp.Data, &store); err != nil {
+		return nil, fmt.Errorf("failed to decode store data: %w", err)
+	}
+
+	return &store, nil
+}
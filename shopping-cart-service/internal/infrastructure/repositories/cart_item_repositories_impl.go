@@ -7,6 +7,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type cartItemRepository struct {
@@ -18,7 +19,51 @@ func NewCartItemRepository(db *gorm.DB) repositories.CartItemRepository {
 }
 
 func (r *cartItemRepository) Create(ctx context.Context, item *entities.CartItem) error {
-	return r.db.WithContext(ctx).Create(item).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := assignNextSortOrder(tx, item); err != nil {
+			return err
+		}
+		return tx.Create(item).Error
+	})
+}
+
+// assignNextSortOrder places a new item after the cart's existing items
+func assignNextSortOrder(tx *gorm.DB, item *entities.CartItem) error {
+	return tx.Model(&entities.CartItem{}).
+		Select("COALESCE(MAX(sort_order) + 1, 0)").
+		Where("cart_id = ?", item.CartID).
+		Scan(&item.SortOrder).Error
+}
+
+func (r *cartItemRepository) AddQuantity(ctx context.Context, item *entities.CartItem, maxQuantity int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only used when inserting; an existing item keeps its position
+		if err := assignNextSortOrder(tx, item); err != nil {
+			return err
+		}
+
+		// updated_at is taken from the insert, so adding to an existing item refreshes it
+		err := tx.Clauses(
+			clause.OnConflict{
+				Columns:     []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+				DoUpdates: append(
+					clause.AssignmentColumns([]string{"price_at_time", "currency", "product_name", "product_sku", "updated_at"}),
+					clause.Assignment{Column: clause.Column{Name: "quantity"}, Value: gorm.Expr("cart_items.quantity + EXCLUDED.quantity")},
+				),
+			},
+			clause.Returning{},
+		).Create(item).Error
+		if err != nil {
+			return err
+		}
+
+		// Roll back rather than leave more in the cart than is allowed
+		if item.Quantity > maxQuantity {
+			return repositories.ErrQuantityExceedsLimit
+		}
+		return nil
+	})
 }
 
 func (r *cartItemRepository) GetByID(ctx context.Context, id string) (*entities.CartItem, error) {
@@ -35,7 +80,10 @@ func (r *cartItemRepository) GetByID(ctx context.Context, id string) (*entities.
 
 func (r *cartItemRepository) GetByCartID(ctx context.Context, cartID string) ([]*entities.CartItem, error) {
 	var items []*entities.CartItem
-	err := r.db.WithContext(ctx).Where("cart_id = ?", cartID).Find(&items).Error
+	err := r.db.WithContext(ctx).
+		Where("cart_id = ?", cartID).
+		Order("sort_order, created_at, id").
+		Find(&items).Error
 	return items, err
 }
 
@@ -66,3 +114,18 @@ func (r *cartItemRepository) DeleteByCartID(ctx context.Context, cartID string)
 func (r *cartItemRepository) DeleteByCartAndProduct(ctx context.Context, cartID, productID string) error {
 	return r.db.WithContext(ctx).Where("cart_id = ? AND product_id = ?", cartID, productID).Delete(&entities.CartItem{}).Error
 }
+
+func (r *cartItemRepository) Reorder(ctx context.Context, cartID string, itemIDs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, itemID := range itemIDs {
+			// UpdateColumn leaves updated_at alone; moving an item does not change it
+			err := tx.Model(&entities.CartItem{}).
+				Where("id = ? AND cart_id = ?", itemID, cartID).
+				UpdateColumn("sort_order", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/repositories"
+)
+
+type cartLockRepository struct {
+	client *redis.Client
+}
+
+func NewCartLockRepository(client *redis.Client) repositories.CartLockRepository {
+	return &cartLockRepository{client: client}
+}
+
+func cartLockKey(userID string) string {
+	return "cart_lock:" + userID
+}
+
+func (r *cartLockRepository) Lock(ctx context.Context, userID string, ttl time.Duration) error {
+	return r.client.Set(ctx, cartLockKey(userID), "1", ttl).Err()
+}
+
+func (r *cartLockRepository) Unlock(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, cartLockKey(userID)).Err()
+}
+
+func (r *cartLockRepository) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	ttl, err := r.client.PTTL(ctx, cartLockKey(userID)).Result()
+	if err != nil {
+		return time.Time{}, err
+	}
+	// PTTL reports a negative duration when the key does not exist or has no expiry
+	if ttl <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(ttl), nil
+}
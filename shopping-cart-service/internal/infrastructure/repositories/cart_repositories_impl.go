@@ -7,6 +7,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/domain/repositories"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type cartRepository struct {
@@ -45,6 +46,27 @@ func (r *cartRepository) GetByUserID(ctx context.Context, userID string) (*entit
 	return &cart, nil
 }
 
+func (r *cartRepository) GetOrCreateByUserID(ctx context.Context, userID string) (*entities.Cart, error) {
+	db := r.db.WithContext(ctx)
+
+	// A concurrent request may create the cart first; the unique user_id index turns
+	// that into a no-op and the read below returns whichever cart won
+	err := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&entities.Cart{UserID: userID}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cart entities.Cart
+	if err := db.Preload("Items").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
 func (r *cartRepository) Update(ctx context.Context, cart *entities.Cart) error {
 	return r.db.WithContext(ctx).Save(cart).Error
 }
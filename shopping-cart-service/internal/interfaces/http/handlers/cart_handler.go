@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/application/dto"
@@ -18,6 +20,30 @@ func NewCartHandler(cartService services.CartService) *CartHandler {
 	}
 }
 
+// cartError writes err with the status and code of its typed cart error, falling back to
+// fallbackStatus for errors the service does not classify
+func cartError(c *fiber.Ctx, err error, fallbackStatus int) error {
+	var stockErr *services.InsufficientStockError
+	var currencyErr *services.CurrencyMismatchError
+	switch {
+	case errors.As(err, &stockErr):
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, utils.CodeInsufficientStock, err.Error())
+	case errors.As(err, &currencyErr):
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, utils.CodeConflict, err.Error())
+	case errors.Is(err, services.ErrCartLocked):
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, utils.CodeCartLocked, err.Error())
+	case errors.Is(err, services.ErrCartItemNotFound), errors.Is(err, services.ErrProductNotFound):
+		return utils.ErrorResponseWithCode(c, fiber.StatusNotFound, utils.CodeNotFound, err.Error())
+	case errors.Is(err, services.ErrProductServiceUnavailable):
+		return utils.ErrorResponseWithCode(c, fiber.StatusServiceUnavailable, utils.CodeServiceUnavailable, err.Error())
+	case errors.Is(err, services.ErrInvalidQuantity), errors.Is(err, services.ErrInvalidReorder),
+		errors.Is(err, services.ErrProductNotAvailable), errors.Is(err, services.ErrCartEmpty):
+		return utils.ErrorResponseWithCode(c, fiber.StatusBadRequest, utils.CodeValidation, err.Error())
+	default:
+		return utils.ErrorResponse(c, fallbackStatus, err.Error())
+	}
+}
+
 func (h *CartHandler) GetCart(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
@@ -26,7 +52,7 @@ func (h *CartHandler) GetCart(c *fiber.Ctx) error {
 
 	cart, err := h.cartService.GetCart(c, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return cartError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "Cart retrieved successfully", cart)
@@ -52,7 +78,7 @@ func (h *CartHandler) AddItemToCart(c *fiber.Ctx) error {
 
 	cart, err := h.cartService.AddItemToCart(c, userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return cartError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Item added to cart successfully", cart)
@@ -74,14 +100,40 @@ func (h *CartHandler) UpdateCartItem(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	if req.Quantity == nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Quantity is required")
+	}
+	if *req.Quantity < 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Quantity must be >= 0")
+	}
+
 	cart, err := h.cartService.UpdateCartItem(c, userID, itemID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return cartError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Cart item updated successfully", cart)
 }
 
+func (h *CartHandler) ReorderCartItems(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	var req dto.ReorderItemsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	cart, err := h.cartService.ReorderCartItems(c, userID, &req)
+	if err != nil {
+		return cartError(c, err, fiber.StatusBadRequest)
+	}
+
+	return utils.SuccessResponse(c, "Cart items reordered successfully", cart)
+}
+
 func (h *CartHandler) RemoveItemFromCart(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
@@ -95,7 +147,7 @@ func (h *CartHandler) RemoveItemFromCart(c *fiber.Ctx) error {
 
 	cart, err := h.cartService.RemoveItemFromCart(c, userID, itemID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return cartError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Item removed from cart successfully", cart)
@@ -107,11 +159,12 @@ func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
 	}
 
-	if err := h.cartService.ClearCart(c, userID); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	cart, err := h.cartService.ClearCart(c, userID)
+	if err != nil {
+		return cartError(c, err, fiber.StatusInternalServerError)
 	}
 
-	return utils.SuccessResponse(c, "Cart cleared successfully", nil)
+	return utils.SuccessResponse(c, "Cart cleared successfully", cart)
 }
 
 func (h *CartHandler) ValidateCart(c *fiber.Ctx) error {
@@ -122,8 +175,35 @@ func (h *CartHandler) ValidateCart(c *fiber.Ctx) error {
 
 	validation, err := h.cartService.ValidateCart(c, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return cartError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "Cart validated successfully", validation)
 }
+
+func (h *CartHandler) LockCart(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	lock, err := h.cartService.LockCart(c, userID)
+	if err != nil {
+		return cartError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return utils.SuccessResponse(c, "Cart locked for checkout", lock)
+}
+
+func (h *CartHandler) UnlockCart(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	if err := h.cartService.UnlockCart(c, userID); err != nil {
+		return cartError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return utils.SuccessResponse(c, "Cart unlocked", dto.CartLockResponse{Locked: false})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/external"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/middleware"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/utils"
 	"gorm.io/gorm"
 )
@@ -16,6 +17,7 @@ type RoutesDependencies struct {
 	Db          *gorm.DB
 	RedisClient *redis.Client
 	Config      *config.Config
+	Metrics     *middleware.Metrics
 }
 
 func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
@@ -28,15 +30,23 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 	// Initialize repositories
 	cartRepo := repositories.NewCartRepository(deps.Db)
 	cartItemRepo := repositories.NewCartItemRepository(deps.Db)
+	cartLocks := repositories.NewCartLockRepository(deps.RedisClient)
 
 	// Initialize external service clients
-	productService := external.NewProductServiceClient(deps.Config.ProductServiceURL)
+	productBreaker := external.NewCircuitBreaker(
+		deps.Config.ProductServiceBreaker.FailureThreshold,
+		deps.Config.ProductServiceBreaker.Cooldown,
+	)
+	productService := external.NewProductServiceClient(deps.Config.ProductServiceURL, deps.Config.ProductServiceTimeout, productBreaker, deps.Config.InternalSigningSecret, deps.Metrics.Registerer())
+	storeService := external.NewStoreServiceClient(deps.Config.StoreServiceURL)
 
 	// Initialize services
 	cartService := services.NewCartService(
 		cartRepo,
 		cartItemRepo,
+		cartLocks,
 		productService,
+		storeService,
 		deps.Config,
 	)
 
@@ -47,8 +57,12 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 	cart := api.Group("/cart")
 	cart.Get("/", cartHandler.GetCart)
 	cart.Post("/items", cartHandler.AddItemToCart)
+	// Registered before /items/:itemId, which would otherwise match it
+	cart.Put("/items/order", cartHandler.ReorderCartItems)
 	cart.Put("/items/:itemId", cartHandler.UpdateCartItem)
 	cart.Delete("/items/:itemId", cartHandler.RemoveItemFromCart)
 	cart.Delete("/clear", cartHandler.ClearCart)
 	cart.Post("/validate", cartHandler.ValidateCart)
+	cart.Post("/lock", cartHandler.LockCart)
+	cart.Delete("/lock", cartHandler.UnlockCart)
 }
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestTimeout gives every request an overall deadline. The deadline is set on
+// c.UserContext(), which handlers pass down to repositories and outgoing calls so their
+// work is cancelled once it passes; the request then fails with 504. A non-positive
+// timeout disables it.
+func RequestTimeout(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+
+		log.Warn().
+			Str("method", c.Method()).
+			Str("path", c.Path()).
+			Dur("timeout", timeout).
+			Msg("Request timed out")
+
+		c.Response().Reset()
+		message := "Request timed out"
+		return c.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{
+			"success": false,
+			"code":    "TIMEOUT",
+			"message": message,
+			"error":   message,
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 type Response struct {
 	Success   bool        `json:"success"`
+	Code      string      `json:"code,omitempty"`
 	Message   string      `json:"message"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     string      `json:"error,omitempty"`
@@ -23,9 +24,16 @@ func SuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
 }
 
 func ErrorResponse(c *fiber.Ctx, statusCode int, message string) error {
+	return ErrorResponseWithCode(c, statusCode, CodeForStatus(statusCode), message)
+}
+
+// ErrorResponseWithCode writes an error envelope carrying a machine-readable code alongside the message
+func ErrorResponseWithCode(c *fiber.Ctx, statusCode int, code string, message string) error {
 	requestID := getRequestID(c)
 	return c.Status(statusCode).JSON(Response{
 		Success:   false,
+		Code:      code,
+		Message:   message,
 		Error:     message,
 		RequestID: requestID,
 	})
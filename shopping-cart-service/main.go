@@ -1,22 +1,30 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/config"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/db"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/infrastructure/external"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/interfaces/http/routes"
 	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/middleware"
+	"github.com/tasiuskenways/scalable-ecommerce/shopping-cart-service/internal/utils"
 	"gorm.io/gorm"
 )
 
 func main() {
 	cfg := config.Load()
+	middleware.SetLogLevel(cfg.LogLevel)
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
 
 	runMigration := flag.Bool("migrate", false, "Run migration")
 	resetDb := flag.Bool("resetDb", false, "Reset DB")
@@ -42,34 +50,66 @@ func main() {
 		log.Fatal("Failed to connect to Redis:", err)
 	}
 
+	if cfg.ProductServiceHealthCheck {
+		if err := external.CheckProductServiceHealth(context.Background(), cfg.ProductServiceURL, cfg.ProductServiceTimeout); err != nil {
+			log.Fatal("Product service health check failed: ", err)
+		}
+	}
+
 	app := fiber.New(fiber.Config{
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 			}
-			return c.Status(code).JSON(fiber.Map{
-				"error":   true,
-				"message": err.Error(),
-			})
+			return utils.ErrorResponse(c, code, err.Error())
 		},
 	})
 
 	app.Use(requestid.New())
 	app.Use(recover.New())
 
+	// Per-service Prometheus metrics, scraped at /metrics
+	metrics := middleware.NewMetrics("shopping-cart-service")
+	app.Use(metrics.Handler())
+	app.Get(middleware.MetricsPath, metrics.Endpoint())
+
+	// Settings that can be reloaded at runtime are read through configHolder
+	configHolder := config.NewHolder(cfg)
+
 	// Add comprehensive request/response logging
-	app.Use(middleware.RequestResponseLogger())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-User-Id",
+	app.Use(middleware.RequestResponseLogger(func() int { return configHolder.Get().LogBodySampleRate }))
+	app.Use(middleware.CORS(func() string { return configHolder.Get().CORSOrigins }, "Origin,Content-Type,Accept,Authorization,X-User-Id"))
+
+	// Maintenance mode rejects writes with 503; toggled at runtime via the admin endpoints
+	maintenance := middleware.NewMaintenance(
+		middleware.NewRedisMaintenanceFlag(redis, "shopping-cart-service"),
+		func() bool { return configHolder.Get().Maintenance.Enabled },
+		func() time.Duration { return configHolder.Get().Maintenance.RetryAfter },
+	)
+	app.Use(maintenance.Handler())
+
+	// Overall deadline for each request, propagated through c.UserContext()
+	app.Use(middleware.RequestTimeout(cfg.RequestTimeout))
+
+	// Operator endpoints, guarded by ADMIN_TOKEN
+	admin := app.Group(middleware.AdminPathPrefix, middleware.AdminOnly(cfg.AdminToken))
+	admin.Get("/maintenance", maintenance.StatusEndpoint())
+	admin.Put("/maintenance", maintenance.UpdateEndpoint())
+	admin.Post("/config/reload", middleware.ReloadEndpoint(func() (map[string]any, error) {
+		reloaded, err := configHolder.Reload()
+		if err != nil {
+			return nil, err
+		}
+		middleware.SetLogLevel(reloaded.LogLevel)
+		return reloaded.Reloadable(), nil
 	}))
 
 	routes.SetupRoutes(app, routes.RoutesDependencies{
 		Db:          postgres,
 		RedisClient: redis,
 		Config:      cfg,
+		Metrics:     metrics,
 	})
 
 	log.Printf("Server starting on port %s", cfg.AppPort)
@@ -1,41 +1,127 @@
 package dto
 
 import (
+	"encoding/json"
+	"slices"
+	"sort"
+
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 )
 
 type CreateStoreRequest struct {
-	Name        string                 `json:"name" validate:"required,min=2,max=100"`
-	Slug        string                 `json:"slug" validate:"required,min=2,max=100,alphanum"`
-	Description string                 `json:"description" validate:"max=1000"`
-	Logo        string                 `json:"logo,omitempty" validate:"omitempty,url"`
-	Banner      string                 `json:"banner,omitempty" validate:"omitempty,url"`
-	Website     string                 `json:"website,omitempty" validate:"omitempty,url"`
-	Phone       string                 `json:"phone,omitempty"`
-	Email       string                 `json:"email,omitempty" validate:"omitempty,email"`
-	Address     string                 `json:"address,omitempty"`
-	City        string                 `json:"city,omitempty"`
-	State       string                 `json:"state,omitempty"`
-	Country     string                 `json:"country,omitempty"`
-	PostalCode  string                 `json:"postal_code,omitempty"`
-	Settings    entities.StoreSettings `json:"settings,omitempty"`
+	Name        string                `json:"name" validate:"required,min=2,max=100"`
+	Slug        string                `json:"slug" validate:"required,min=2,max=100,alphanum"`
+	Description string                `json:"description" validate:"max=1000"`
+	Logo        string                `json:"logo,omitempty" validate:"omitempty,url"`
+	Banner      string                `json:"banner,omitempty" validate:"omitempty,url"`
+	Website     string                `json:"website,omitempty" validate:"omitempty,url"`
+	Phone       string                `json:"phone,omitempty"`
+	Email       string                `json:"email,omitempty" validate:"omitempty,email"`
+	Address     string                `json:"address,omitempty"`
+	City        string                `json:"city,omitempty"`
+	State       string                `json:"state,omitempty"`
+	Country     string                `json:"country,omitempty"`
+	PostalCode  string                `json:"postal_code,omitempty"`
+	Settings    *StoreSettingsRequest `json:"settings,omitempty"`
 }
 
 type UpdateStoreRequest struct {
-	Name        *string                 `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
-	Description *string                 `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Logo        *string                 `json:"logo,omitempty" validate:"omitempty,url"`
-	Banner      *string                 `json:"banner,omitempty" validate:"omitempty,url"`
-	Website     *string                 `json:"website,omitempty" validate:"omitempty,url"`
-	Phone       *string                 `json:"phone,omitempty"`
-	Email       *string                 `json:"email,omitempty" validate:"omitempty,email"`
-	Address     *string                 `json:"address,omitempty"`
-	City        *string                 `json:"city,omitempty"`
-	State       *string                 `json:"state,omitempty"`
-	Country     *string                 `json:"country,omitempty"`
-	PostalCode  *string                 `json:"postal_code,omitempty"`
-	IsActive    *bool                   `json:"is_active,omitempty"`
-	Settings    *entities.StoreSettings `json:"settings,omitempty"`
+	Name        *string               `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Slug        *string               `json:"slug,omitempty" validate:"omitempty,min=2,max=100,alphanum"`
+	Description *string               `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Logo        *string               `json:"logo,omitempty" validate:"omitempty,url"`
+	Banner      *string               `json:"banner,omitempty" validate:"omitempty,url"`
+	Website     *string               `json:"website,omitempty" validate:"omitempty,url"`
+	Phone       *string               `json:"phone,omitempty"`
+	Email       *string               `json:"email,omitempty" validate:"omitempty,email"`
+	Address     *string               `json:"address,omitempty"`
+	City        *string               `json:"city,omitempty"`
+	State       *string               `json:"state,omitempty"`
+	Country     *string               `json:"country,omitempty"`
+	PostalCode  *string               `json:"postal_code,omitempty"`
+	IsActive    *bool                 `json:"is_active,omitempty"`
+	Settings    *StoreSettingsRequest `json:"settings,omitempty"`
+	// WebhookURL may be set to "" to disable member webhooks
+	WebhookURL    *string `json:"webhook_url,omitempty" validate:"omitempty,url"`
+	WebhookSecret *string `json:"webhook_secret,omitempty" validate:"omitempty,min=16,max=255"`
+	// Version, when sent, must match the store's current version or the update is rejected
+	Version *int64 `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+// StoreSettingsRequest carries the settings a client sent; nil fields were not provided
+type StoreSettingsRequest struct {
+	Currency           *string `json:"currency,omitempty"`
+	Timezone           *string `json:"timezone,omitempty"`
+	AllowPublicListing *bool   `json:"allow_public_listing,omitempty"`
+	RequireApproval    *bool   `json:"require_approval,omitempty"`
+	MaxProducts        *int    `json:"max_products,omitempty"`
+
+	ShippingMethod        *string  `json:"shipping_method,omitempty"`
+	ShippingFlatRate      *float64 `json:"shipping_flat_rate,omitempty"`
+	FreeShippingThreshold *float64 `json:"free_shipping_threshold,omitempty"`
+	TaxRate               *float64 `json:"tax_rate,omitempty"`
+
+	PaymentMethods *[]string `json:"payment_methods,omitempty"`
+
+	// UnknownKeys holds settings keys in the request that StoreSettings does not define
+	UnknownKeys []string `json:"-"`
+}
+
+func (r *StoreSettingsRequest) UnmarshalJSON(data []byte) error {
+	type alias StoreSettingsRequest
+	if err := json.Unmarshal(data, (*alias)(r)); err != nil {
+		return err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	r.UnknownKeys = nil
+	for key := range keys {
+		if !slices.Contains(entities.StoreSettingsKeys, key) {
+			r.UnknownKeys = append(r.UnknownKeys, key)
+		}
+	}
+	sort.Strings(r.UnknownKeys)
+
+	return nil
+}
+
+// MergeInto returns base with every provided setting overriding its counterpart
+func (r *StoreSettingsRequest) MergeInto(base entities.StoreSettings) entities.StoreSettings {
+	if r.Currency != nil {
+		base.Currency = *r.Currency
+	}
+	if r.Timezone != nil {
+		base.Timezone = *r.Timezone
+	}
+	if r.AllowPublicListing != nil {
+		base.AllowPublicListing = *r.AllowPublicListing
+	}
+	if r.RequireApproval != nil {
+		base.RequireApproval = *r.RequireApproval
+	}
+	if r.MaxProducts != nil {
+		base.MaxProducts = *r.MaxProducts
+	}
+	if r.ShippingMethod != nil {
+		base.ShippingMethod = *r.ShippingMethod
+	}
+	if r.ShippingFlatRate != nil {
+		base.ShippingFlatRate = *r.ShippingFlatRate
+	}
+	if r.FreeShippingThreshold != nil {
+		base.FreeShippingThreshold = *r.FreeShippingThreshold
+	}
+	if r.TaxRate != nil {
+		base.TaxRate = *r.TaxRate
+	}
+	if r.PaymentMethods != nil {
+		base.PaymentMethods = *r.PaymentMethods
+	}
+	return base
 }
 
 type StoreResponse struct {
@@ -55,6 +141,8 @@ type StoreResponse struct {
 	PostalCode  string                    `json:"postal_code,omitempty"`
 	IsActive    bool                      `json:"is_active"`
 	Settings    entities.StoreSettings    `json:"settings"`
+	WebhookURL  string                    `json:"webhook_url,omitempty"`
+	Version     int64                     `json:"version"`
 	CreatedAt   string                    `json:"created_at"`
 	UpdatedAt   string                    `json:"updated_at"`
 	UserRole    *entities.StoreRole       `json:"user_role,omitempty"`
@@ -72,6 +160,38 @@ type StoreListResponse struct {
 type InviteMemberRequest struct {
 	Email string             `json:"email" validate:"required,email"`
 	Role  entities.StoreRole `json:"role" validate:"required,oneof=ADMIN MANAGER MEMBER"`
+	// ExpiresInDays overrides the configured invitation TTL, up to the configured maximum
+	ExpiresInDays *int `json:"expires_in_days,omitempty" validate:"omitempty,min=1"`
+}
+
+// BulkInviteMembersRequest invites several emails with the same role
+type BulkInviteMembersRequest struct {
+	Emails []string           `json:"emails" validate:"required,min=1,max=50,dive,required,email"`
+	Role   entities.StoreRole `json:"role" validate:"required,oneof=ADMIN MANAGER MEMBER"`
+}
+
+// BulkInviteStatus is the outcome of one email in a bulk invite
+type BulkInviteStatus string
+
+const (
+	BulkInviteInvited        BulkInviteStatus = "invited"
+	BulkInviteAlreadyMember  BulkInviteStatus = "already_member"
+	BulkInviteAlreadyInvited BulkInviteStatus = "already_invited"
+	BulkInviteDuplicate      BulkInviteStatus = "duplicate"
+	BulkInviteRateLimited    BulkInviteStatus = "rate_limited"
+	BulkInviteFailed         BulkInviteStatus = "failed"
+)
+
+type BulkInviteResult struct {
+	Email      string                   `json:"email"`
+	Status     BulkInviteStatus         `json:"status"`
+	Invitation *StoreInvitationResponse `json:"invitation,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+type BulkInviteResponse struct {
+	Invited int                `json:"invited"`
+	Results []BulkInviteResult `json:"results"`
 }
 
 type UpdateMemberRoleRequest struct {
@@ -96,6 +216,46 @@ type StoreMemberResponse struct {
 	Role     entities.StoreRole `json:"role"`
 	IsActive bool               `json:"is_active"`
 	JoinedAt string             `json:"joined_at"`
+	// LastActiveAt is omitted for members who have not acted on the store yet
+	LastActiveAt *string `json:"last_active_at,omitempty"`
+}
+
+type StoreDashboardResponse struct {
+	StoreID               string  `json:"store_id"`
+	ProductStatsAvailable bool    `json:"product_stats_available"`
+	ProductCount          int     `json:"product_count"`
+	LowStockCount         int     `json:"low_stock_count"`
+	LowStockThreshold     int     `json:"low_stock_threshold"`
+	TotalInventoryValue   float64 `json:"total_inventory_value"`
+}
+
+// StoreMembershipResponse describes a user's role in a store for other services
+type StoreMembershipResponse struct {
+	StoreID     string                   `json:"store_id"`
+	UserID      string                   `json:"user_id"`
+	Role        entities.StoreRole       `json:"role"`
+	Permissions entities.RolePermissions `json:"permissions"`
+}
+
+// InternalStoreResponse exposes the commercial settings of a store to other services
+type InternalStoreResponse struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	IsActive bool                   `json:"is_active"`
+	Settings entities.StoreSettings `json:"settings"`
+}
+
+// InvitationPreviewResponse is the public view of an invitation, looked up by its token
+// before the invitee has signed in; it carries only what they need to decide to join
+type InvitationPreviewResponse struct {
+	StoreName string                    `json:"store_name"`
+	StoreSlug string                    `json:"store_slug"`
+	StoreLogo string                    `json:"store_logo,omitempty"`
+	Email     string                    `json:"email"`
+	Role      entities.StoreRole        `json:"role"`
+	Status    entities.InvitationStatus `json:"status"`
+	ExpiresAt string                    `json:"expires_at"`
+	CanAccept bool                      `json:"can_accept"`
 }
 
 type AcceptInvitationRequest struct {
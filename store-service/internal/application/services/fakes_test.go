@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/repositories"
+	repoImpl "github.com/tasiuskenways/scalable-ecommerce/store-service/internal/infrastructure/repositories"
+)
+
+// fakeStoreRepo keeps stores in memory and follows the slug and version rules of the
+// real repository
+type fakeStoreRepo struct {
+	mu     sync.Mutex
+	stores map[string]entities.Store
+	nextID int
+}
+
+func newFakeStoreRepo() *fakeStoreRepo {
+	return &fakeStoreRepo{stores: make(map[string]entities.Store)}
+}
+
+func (r *fakeStoreRepo) Create(store *entities.Store) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.stores {
+		if existing.Slug == store.Slug {
+			return repoImpl.ErrStoreSlugExists
+		}
+	}
+	if store.ID == "" {
+		r.nextID++
+		store.ID = "store-" + strconv.Itoa(r.nextID)
+	}
+	store.Version = 1
+	r.stores[store.ID] = *store
+	return nil
+}
+
+func (r *fakeStoreRepo) GetByID(id string) (*entities.Store, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	store, ok := r.stores[id]
+	if !ok {
+		return nil, repoImpl.ErrStoreNotFound
+	}
+	return &store, nil
+}
+
+func (r *fakeStoreRepo) GetBySlug(slug string) (*entities.Store, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, store := range r.stores {
+		if store.Slug == slug {
+			return &store, nil
+		}
+	}
+	return nil, repoImpl.ErrStoreNotFound
+}
+
+func (r *fakeStoreRepo) GetByUserID(userID string, limit, offset int) ([]entities.Store, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeStoreRepo) Update(store *entities.Store) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.stores[store.ID]
+	if !ok || current.Version != store.Version {
+		return repoImpl.ErrStoreVersionConflict
+	}
+	for id, existing := range r.stores {
+		if id != store.ID && existing.Slug == store.Slug {
+			return repoImpl.ErrStoreSlugExists
+		}
+	}
+	store.Version++
+	r.stores[store.ID] = *store
+	return nil
+}
+
+func (r *fakeStoreRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.stores, id)
+	return nil
+}
+
+func (r *fakeStoreRepo) GetStoresByFilter(filter repositories.StoreFilter) ([]entities.Store, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *fakeStoreRepo) SlugExists(slug string, excludeID ...string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, store := range r.stores {
+		if store.Slug == slug && (len(excludeID) == 0 || id != excludeID[0]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeRoleRepo keeps memberships in memory. Like the real repository, access-check lookups
+// skip suspended memberships.
+type fakeRoleRepo struct {
+	mu    sync.Mutex
+	roles []entities.UserStoreRole
+}
+
+func (r *fakeRoleRepo) find(userID, storeID string, includeSuspended bool) *entities.UserStoreRole {
+	for i := range r.roles {
+		role := &r.roles[i]
+		if role.UserID == userID && role.StoreID == storeID && (includeSuspended || role.IsActive) {
+			return role
+		}
+	}
+	return nil
+}
+
+func (r *fakeRoleRepo) Create(role *entities.UserStoreRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.find(role.UserID, role.StoreID, true) != nil {
+		return repoImpl.ErrAlreadyMember
+	}
+	r.roles = append(r.roles, *role)
+	return nil
+}
+
+func (r *fakeRoleRepo) GetByUserAndStore(userID, storeID string) (*entities.UserStoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if role := r.find(userID, storeID, false); role != nil {
+		found := *role
+		return &found, nil
+	}
+	return nil, errors.New("membership not found")
+}
+
+func (r *fakeRoleRepo) GetMembershipIncludingSuspended(userID, storeID string) (*entities.UserStoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if role := r.find(userID, storeID, true); role != nil {
+		found := *role
+		return &found, nil
+	}
+	return nil, errors.New("membership not found")
+}
+
+func (r *fakeRoleRepo) GetByEmailAndStore(email, storeID string) (*entities.UserStoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, role := range r.roles {
+		if role.Email == email && role.StoreID == storeID {
+			return &role, nil
+		}
+	}
+	return nil, errors.New("membership not found")
+}
+
+func (r *fakeRoleRepo) GetByStoreID(storeID string) ([]entities.UserStoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var roles []entities.UserStoreRole
+	for _, role := range r.roles {
+		if role.StoreID == storeID {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) GetByUserID(userID string) ([]entities.UserStoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var roles []entities.UserStoreRole
+	for _, role := range r.roles {
+		if role.UserID == userID && role.IsActive {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) Update(role *entities.UserStoreRole) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := r.find(role.UserID, role.StoreID, true)
+	if existing == nil {
+		return errors.New("membership not found")
+	}
+	*existing = *role
+	return nil
+}
+
+func (r *fakeRoleRepo) SetActive(userID, storeID string, active bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := r.find(userID, storeID, true)
+	if existing == nil {
+		return errors.New("membership not found")
+	}
+	existing.IsActive = active
+	return nil
+}
+
+func (r *fakeRoleRepo) Delete(userID, storeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, role := range r.roles {
+		if role.UserID == userID && role.StoreID == storeID {
+			r.roles = append(r.roles[:i], r.roles[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakeRoleRepo) GetUserRole(userID, storeID string) (entities.StoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if role := r.find(userID, storeID, false); role != nil {
+		return role.Role, nil
+	}
+	return "", errors.New("membership not found")
+}
+
+func (r *fakeRoleRepo) GetUserRolesForStores(userID string, storeIDs []string) (map[string]entities.StoreRole, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles := make(map[string]entities.StoreRole)
+	for _, storeID := range storeIDs {
+		if role := r.find(userID, storeID, false); role != nil {
+			roles[storeID] = role.Role
+		}
+	}
+	return roles, nil
+}
+
+func (r *fakeRoleRepo) HasPermission(userID, storeID string, requiredRole entities.StoreRole) (bool, error) {
+	role, err := r.GetUserRole(userID, storeID)
+	if err != nil {
+		return false, nil
+	}
+	return role.AtLeast(requiredRole), nil
+}
+
+func (r *fakeRoleRepo) IsStoreOwner(userID, storeID string) (bool, error) {
+	role, err := r.GetUserRole(userID, storeID)
+	if err != nil {
+		return false, nil
+	}
+	return role == entities.StoreRoleOwner, nil
+}
+
+func (r *fakeRoleRepo) TouchLastActive(userID, storeID string, at time.Time) error {
+	return nil
+}
+
+func (r *fakeRoleRepo) GetInactiveByStoreID(storeID string, since time.Time) ([]entities.UserStoreRole, error) {
+	return nil, errors.New("not implemented")
+}
+
+// newTestStoreService returns a store service over in-memory repositories. Stores have no
+// webhook URL, so member changes send no webhooks.
+func newTestStoreService() (*storeService, *fakeStoreRepo, *fakeRoleRepo) {
+	stores := newFakeStoreRepo()
+	roles := &fakeRoleRepo{}
+	service := &storeService{
+		storeRepo: stores,
+		roleRepo:  roles,
+		config: &config.StoreConfig{
+			InvitationTTL:     7 * 24 * time.Hour,
+			MaxInvitationDays: 30,
+		},
+	}
+	return service, stores, roles
+}
+
+// addStore creates a store with default settings and a member for each role given
+func addStore(t *testing.T, stores *fakeStoreRepo, roles *fakeRoleRepo, slug string, members map[string]entities.StoreRole) string {
+	t.Helper()
+
+	store := &entities.Store{Name: slug, Slug: slug, IsActive: true, Settings: entities.GetDefaultStoreSettings()}
+	if err := stores.Create(store); err != nil {
+		t.Fatalf("create store %s: %v", slug, err)
+	}
+	for userID, role := range members {
+		if err := roles.Create(&entities.UserStoreRole{UserID: userID, StoreID: store.ID, Role: role, IsActive: true}); err != nil {
+			t.Fatalf("add %s to %s: %v", userID, slug, err)
+		}
+	}
+	return store.ID
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+)
+
+// sendInvitationEmail emails the invitee an accept link, retrying with linear backoff.
+// Failures are only logged: the invitation already exists and its token is in the API response.
+func (s *storeService) sendInvitationEmail(invitation entities.StoreInvitation) {
+	storeName := "a store"
+	if store, err := s.storeRepo.GetByID(invitation.StoreID); err == nil {
+		storeName = store.Name
+	}
+
+	link := s.config.InvitationURL + "?token=" + url.QueryEscape(invitation.Token)
+	subject := fmt.Sprintf("You're invited to join %s", storeName)
+	body := fmt.Sprintf(
+		"You have been invited to join %s as %s.\n\nAccept the invitation here:\n%s\n\nThis invitation expires on %s.\n",
+		storeName, invitation.Role, link, invitation.ExpiresAt.Format(time.RFC1123),
+	)
+
+	for attempt := 0; attempt <= s.config.InvitationEmailRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
+		err := s.mailer.Send(invitation.Email, subject, body)
+		if err == nil {
+			return
+		}
+		log.Printf("Invitation email to %s failed (attempt %d/%d): %v", invitation.Email, attempt+1, s.config.InvitationEmailRetries+1, err)
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+// GetStoreDashboard summarizes the store's active products. When the product service cannot be
+// reached the dashboard is still returned, with ProductStatsAvailable false and zeroed stats.
+func (s *storeService) GetStoreDashboard(storeID, userID string) (*dto.StoreDashboardResponse, error) {
+	role, err := s.roleRepo.GetUserRole(userID, storeID)
+	if err != nil {
+		return nil, services.NewError(services.ErrForbidden, "access denied")
+	}
+
+	if !entities.GetPermissions(role).CanViewAnalytics {
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to view store dashboard")
+	}
+
+	dashboard := &dto.StoreDashboardResponse{
+		StoreID:           storeID,
+		LowStockThreshold: s.config.LowStockThreshold,
+	}
+
+	products, err := s.productService.GetStoreProducts(context.Background(), storeID)
+	if err != nil {
+		log.Printf("Product stats unavailable for store %s dashboard: %v", storeID, err)
+		return dashboard, nil
+	}
+
+	dashboard.ProductStatsAvailable = true
+	dashboard.ProductCount = len(products)
+	for _, product := range products {
+		if product.Stock <= s.config.LowStockThreshold {
+			dashboard.LowStockCount++
+		}
+		dashboard.TotalInventoryValue += product.Price * float64(product.Stock)
+	}
+
+	return dashboard, nil
+}
@@ -5,52 +5,80 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/config"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/infrastructure/external"
 	repoImpl "github.com/tasiuskenways/scalable-ecommerce/store-service/internal/infrastructure/repositories"
+	"gorm.io/gorm"
 )
 
 type storeService struct {
 	storeRepo      repositories.StoreRepository
 	roleRepo       repositories.UserStoreRoleRepository
 	invitationRepo repositories.StoreInvitationRepository
+	webhookRepo    repositories.WebhookDeliveryRepository
+	mailer         services.Mailer
+	webhookSender  services.WebhookSender
+	productService *external.ProductServiceClient
+	storage        services.FileStorage
+	rateLimiter    services.RateLimiter
+	config         *config.StoreConfig
+	uploads        *config.UploadConfig
 }
 
 func NewStoreService(
 	storeRepo repositories.StoreRepository,
 	roleRepo repositories.UserStoreRoleRepository,
 	invitationRepo repositories.StoreInvitationRepository,
+	webhookRepo repositories.WebhookDeliveryRepository,
+	mailer services.Mailer,
+	webhookSender services.WebhookSender,
+	productService *external.ProductServiceClient,
+	storage services.FileStorage,
+	rateLimiter services.RateLimiter,
+	config *config.StoreConfig,
+	uploads *config.UploadConfig,
 ) services.StoreService {
 	return &storeService{
 		storeRepo:      storeRepo,
 		roleRepo:       roleRepo,
 		invitationRepo: invitationRepo,
+		webhookRepo:    webhookRepo,
+		mailer:         mailer,
+		webhookSender:  webhookSender,
+		productService: productService,
+		storage:        storage,
+		rateLimiter:    rateLimiter,
+		config:         config,
+		uploads:        uploads,
 	}
 }
 
-func (s *storeService) CreateStore(userID string, req dto.CreateStoreRequest) (*dto.StoreResponse, error) {
-	// Validate and clean slug
-	slug := s.generateSlug(req.Slug)
-
-	// Check if slug exists
-	exists, err := s.storeRepo.SlugExists(slug)
+func (s *storeService) CreateStore(userID, userEmail string, req dto.CreateStoreRequest) (*dto.StoreResponse, error) {
+	slug, err := s.resolveSlug(req.Slug, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to check slug existence: %w", err)
-	}
-	if exists {
-		return nil, errors.New("store slug already exists")
+		return nil, err
 	}
 
 	// Create store with default settings if none provided
-	settings := req.Settings
-	if settings == (entities.StoreSettings{}) {
-		settings = entities.GetDefaultStoreSettings()
+	settings := entities.GetDefaultStoreSettings()
+	var unknownSettingsKeys []string
+	if req.Settings != nil {
+		settings = req.Settings.MergeInto(settings)
+		unknownSettingsKeys = req.Settings.UnknownKeys
+	}
+
+	if err := s.validateSettings(settings, unknownSettingsKeys); err != nil {
+		return nil, err
 	}
 
 	store := &entities.Store{
@@ -72,6 +100,9 @@ func (s *storeService) CreateStore(userID string, req dto.CreateStoreRequest) (*
 	}
 
 	if err := s.storeRepo.Create(store); err != nil {
+		if errors.Is(err, repoImpl.ErrStoreSlugExists) {
+			return nil, errSlugTaken
+		}
 		return nil, fmt.Errorf("failed to create store: %w", err)
 	}
 
@@ -79,6 +110,7 @@ func (s *storeService) CreateStore(userID string, req dto.CreateStoreRequest) (*
 	ownerRole := &entities.UserStoreRole{
 		UserID:   userID,
 		StoreID:  store.ID,
+		Email:    userEmail,
 		Role:     entities.StoreRoleOwner,
 		IsActive: true,
 	}
@@ -104,7 +136,7 @@ func (s *storeService) GetStore(storeID, userID string) (*dto.StoreResponse, err
 	role, err := s.roleRepo.GetUserRole(userID, storeID)
 	if err != nil {
 		// User doesn't have access to this store
-		return nil, errors.New("store not found or access denied")
+		return nil, services.NewError(services.ErrNotFound, "store not found or access denied")
 	}
 
 	return s.mapStoreToResponse(store, &role), nil
@@ -123,17 +155,18 @@ func (s *storeService) GetStoreBySlug(slug, userID string) (*dto.StoreResponse,
 	role, err := s.roleRepo.GetUserRole(userID, store.ID)
 	if err != nil {
 		// User doesn't have access to this store
-		return nil, errors.New("store not found or access denied")
+		return nil, services.NewError(services.ErrNotFound, "store not found or access denied")
 	}
 
 	return s.mapStoreToResponse(store, &role), nil
 }
 
-func (s *storeService) GetUserStores(userID string, page, perPage int) (*dto.StoreListResponse, error) {
+func (s *storeService) GetUserStores(userID string, role entities.StoreRole, page, perPage int) (*dto.StoreListResponse, error) {
 	offset := (page - 1) * perPage
 
 	stores, total, err := s.storeRepo.GetStoresByFilter(repositories.StoreFilter{
 		UserID: userID,
+		Role:   role,
 		Limit:  perPage,
 		Offset: offset,
 	})
@@ -141,10 +174,19 @@ func (s *storeService) GetUserStores(userID string, page, perPage int) (*dto.Sto
 		return nil, fmt.Errorf("failed to get user stores: %w", err)
 	}
 
+	storeIDs := make([]string, len(stores))
+	for i, store := range stores {
+		storeIDs[i] = store.ID
+	}
+
+	roles, err := s.roleRepo.GetUserRolesForStores(userID, storeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user store roles: %w", err)
+	}
+
 	storeResponses := make([]dto.StoreResponse, len(stores))
 	for i, store := range stores {
-		// Get user role for each store
-		role, _ := s.roleRepo.GetUserRole(userID, store.ID)
+		role := roles[store.ID]
 		storeResponses[i] = *s.mapStoreToResponse(&store, &role)
 	}
 
@@ -163,92 +205,312 @@ func (s *storeService) InviteMember(storeID, inviterID string, req dto.InviteMem
 	// Check if inviter has permission to invite members (OWNER or ADMIN only)
 	inviterRole, err := s.roleRepo.GetUserRole(inviterID, storeID)
 	if err != nil {
-		return nil, errors.New("access denied")
+		return nil, services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	permissions := entities.GetPermissions(inviterRole)
 	if !permissions.CanInviteMembers {
-		return nil, errors.New("insufficient permissions to invite members")
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to invite members")
 	}
 
-	// Check if invitation already exists
-	existing, err := s.invitationRepo.GetPendingByEmailAndStore(req.Email, storeID)
-	if err == nil && existing != nil {
-		return nil, errors.New("invitation already sent to this email")
+	expiresAt, err := s.invitationExpiry(req.ExpiresInDays)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate invitation token
-	token, err := s.generateToken()
+	invitation, err := s.createInvitation(storeID, inviterID, req.Email, req.Role, expiresAt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+		return nil, err
 	}
 
-	// Create invitation
-	invitation := &entities.StoreInvitation{
-		StoreID:   storeID,
-		InviterID: inviterID,
-		Email:     req.Email,
-		Role:      req.Role,
-		Token:     token,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 days
+	return s.mapInvitationToResponse(invitation), nil
+}
+
+// maxBulkInvitations caps how many emails a single bulk invite may list
+const maxBulkInvitations = 50
+
+// InviteMembers invites every email in one call with the default expiry. Emails that are
+// already members, already invited or repeated in the list are skipped and reported in
+// the per-email results rather than failing the whole request.
+func (s *storeService) InviteMembers(storeID, inviterID string, emails []string, role entities.StoreRole) (*dto.BulkInviteResponse, error) {
+	if len(emails) == 0 {
+		return nil, &services.ValidationError{Errors: []string{"emails must list at least one address"}}
+	}
+	if len(emails) > maxBulkInvitations {
+		return nil, &services.ValidationError{Errors: []string{fmt.Sprintf("emails must list at most %d addresses", maxBulkInvitations)}}
 	}
 
-	if err := s.invitationRepo.Create(invitation); err != nil {
-		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	inviterRole, err := s.roleRepo.GetUserRole(inviterID, storeID)
+	if err != nil {
+		return nil, services.NewError(services.ErrForbidden, "access denied")
+	}
+	if !entities.GetPermissions(inviterRole).CanInviteMembers {
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to invite members")
 	}
 
-	return s.mapInvitationToResponse(invitation), nil
+	expiresAt, err := s.invitationExpiry(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.BulkInviteResponse{Results: make([]dto.BulkInviteResult, 0, len(emails))}
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		result := dto.BulkInviteResult{Email: email}
+
+		key := strings.ToLower(email)
+		if seen[key] {
+			result.Status = dto.BulkInviteDuplicate
+			response.Results = append(response.Results, result)
+			continue
+		}
+		seen[key] = true
+
+		invitation, err := s.createInvitation(storeID, inviterID, email, role, expiresAt)
+		switch {
+		case err == nil:
+			result.Status = dto.BulkInviteInvited
+			result.Invitation = s.mapInvitationToResponse(invitation)
+			response.Invited++
+		case errors.Is(err, errAlreadyMember):
+			result.Status = dto.BulkInviteAlreadyMember
+		case errors.Is(err, errAlreadyInvited):
+			result.Status = dto.BulkInviteAlreadyInvited
+		case errors.Is(err, services.ErrRateLimited):
+			result.Status = dto.BulkInviteRateLimited
+			result.Error = err.Error()
+		default:
+			result.Status = dto.BulkInviteFailed
+			result.Error = err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// PreviewInvitation lets someone holding an invitation link see the store and role before
+// they register; they accept it with AcceptInvitation once signed in
+func (s *storeService) PreviewInvitation(token string) (*dto.InvitationPreviewResponse, error) {
+	invitation, err := s.invitationRepo.GetByToken(token)
+	if err != nil || invitation.Store == nil {
+		return nil, services.NewError(services.ErrNotFound, "invitation not found")
+	}
+
+	// Expiry is not written back to pending invitations, so report it here
+	status := invitation.Status
+	if status == entities.InvitationStatusPending && invitation.IsExpired() {
+		status = entities.InvitationStatusExpired
+	}
+
+	return &dto.InvitationPreviewResponse{
+		StoreName: invitation.Store.Name,
+		StoreSlug: invitation.Store.Slug,
+		StoreLogo: invitation.Store.Logo,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    status,
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CanAccept: invitation.CanAccept(),
+	}, nil
 }
 
 func (s *storeService) AcceptInvitation(userID string, req dto.AcceptInvitationRequest) error {
 	invitation, err := s.invitationRepo.GetByToken(req.Token)
 	if err != nil {
-		return errors.New("invalid invitation token")
+		return services.NewError(services.ErrInvalid, "invalid invitation token")
 	}
 
 	if !invitation.CanAccept() {
-		return errors.New("invitation has expired or is no longer valid")
+		return services.NewError(services.ErrInvalid, "invitation has expired or is no longer valid")
 	}
 
 	// Check if user is already a member
 	existing, err := s.roleRepo.GetByUserAndStore(userID, invitation.StoreID)
 	if err == nil && existing != nil {
-		return errors.New("user is already a member of this store")
+		return services.NewError(services.ErrConflict, "user is already a member of this store")
 	}
 
 	// Create user store role
 	role := &entities.UserStoreRole{
 		UserID:   userID,
 		StoreID:  invitation.StoreID,
+		Email:    invitation.Email,
 		Role:     invitation.Role,
 		IsActive: true,
 	}
 
-	if err := s.roleRepo.Create(role); err != nil {
-		return fmt.Errorf("failed to add user to store: %w", err)
-	}
-
 	// Update invitation status
 	now := time.Now()
 	invitation.Status = entities.InvitationStatusAccepted
 	invitation.InviteeID = &userID
 	invitation.AcceptedAt = &now
 
-	if err := s.invitationRepo.Update(invitation); err != nil {
-		return fmt.Errorf("failed to update invitation: %w", err)
+	// A concurrent accept can pass the check above; the unique membership index catches it here
+	if err := s.invitationRepo.Accept(invitation, role); err != nil {
+		if errors.Is(err, repoImpl.ErrAlreadyMember) {
+			return services.NewError(services.ErrConflict, "user is already a member of this store")
+		}
+		return fmt.Errorf("failed to accept invitation: %w", err)
 	}
 
+	go s.notifyMemberChange(invitation.StoreID, entities.WebhookEventMemberJoined, memberWebhookData{
+		UserID: userID,
+		Role:   invitation.Role,
+	})
+
 	return nil
 }
 
 // Helper methods
-func (s *storeService) generateSlug(input string) string {
+
+var (
+	errAlreadyMember  = services.NewError(services.ErrConflict, "user is already a member")
+	errAlreadyInvited = services.NewError(services.ErrConflict, "invitation already sent to this email")
+)
+
+// allowInvitation counts one invitation against the store's rate limit. The limiter failing
+// lets the invitation through rather than blocking invites while Redis is unavailable.
+func (s *storeService) allowInvitation(storeID string) error {
+	if s.config.InvitationRateLimit <= 0 || s.config.InvitationRateWindow <= 0 {
+		return nil
+	}
+
+	allowed, err := s.rateLimiter.Allow("invitations:"+storeID, s.config.InvitationRateLimit, s.config.InvitationRateWindow)
+	if err != nil {
+		log.Printf("Invitation rate limit check for store %s failed: %v", storeID, err)
+		return nil
+	}
+	if !allowed {
+		return services.NewError(services.ErrRateLimited, fmt.Sprintf(
+			"invitation limit reached: a store can send at most %d invitations every %s",
+			s.config.InvitationRateLimit, s.config.InvitationRateWindow,
+		))
+	}
+	return nil
+}
+
+// createInvitation records a pending invitation for email and emails it in the background.
+// It fails with errAlreadyMember or errAlreadyInvited when the email needs no new invitation,
+// and with an ErrRateLimited error once the store has sent too many.
+func (s *storeService) createInvitation(storeID, inviterID, email string, role entities.StoreRole, expiresAt time.Time) (*entities.StoreInvitation, error) {
+	// Check if the email already belongs to a member
+	member, err := s.roleRepo.GetByEmailAndStore(email, storeID)
+	if err == nil && member != nil {
+		return nil, errAlreadyMember
+	}
+
+	// Check if invitation already exists
+	existing, err := s.invitationRepo.GetPendingByEmailAndStore(email, storeID)
+	if err == nil && existing != nil {
+		return nil, errAlreadyInvited
+	}
+
+	if err := s.allowInvitation(storeID); err != nil {
+		return nil, err
+	}
+
+	// Generate invitation token
+	token, err := s.generateToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+
+	// Create invitation
+	invitation := &entities.StoreInvitation{
+		StoreID:   storeID,
+		InviterID: inviterID,
+		Email:     email,
+		Role:      role,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	// Delivery happens in the background so a slow or failing mail server never blocks the invite
+	go s.sendInvitationEmail(*invitation)
+
+	return invitation, nil
+}
+
+// invitationExpiry returns when a new or resent invitation expires, applying the
+// optional per-invitation override in days
+func (s *storeService) invitationExpiry(expiresInDays *int) (time.Time, error) {
+	if expiresInDays == nil {
+		return time.Now().Add(s.config.InvitationTTL), nil
+	}
+
+	days := *expiresInDays
+	if days < 1 || days > s.config.MaxInvitationDays {
+		return time.Time{}, &services.ValidationError{
+			Errors: []string{fmt.Sprintf("expires_in_days must be between 1 and %d", s.config.MaxInvitationDays)},
+		}
+	}
+
+	return time.Now().AddDate(0, 0, days), nil
+}
+
+// reservedSlugs cannot be used as store slugs because they clash with routes or would
+// mislead users
+var reservedSlugs = []string{"admin", "api", "search", "internal", "invitations", "new", "settings", "slug"}
+
+var errSlugTaken = services.NewError(services.ErrConflict, "store slug already exists")
+
+var errStoreModified = services.NewError(services.ErrConflict, "store was modified by another request; reload it and retry")
+
+// resolveSlug normalizes input into a slug and checks it is not used by another store
+// than excludeID
+func (s *storeService) resolveSlug(input, excludeID string) (string, error) {
+	slug, err := s.generateSlug(input)
+	if err != nil {
+		return "", err
+	}
+
+	exists, err := s.storeRepo.SlugExists(slug, excludeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check slug existence: %w", err)
+	}
+	if exists {
+		return "", errSlugTaken
+	}
+
+	return slug, nil
+}
+
+// minSlugLength is the shortest slug kept as is; shorter ones get a random suffix
+const minSlugLength = 3
+
+// generateSlug lowercases input and reduces it to letters, digits and single hyphens.
+// A slug shorter than minSlugLength is padded with a random suffix. Input with no usable
+// characters, such as only symbols or emoji, and reserved words are rejected.
+func (s *storeService) generateSlug(input string) (string, error) {
 	// Convert to lowercase and replace spaces with hyphens
 	slug := strings.ToLower(strings.TrimSpace(input))
 	slug = regexp.MustCompile(`[^a-z0-9\-]`).ReplaceAllString(slug, "-")
 	slug = regexp.MustCompile(`-+`).ReplaceAllString(slug, "-")
 	slug = strings.Trim(slug, "-")
-	return slug
+
+	if slug == "" {
+		return "", &services.ValidationError{Errors: []string{"slug must contain at least one letter or digit"}}
+	}
+
+	if slices.Contains(reservedSlugs, slug) {
+		return "", &services.ValidationError{Errors: []string{fmt.Sprintf("slug %q is reserved", slug)}}
+	}
+
+	if len(slug) < minSlugLength {
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", fmt.Errorf("failed to generate slug suffix: %w", err)
+		}
+		slug += "-" + hex.EncodeToString(suffix)
+	}
+
+	return slug, nil
 }
 
 func (s *storeService) generateToken() (string, error) {
@@ -277,6 +539,8 @@ func (s *storeService) mapStoreToResponse(store *entities.Store, userRole *entit
 		PostalCode:  store.PostalCode,
 		IsActive:    store.IsActive,
 		Settings:    store.Settings,
+		WebhookURL:  store.WebhookURL,
+		Version:     store.Version,
 		CreatedAt:   store.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   store.UpdatedAt.Format(time.RFC3339),
 	}
@@ -294,13 +558,13 @@ func (s *storeService) UpdateStore(storeID, userID string, req dto.UpdateStoreRe
 	// Get user's role
 	userRole, err := s.roleRepo.GetUserRole(userID, storeID)
 	if err != nil {
-		return nil, errors.New("access denied")
+		return nil, services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	// Check if user has permission to edit store settings
 	permissions := entities.GetPermissions(userRole)
 	if !permissions.CanEditStoreSettings {
-		return nil, errors.New("insufficient permissions to update store")
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to update store")
 	}
 
 	// Get existing store
@@ -308,11 +572,21 @@ func (s *storeService) UpdateStore(storeID, userID string, req dto.UpdateStoreRe
 	if err != nil {
 		return nil, fmt.Errorf("failed to get store: %w", err)
 	}
+	if req.Version != nil && *req.Version != store.Version {
+		return nil, errStoreModified
+	}
 
 	// Update fields if provided
 	if req.Name != nil {
 		store.Name = *req.Name
 	}
+	if req.Slug != nil {
+		slug, err := s.resolveSlug(*req.Slug, store.ID)
+		if err != nil {
+			return nil, err
+		}
+		store.Slug = slug
+	}
 	if req.Description != nil {
 		store.Description = *req.Description
 	}
@@ -349,11 +623,37 @@ func (s *storeService) UpdateStore(storeID, userID string, req dto.UpdateStoreRe
 	if req.IsActive != nil {
 		store.IsActive = *req.IsActive
 	}
+	if req.WebhookURL != nil {
+		if *req.WebhookURL != "" {
+			if err := external.ValidateWebhookURL(*req.WebhookURL, s.config.WebhookAllowPrivate); err != nil {
+				return nil, &services.ValidationError{Errors: []string{fmt.Sprintf("webhook_url is not allowed: %v", err)}}
+			}
+		}
+		store.WebhookURL = *req.WebhookURL
+	}
+	if req.WebhookSecret != nil {
+		store.WebhookSecret = *req.WebhookSecret
+	}
+	// Webhooks are only ever sent signed, so a URL needs a secret to go with it
+	if store.WebhookURL != "" && store.WebhookSecret == "" {
+		return nil, &services.ValidationError{Errors: []string{"webhook_secret is required when webhook_url is set"}}
+	}
 	if req.Settings != nil {
-		store.Settings = *req.Settings
+		settings := req.Settings.MergeInto(store.Settings)
+		if err := s.validateSettings(settings, req.Settings.UnknownKeys); err != nil {
+			return nil, err
+		}
+		store.Settings = settings
 	}
 
+	// The unique slug index settles a race with another store claiming the same slug
 	if err := s.storeRepo.Update(store); err != nil {
+		if errors.Is(err, repoImpl.ErrStoreSlugExists) {
+			return nil, errSlugTaken
+		}
+		if errors.Is(err, repoImpl.ErrStoreVersionConflict) {
+			return nil, errStoreModified
+		}
 		return nil, fmt.Errorf("failed to update store: %w", err)
 	}
 
@@ -366,7 +666,7 @@ func (s *storeService) DeleteStore(storeID, userID string) error {
 	// Only store owner can delete store
 	isOwner, err := s.roleRepo.IsStoreOwner(userID, storeID)
 	if err != nil || !isOwner {
-		return errors.New("only store owner can delete the store")
+		return services.NewError(services.ErrForbidden, "only store owner can delete the store")
 	}
 
 	return s.storeRepo.Delete(storeID)
@@ -376,7 +676,7 @@ func (s *storeService) GetStoreMembers(storeID, userID string) ([]dto.StoreMembe
 	// Check if user has access to store
 	_, err := s.roleRepo.GetUserRole(userID, storeID)
 	if err != nil {
-		return nil, errors.New("access denied")
+		return nil, services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	members, err := s.roleRepo.GetByStoreID(storeID)
@@ -384,6 +684,36 @@ func (s *storeService) GetStoreMembers(storeID, userID string) ([]dto.StoreMembe
 		return nil, fmt.Errorf("failed to get store members: %w", err)
 	}
 
+	return mapMembersToResponse(members), nil
+}
+
+// maxInactiveDays bounds the inactivity window accepted by GetInactiveMembers
+const maxInactiveDays = 3650
+
+// GetInactiveMembers lists members who have not acted on the store for at least days days.
+// Members who never acted count from when they joined.
+func (s *storeService) GetInactiveMembers(storeID, userID string, days int) ([]dto.StoreMemberResponse, error) {
+	role, err := s.roleRepo.GetUserRole(userID, storeID)
+	if err != nil {
+		return nil, services.NewError(services.ErrForbidden, "access denied")
+	}
+	if !entities.GetPermissions(role).CanManageMembers {
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to view member activity")
+	}
+
+	if days < 1 || days > maxInactiveDays {
+		return nil, &services.ValidationError{Errors: []string{fmt.Sprintf("days must be between 1 and %d", maxInactiveDays)}}
+	}
+
+	members, err := s.roleRepo.GetInactiveByStoreID(storeID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inactive members: %w", err)
+	}
+
+	return mapMembersToResponse(members), nil
+}
+
+func mapMembersToResponse(members []entities.UserStoreRole) []dto.StoreMemberResponse {
 	responses := make([]dto.StoreMemberResponse, len(members))
 	for i, member := range members {
 		responses[i] = dto.StoreMemberResponse{
@@ -393,103 +723,194 @@ func (s *storeService) GetStoreMembers(storeID, userID string) ([]dto.StoreMembe
 			IsActive: member.IsActive,
 			JoinedAt: member.JoinedAt.Format(time.RFC3339),
 		}
+		if member.LastActiveAt != nil {
+			lastActiveAt := member.LastActiveAt.Format(time.RFC3339)
+			responses[i].LastActiveAt = &lastActiveAt
+		}
 	}
-
-	return responses, nil
+	return responses
 }
 
 func (s *storeService) UpdateMemberRole(storeID, memberUserID, requesterID string, req dto.UpdateMemberRoleRequest) error {
 	// Get requester's role
 	requesterRole, err := s.roleRepo.GetUserRole(requesterID, storeID)
 	if err != nil {
-		return errors.New("access denied")
+		return services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	// Check if requester has permission to manage members
 	permissions := entities.GetPermissions(requesterRole)
 	if !permissions.CanManageMembers {
-		return errors.New("insufficient permissions to update member role")
+		return services.NewError(services.ErrForbidden, "insufficient permissions to update member role")
 	}
 
 	// Get current member role
 	memberRole, err := s.roleRepo.GetByUserAndStore(memberUserID, storeID)
 	if err != nil {
-		return errors.New("member not found")
+		return services.NewError(services.ErrNotFound, "member not found")
 	}
 
 	// Cannot change owner's role
 	if memberRole.Role == entities.StoreRoleOwner {
-		return errors.New("cannot change store owner's role")
+		return services.NewError(services.ErrForbidden, "cannot change store owner's role")
 	}
 
 	// Cannot promote someone to owner (ownership transfer is a different operation)
 	if req.Role == entities.StoreRoleOwner {
-		return errors.New("cannot promote member to owner")
+		return services.NewError(services.ErrForbidden, "cannot promote member to owner")
 	}
 
 	// Requester can only manage users with lower roles
-	if !requesterRole.HasPermission(memberRole.Role) {
-		return errors.New("cannot modify role of user with equal or higher permissions")
+	if !requesterRole.CanManage(memberRole.Role) {
+		return services.NewError(services.ErrForbidden, "cannot modify role of user with equal or higher permissions")
 	}
 
-	// Requester cannot assign a role higher than their own
-	if !requesterRole.HasPermission(req.Role) && requesterRole != req.Role {
-		return errors.New("cannot assign role higher than your own")
+	// Requester can only assign roles strictly below their own
+	if !requesterRole.CanManage(req.Role) {
+		return services.NewError(services.ErrForbidden, "cannot assign role equal to or higher than your own")
 	}
 
 	// Update role
+	previousRole := memberRole.Role
 	memberRole.Role = req.Role
-	return s.roleRepo.Update(memberRole)
+	if err := s.roleRepo.Update(memberRole); err != nil {
+		return err
+	}
+
+	go s.notifyMemberChange(storeID, entities.WebhookEventMemberRoleUpdated, memberWebhookData{
+		UserID:       memberUserID,
+		Role:         req.Role,
+		PreviousRole: previousRole,
+		ActorID:      requesterID,
+	})
+
+	return nil
 }
 
 func (s *storeService) RemoveMember(storeID, memberUserID, requesterID string) error {
 	// Get requester's role
 	requesterRole, err := s.roleRepo.GetUserRole(requesterID, storeID)
 	if err != nil {
-		return errors.New("access denied")
+		return services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	// Check if requester has permission to manage members
 	permissions := entities.GetPermissions(requesterRole)
 	if !permissions.CanManageMembers {
-		return errors.New("insufficient permissions to remove member")
+		return services.NewError(services.ErrForbidden, "insufficient permissions to remove member")
 	}
 
-	// Get member to be removed role
-	memberRole, err := s.roleRepo.GetUserRole(memberUserID, storeID)
+	// Get member to be removed role; suspended members can be removed too
+	member, err := s.roleRepo.GetMembershipIncludingSuspended(memberUserID, storeID)
 	if err != nil {
-		return errors.New("member not found")
+		return services.NewError(services.ErrNotFound, "member not found")
 	}
+	memberRole := member.Role
 
 	// Cannot remove store owner
 	if memberRole == entities.StoreRoleOwner {
-		return errors.New("cannot remove store owner")
+		return services.NewError(services.ErrForbidden, "cannot remove store owner")
 	}
 
 	// Cannot remove self
 	if memberUserID == requesterID {
-		return errors.New("cannot remove yourself from the store")
+		return services.NewError(services.ErrForbidden, "cannot remove yourself from the store")
 	}
 
 	// Requester can only remove users with lower roles
-	if !requesterRole.HasPermission(memberRole) {
-		return errors.New("cannot remove user with equal or higher permissions")
+	if !requesterRole.CanManage(memberRole) {
+		return services.NewError(services.ErrForbidden, "cannot remove user with equal or higher permissions")
+	}
+
+	if err := s.roleRepo.Delete(memberUserID, storeID); err != nil {
+		return err
 	}
 
-	return s.roleRepo.Delete(memberUserID, storeID)
+	go s.notifyMemberChange(storeID, entities.WebhookEventMemberRemoved, memberWebhookData{
+		UserID:       memberUserID,
+		PreviousRole: memberRole,
+		ActorID:      requesterID,
+	})
+
+	return nil
+}
+
+// SuspendMember revokes a member's access without deleting the membership, so it keeps its
+// history and can be restored with UnsuspendMember
+func (s *storeService) SuspendMember(storeID, memberUserID, requesterID string) error {
+	return s.setMemberActive(storeID, memberUserID, requesterID, false)
+}
+
+// UnsuspendMember restores the access of a suspended member with their previous role
+func (s *storeService) UnsuspendMember(storeID, memberUserID, requesterID string) error {
+	return s.setMemberActive(storeID, memberUserID, requesterID, true)
+}
+
+func (s *storeService) setMemberActive(storeID, memberUserID, requesterID string, active bool) error {
+	action := "suspend"
+	event := entities.WebhookEventMemberSuspended
+	if active {
+		action = "unsuspend"
+		event = entities.WebhookEventMemberUnsuspended
+	}
+
+	requesterRole, err := s.roleRepo.GetUserRole(requesterID, storeID)
+	if err != nil {
+		return services.NewError(services.ErrForbidden, "access denied")
+	}
+
+	if !entities.GetPermissions(requesterRole).CanManageMembers {
+		return services.NewError(services.ErrForbidden, "insufficient permissions to "+action+" member")
+	}
+
+	member, err := s.roleRepo.GetMembershipIncludingSuspended(memberUserID, storeID)
+	if err != nil {
+		return services.NewError(services.ErrNotFound, "member not found")
+	}
+
+	if member.Role == entities.StoreRoleOwner {
+		return services.NewError(services.ErrForbidden, "cannot "+action+" store owner")
+	}
+
+	if memberUserID == requesterID {
+		return services.NewError(services.ErrForbidden, "cannot "+action+" yourself")
+	}
+
+	if !requesterRole.CanManage(member.Role) {
+		return services.NewError(services.ErrForbidden, "cannot "+action+" user with equal or higher permissions")
+	}
+
+	if member.IsActive == active {
+		if active {
+			return services.NewError(services.ErrConflict, "member is not suspended")
+		}
+		return services.NewError(services.ErrConflict, "member is already suspended")
+	}
+
+	if err := s.roleRepo.SetActive(memberUserID, storeID, active); err != nil {
+		return fmt.Errorf("failed to %s member: %w", action, err)
+	}
+
+	go s.notifyMemberChange(storeID, event, memberWebhookData{
+		UserID:  memberUserID,
+		Role:    member.Role,
+		ActorID: requesterID,
+	})
+
+	return nil
 }
 
 func (s *storeService) GetStoreInvitations(storeID, userID string) ([]dto.StoreInvitationResponse, error) {
 	// Get user's role
 	userRole, err := s.roleRepo.GetUserRole(userID, storeID)
 	if err != nil {
-		return nil, errors.New("access denied")
+		return nil, services.NewError(services.ErrForbidden, "access denied")
 	}
 
 	// Check if user has permission to manage members (only admin/owner can view invitations)
 	permissions := entities.GetPermissions(userRole)
 	if !permissions.CanManageMembers {
-		return nil, errors.New("insufficient permissions to view invitations")
+		return nil, services.NewError(services.ErrForbidden, "insufficient permissions to view invitations")
 	}
 
 	invitations, err := s.invitationRepo.GetByStoreID(storeID)
@@ -531,3 +952,41 @@ func (s *storeService) mapInvitationToResponse(invitation *entities.StoreInvitat
 		InviteToken: &invitation.Token,
 	}
 }
+
+// GetMembership returns the user's role and permissions in the store, or ErrNotFound when
+// the user is not an active member
+func (s *storeService) GetMembership(storeID, userID string) (*dto.StoreMembershipResponse, error) {
+	role, err := s.roleRepo.GetUserRole(userID, storeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, services.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	return &dto.StoreMembershipResponse{
+		StoreID:     storeID,
+		UserID:      userID,
+		Role:        role,
+		Permissions: entities.GetPermissions(role),
+	}, nil
+}
+
+// GetInternalStore returns a store's settings regardless of the caller's membership, or
+// ErrNotFound when the store does not exist
+func (s *storeService) GetInternalStore(storeID string) (*dto.InternalStoreResponse, error) {
+	store, err := s.storeRepo.GetByID(storeID)
+	if err != nil {
+		if errors.Is(err, repoImpl.ErrStoreNotFound) {
+			return nil, services.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get store: %w", err)
+	}
+
+	return &dto.InternalStoreResponse{
+		ID:       store.ID,
+		Name:     store.Name,
+		IsActive: store.IsActive,
+		Settings: store.Settings,
+	}, nil
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+// settingsRequest decodes raw the way the handler does
+func settingsRequest(t *testing.T, raw string) *dto.StoreSettingsRequest {
+	t.Helper()
+
+	var settings dto.StoreSettingsRequest
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		t.Fatalf("decode settings %s: %v", raw, err)
+	}
+	return &settings
+}
+
+func TestCreateStoreWithoutSettingsUsesDefaults(t *testing.T) {
+	service, stores, _ := newTestStoreService()
+
+	response, err := service.CreateStore("owner", "owner@example.com", dto.CreateStoreRequest{Name: "Shop", Slug: "shop"})
+	if err != nil {
+		t.Fatalf("CreateStore: %v", err)
+	}
+
+	store, _ := stores.GetByID(response.ID)
+	defaults := entities.GetDefaultStoreSettings()
+	if store.Settings.Currency != defaults.Currency || store.Settings.MaxProducts != defaults.MaxProducts ||
+		!slices.Equal(store.Settings.PaymentMethods, defaults.PaymentMethods) {
+		t.Errorf("settings = %+v, want defaults %+v", store.Settings, defaults)
+	}
+}
+
+func TestCreateStoreMergesPartialSettingsOverDefaults(t *testing.T) {
+	service, stores, _ := newTestStoreService()
+
+	response, err := service.CreateStore("owner", "owner@example.com", dto.CreateStoreRequest{
+		Name:     "Shop",
+		Slug:     "shop",
+		Settings: settingsRequest(t, `{"currency": "EUR", "allow_public_listing": false}`),
+	})
+	if err != nil {
+		t.Fatalf("CreateStore: %v", err)
+	}
+
+	store, _ := stores.GetByID(response.ID)
+	defaults := entities.GetDefaultStoreSettings()
+	if store.Settings.Currency != "EUR" {
+		t.Errorf("currency = %q, want EUR", store.Settings.Currency)
+	}
+	// An explicit false must not be mistaken for "not provided"
+	if store.Settings.AllowPublicListing {
+		t.Error("allow_public_listing = true, want the provided false")
+	}
+	if store.Settings.Timezone != defaults.Timezone {
+		t.Errorf("timezone = %q, want default %q", store.Settings.Timezone, defaults.Timezone)
+	}
+	if store.Settings.MaxProducts != defaults.MaxProducts {
+		t.Errorf("max_products = %d, want default %d", store.Settings.MaxProducts, defaults.MaxProducts)
+	}
+	if !slices.Equal(store.Settings.PaymentMethods, defaults.PaymentMethods) {
+		t.Errorf("payment_methods = %v, want default %v", store.Settings.PaymentMethods, defaults.PaymentMethods)
+	}
+}
+
+func TestCreateStoreValidatesMergedSettings(t *testing.T) {
+	service, _, _ := newTestStoreService()
+
+	_, err := service.CreateStore("owner", "owner@example.com", dto.CreateStoreRequest{
+		Name:     "Shop",
+		Slug:     "shop",
+		Settings: settingsRequest(t, `{"max_products": 0}`),
+	})
+
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a validation error", err)
+	}
+}
+
+func TestUpdateStoreMergesPartialSettingsOverCurrent(t *testing.T) {
+	service, stores, roles := newTestStoreService()
+	storeID := addStore(t, stores, roles, "shop", map[string]entities.StoreRole{"owner": entities.StoreRoleOwner})
+
+	if _, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{
+		Settings: settingsRequest(t, `{"currency": "EUR", "max_products": 50}`),
+	}); err != nil {
+		t.Fatalf("first UpdateStore: %v", err)
+	}
+	if _, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{
+		Settings: settingsRequest(t, `{"timezone": "Europe/Berlin"}`),
+	}); err != nil {
+		t.Fatalf("second UpdateStore: %v", err)
+	}
+
+	store, _ := stores.GetByID(storeID)
+	if store.Settings.Currency != "EUR" || store.Settings.MaxProducts != 50 {
+		t.Errorf("settings = %+v, earlier partial update was lost", store.Settings)
+	}
+	if store.Settings.Timezone != "Europe/Berlin" {
+		t.Errorf("timezone = %q, want Europe/Berlin", store.Settings.Timezone)
+	}
+}
+
+func TestUpdateMemberRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		requester entities.StoreRole
+		member    entities.StoreRole
+		assign    entities.StoreRole
+		allowed   bool
+	}{
+		{name: "owner assigns manager", requester: entities.StoreRoleOwner, member: entities.StoreRoleMember, assign: entities.StoreRoleManager, allowed: true},
+		{name: "owner assigns admin", requester: entities.StoreRoleOwner, member: entities.StoreRoleManager, assign: entities.StoreRoleAdmin, allowed: true},
+		{name: "owner assigns owner", requester: entities.StoreRoleOwner, member: entities.StoreRoleAdmin, assign: entities.StoreRoleOwner},
+		{name: "admin assigns manager", requester: entities.StoreRoleAdmin, member: entities.StoreRoleMember, assign: entities.StoreRoleManager, allowed: true},
+		{name: "admin assigns admin", requester: entities.StoreRoleAdmin, member: entities.StoreRoleManager, assign: entities.StoreRoleAdmin},
+		{name: "admin demotes admin", requester: entities.StoreRoleAdmin, member: entities.StoreRoleAdmin, assign: entities.StoreRoleMember},
+		{name: "manager assigns admin", requester: entities.StoreRoleManager, member: entities.StoreRoleMember, assign: entities.StoreRoleAdmin},
+		{name: "manager assigns member", requester: entities.StoreRoleManager, member: entities.StoreRoleMember, assign: entities.StoreRoleMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, stores, roles := newTestStoreService()
+			members := map[string]entities.StoreRole{"requester": tt.requester, "member": tt.member}
+			if tt.requester != entities.StoreRoleOwner {
+				members["owner"] = entities.StoreRoleOwner
+			}
+			storeID := addStore(t, stores, roles, "shop", members)
+
+			err := service.UpdateMemberRole(storeID, "member", "requester", dto.UpdateMemberRoleRequest{Role: tt.assign})
+
+			role, _ := roles.GetUserRole("member", storeID)
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("UpdateMemberRole: %v", err)
+				}
+				if role != tt.assign {
+					t.Errorf("role = %s, want %s", role, tt.assign)
+				}
+				return
+			}
+			if !errors.Is(err, services.ErrForbidden) {
+				t.Fatalf("err = %v, want forbidden", err)
+			}
+			if role != tt.member {
+				t.Errorf("role = %s, want it unchanged at %s", role, tt.member)
+			}
+		})
+	}
+}
+
+func TestInvitationExpiryBounds(t *testing.T) {
+	service, _, _ := newTestStoreService()
+
+	for _, days := range []int{0, -1, service.config.MaxInvitationDays + 1, 1 << 30} {
+		days := days
+		if _, err := service.invitationExpiry(&days); err == nil {
+			t.Errorf("expires_in_days = %d accepted, want a validation error", days)
+		} else {
+			var validationErr *services.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("expires_in_days = %d: err = %v, want a validation error", days, err)
+			}
+		}
+	}
+
+	for _, days := range []int{1, service.config.MaxInvitationDays} {
+		days := days
+		expiresAt, err := service.invitationExpiry(&days)
+		if err != nil {
+			t.Errorf("expires_in_days = %d: %v", days, err)
+			continue
+		}
+		if want := time.Now().AddDate(0, 0, days); expiresAt.Sub(want).Abs() > time.Minute {
+			t.Errorf("expires_in_days = %d: expires at %s, want about %s", days, expiresAt, want)
+		}
+	}
+
+	expiresAt, err := service.invitationExpiry(nil)
+	if err != nil {
+		t.Fatalf("default expiry: %v", err)
+	}
+	if want := time.Now().Add(service.config.InvitationTTL); expiresAt.Sub(want).Abs() > time.Minute {
+		t.Errorf("default expiry = %s, want about %s", expiresAt, want)
+	}
+}
+
+// recordingWebhookSender counts deliveries instead of sending them
+type recordingWebhookSender struct {
+	posts int
+}
+
+func (s *recordingWebhookSender) Post(url string, event string, body []byte, signature string) (int, error) {
+	s.posts++
+	return 204, nil
+}
+
+type discardWebhookRepo struct{}
+
+func (discardWebhookRepo) Create(delivery *entities.WebhookDelivery) error { return nil }
+func (discardWebhookRepo) Update(delivery *entities.WebhookDelivery) error { return nil }
+
+func TestUpdateStoreRequiresWebhookSecret(t *testing.T) {
+	service, stores, roles := newTestStoreService()
+	storeID := addStore(t, stores, roles, "shop", map[string]entities.StoreRole{"owner": entities.StoreRoleOwner})
+	url := "https://hooks.example.com/store"
+
+	_, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{WebhookURL: &url})
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a validation error for the missing secret", err)
+	}
+
+	secret := "0123456789abcdef"
+	if _, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{WebhookURL: &url, WebhookSecret: &secret}); err != nil {
+		t.Fatalf("UpdateStore with secret: %v", err)
+	}
+}
+
+func TestUpdateStoreRejectsInternalWebhookURL(t *testing.T) {
+	service, stores, roles := newTestStoreService()
+	storeID := addStore(t, stores, roles, "shop", map[string]entities.StoreRole{"owner": entities.StoreRoleOwner})
+	secret := "0123456789abcdef"
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:3004/api/products"} {
+		url := url
+		_, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{WebhookURL: &url, WebhookSecret: &secret})
+		var validationErr *services.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("webhook_url %s: err = %v, want a validation error", url, err)
+		}
+	}
+}
+
+func TestNotifyMemberChangeNeedsSecret(t *testing.T) {
+	service, stores, roles := newTestStoreService()
+	sender := &recordingWebhookSender{}
+	service.webhookSender = sender
+	service.webhookRepo = discardWebhookRepo{}
+	storeID := addStore(t, stores, roles, "shop", nil)
+
+	store, _ := stores.GetByID(storeID)
+	store.WebhookURL = "https://hooks.example.com/store"
+	stores.stores[storeID] = *store
+
+	service.notifyMemberChange(storeID, entities.WebhookEventMemberJoined, memberWebhookData{UserID: "member"})
+	if sender.posts != 0 {
+		t.Fatalf("webhook sent without a secret")
+	}
+
+	store.WebhookSecret = "0123456789abcdef"
+	stores.stores[storeID] = *store
+
+	service.notifyMemberChange(storeID, entities.WebhookEventMemberJoined, memberWebhookData{UserID: "member"})
+	if sender.posts != 1 {
+		t.Fatalf("posts = %d, want 1 signed delivery", sender.posts)
+	}
+}
+
+func TestSuspendedMemberCannotViewOrUpdateStore(t *testing.T) {
+	service, stores, roles := newTestStoreService()
+	storeID := addStore(t, stores, roles, "shop", map[string]entities.StoreRole{
+		"owner":   entities.StoreRoleOwner,
+		"manager": entities.StoreRoleAdmin,
+	})
+	if err := service.SuspendMember(storeID, "manager", "owner"); err != nil {
+		t.Fatalf("SuspendMember: %v", err)
+	}
+
+	if _, err := service.GetStore(storeID, "manager"); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("GetStore: err = %v, want not found", err)
+	}
+	if _, err := service.GetStoreBySlug("shop", "manager"); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("GetStoreBySlug: err = %v, want not found", err)
+	}
+	name := "Renamed"
+	if _, err := service.UpdateStore(storeID, "manager", dto.UpdateStoreRequest{Name: &name}); !errors.Is(err, services.ErrForbidden) {
+		t.Errorf("UpdateStore: err = %v, want forbidden", err)
+	}
+	if store, _ := stores.GetByID(storeID); store.Name != "shop" {
+		t.Errorf("name = %q, a suspended member renamed the store", store.Name)
+	}
+
+	if err := service.UnsuspendMember(storeID, "manager", "owner"); err != nil {
+		t.Fatalf("UnsuspendMember: %v", err)
+	}
+	if _, err := service.GetStore(storeID, "manager"); err != nil {
+		t.Errorf("GetStore after unsuspending: %v", err)
+	}
+}
+
+func TestUpdateStoreSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		slug     string
+		want     string
+		conflict bool
+		invalid  bool
+	}{
+		{name: "new slug is normalized", slug: "  My New Shop!  ", want: "my-new-shop"},
+		{name: "own slug is kept", slug: "shop", want: "shop"},
+		{name: "slug of another store", slug: "Other", conflict: true},
+		{name: "reserved word", slug: "admin", invalid: true},
+		{name: "reserved word after normalizing", slug: " API ", invalid: true},
+		{name: "symbols only", slug: "!!!", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, stores, roles := newTestStoreService()
+			storeID := addStore(t, stores, roles, "shop", map[string]entities.StoreRole{"owner": entities.StoreRoleOwner})
+			addStore(t, stores, roles, "other", nil)
+
+			_, err := service.UpdateStore(storeID, "owner", dto.UpdateStoreRequest{Slug: &tt.slug})
+
+			store, _ := stores.GetByID(storeID)
+			var validationErr *services.ValidationError
+			switch {
+			case tt.conflict:
+				if !errors.Is(err, services.ErrConflict) {
+					t.Fatalf("err = %v, want a conflict", err)
+				}
+			case tt.invalid:
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("err = %v, want a validation error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("UpdateStore: %v", err)
+				}
+				if store.Slug != tt.want {
+					t.Errorf("slug = %q, want %q", store.Slug, tt.want)
+				}
+				return
+			}
+			if store.Slug != "shop" {
+				t.Errorf("slug = %q, want it unchanged", store.Slug)
+			}
+		})
+	}
+}
+
+func TestGenerateSlug(t *testing.T) {
+	service, _, _ := newTestStoreService()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		invalid bool
+	}{
+		{name: "words", input: "Corner Shop", want: "corner-shop"},
+		{name: "repeated separators", input: "--Corner   &  Shop--", want: "corner-shop"},
+		{name: "emoji only", input: "🛒🛍️", invalid: true},
+		{name: "symbols only", input: "&*()!", invalid: true},
+		{name: "blank", input: "   ", invalid: true},
+		{name: "non-ASCII letters only", input: "éü", invalid: true},
+		{name: "reserved", input: "Settings", invalid: true},
+		{name: "emoji around a name", input: "🛒 Mart 🛒", want: "mart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug, err := service.generateSlug(tt.input)
+			if tt.invalid {
+				var validationErr *services.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Errorf("slug = %q, err = %v, want a validation error", slug, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateSlug: %v", err)
+			}
+			if slug != tt.want {
+				t.Errorf("slug = %q, want %q", slug, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSlugPadsShortSlugs(t *testing.T) {
+	service, _, _ := newTestStoreService()
+
+	for _, input := range []string{"A", "ab"} {
+		slug, err := service.generateSlug(input)
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		prefix := strings.ToLower(input) + "-"
+		if !strings.HasPrefix(slug, prefix) || len(slug) != len(prefix)+6 {
+			t.Errorf("%q: slug = %q, want %s followed by 6 hex characters", input, slug, prefix)
+		}
+	}
+
+	first, _ := service.generateSlug("ab")
+	second, _ := service.generateSlug("ab")
+	if first == second {
+		t.Errorf("two short slugs got the same suffix %q", first)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // the runtime image has no zoneinfo, so embed it for timezone validation
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+var currencyCodeRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateSettings checks each known setting value and, in strict mode, rejects unknown keys.
+// Outside strict mode unknown keys are stripped, since StoreSettings never decodes them.
+func (s *storeService) validateSettings(settings entities.StoreSettings, unknownKeys []string) error {
+	var fieldErrors []string
+
+	if !currencyCodeRegex.MatchString(settings.Currency) {
+		fieldErrors = append(fieldErrors, "settings.currency must be a 3-letter ISO 4217 currency code")
+	}
+
+	if settings.Timezone == "" {
+		fieldErrors = append(fieldErrors, "settings.timezone is required")
+	} else if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		fieldErrors = append(fieldErrors, "settings.timezone must be a valid IANA timezone")
+	}
+
+	if settings.MaxProducts < 1 {
+		fieldErrors = append(fieldErrors, "settings.max_products must be at least 1")
+	}
+
+	switch settings.ShippingMethod {
+	case "", entities.ShippingMethodNone, entities.ShippingMethodFlatRate, entities.ShippingMethodFreeOverThreshold:
+	default:
+		fieldErrors = append(fieldErrors, fmt.Sprintf("settings.shipping_method must be one of: %s, %s, %s",
+			entities.ShippingMethodNone, entities.ShippingMethodFlatRate, entities.ShippingMethodFreeOverThreshold))
+	}
+
+	if settings.ShippingFlatRate < 0 {
+		fieldErrors = append(fieldErrors, "settings.shipping_flat_rate must not be negative")
+	}
+
+	if settings.FreeShippingThreshold < 0 {
+		fieldErrors = append(fieldErrors, "settings.free_shipping_threshold must not be negative")
+	}
+
+	if settings.TaxRate < 0 || settings.TaxRate > 1 {
+		fieldErrors = append(fieldErrors, "settings.tax_rate must be between 0 and 1")
+	}
+
+	if len(settings.PaymentMethods) == 0 {
+		fieldErrors = append(fieldErrors, "settings.payment_methods must list at least one payment method")
+	}
+	seenMethods := make(map[string]bool, len(settings.PaymentMethods))
+	for _, method := range settings.PaymentMethods {
+		if !slices.Contains(entities.PaymentMethods, method) {
+			fieldErrors = append(fieldErrors, fmt.Sprintf("settings.payment_methods contains unsupported method %q; supported: %s",
+				method, strings.Join(entities.PaymentMethods, ", ")))
+		} else if seenMethods[method] {
+			fieldErrors = append(fieldErrors, fmt.Sprintf("settings.payment_methods lists %q more than once", method))
+		}
+		seenMethods[method] = true
+	}
+
+	if s.config.StrictSettings {
+		for _, key := range unknownKeys {
+			fieldErrors = append(fieldErrors, fmt.Sprintf("settings.%s is not a recognized setting", key))
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return &services.ValidationError{Errors: fieldErrors}
+	}
+
+	return nil
+}
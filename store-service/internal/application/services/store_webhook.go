@@ -0,0 +1,97 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+)
+
+type memberWebhookPayload struct {
+	Event      entities.WebhookEvent `json:"event"`
+	StoreID    string                `json:"store_id"`
+	OccurredAt string                `json:"occurred_at"`
+	Data       memberWebhookData     `json:"data"`
+}
+
+type memberWebhookData struct {
+	UserID       string             `json:"user_id"`
+	Role         entities.StoreRole `json:"role,omitempty"`
+	PreviousRole entities.StoreRole `json:"previous_role,omitempty"`
+	ActorID      string             `json:"actor_id,omitempty"`
+}
+
+// notifyMemberChange posts a member webhook when the store has a URL and secret configured. The payload is
+// signed with the store's secret, retried with exponential backoff and recorded as a WebhookDelivery.
+// It blocks while retrying, so callers run it in a goroutine.
+func (s *storeService) notifyMemberChange(storeID string, event entities.WebhookEvent, data memberWebhookData) {
+	occurredAt := time.Now()
+
+	store, err := s.storeRepo.GetByID(storeID)
+	if err != nil || store.WebhookURL == "" {
+		return
+	}
+	// An empty key would make the signature forgeable by anyone, so unsigned webhooks are never sent
+	if store.WebhookSecret == "" {
+		log.Printf("Skipping %s webhook for store %s: no webhook secret is set", event, storeID)
+		return
+	}
+
+	body, err := json.Marshal(memberWebhookPayload{
+		Event:      event,
+		StoreID:    storeID,
+		OccurredAt: occurredAt.UTC().Format(time.RFC3339),
+		Data:       data,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s webhook for store %s: %v", event, storeID, err)
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(store.WebhookSecret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	delivery := &entities.WebhookDelivery{
+		StoreID: storeID,
+		Event:   event,
+		URL:     store.WebhookURL,
+		Payload: string(body),
+	}
+	if err := s.webhookRepo.Create(delivery); err != nil {
+		log.Printf("Failed to record %s webhook for store %s: %v", event, storeID, err)
+		return
+	}
+
+	backoff := time.Second
+	for attempt := 0; attempt <= s.config.WebhookMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		statusCode, err := s.webhookSender.Post(store.WebhookURL, string(event), body, signature)
+		delivery.Attempts = attempt + 1
+		delivery.StatusCode = statusCode
+		if err == nil {
+			now := time.Now()
+			delivery.Success = true
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			break
+		}
+		delivery.LastError = err.Error()
+	}
+
+	if !delivery.Success {
+		log.Printf("Webhook %s for store %s failed after %d attempts: %s", event, storeID, delivery.Attempts, delivery.LastError)
+	}
+
+	if err := s.webhookRepo.Update(delivery); err != nil {
+		log.Printf("Failed to update webhook delivery %s: %v", delivery.ID, err)
+	}
+}
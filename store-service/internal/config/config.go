@@ -1,19 +1,38 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
 	Database          DatabaseConfig
 	Redis             RedisConfig
+	Store             StoreConfig
+	SMTP              SMTPConfig
+	Uploads           UploadConfig
 	AppEnv            string
 	AppPort           string
+	LogLevel          string
+	LogBodySampleRate int
+	Maintenance       MaintenanceConfig
+	RequestTimeout    time.Duration
+	CORSOrigins       string
+	AdminToken        string
+	ReloadFile        string
 	ProductServiceURL string
 	UserServiceURL    string
 }
 
+// MaintenanceConfig controls maintenance mode. Enabled forces it on; otherwise it
+// can be toggled at runtime through the admin endpoint.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
+}
+
 type DatabaseConfig struct {
 	Host     string
 	User     string
@@ -23,6 +42,54 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+type StoreConfig struct {
+	// StrictSettings rejects unknown store settings keys instead of stripping them
+	StrictSettings bool
+	// InvitationTTL is how long an invitation stays valid when the request sets no expiry
+	InvitationTTL time.Duration
+	// MaxInvitationDays bounds the per-invitation expires_in_days override
+	MaxInvitationDays int
+	// InvitationURL is the accept-invitation page; the token is appended as a query parameter
+	InvitationURL string
+	// InvitationEmailRetries is how many times a failed invitation email is retried
+	InvitationEmailRetries int
+	// WebhookMaxRetries is how many times a failed member webhook is retried
+	WebhookMaxRetries int
+	// WebhookTimeout bounds each webhook delivery attempt
+	WebhookTimeout time.Duration
+	// WebhookAllowPrivate lets webhooks reach loopback and private addresses, for local
+	// development only
+	WebhookAllowPrivate bool
+	// LowStockThreshold is the stock level at or below which the dashboard counts a product as low
+	LowStockThreshold int
+	// ActivityTouchInterval is the minimum time between last-activity writes for one member
+	ActivityTouchInterval time.Duration
+	// InvitationRateLimit caps the invitations one store can create per InvitationRateWindow;
+	// zero disables the cap
+	InvitationRateLimit  int
+	InvitationRateWindow time.Duration
+}
+
+// UploadConfig controls store image uploads. Files are written under Dir and served by
+// the service at /uploads; BaseURL is the public address of that path and prefixes the
+// URLs saved on the store.
+type UploadConfig struct {
+	Dir     string
+	BaseURL string
+	// MaxSize is the largest accepted file in bytes; it must stay below the 4MB body limit
+	MaxSize int64
+	// MaxDimension bounds both the width and the height of an uploaded image in pixels
+	MaxDimension int
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
 type RedisConfig struct {
 	Host     string
 	Port     int
@@ -34,6 +101,24 @@ func Load() *Config {
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	strictSettings, _ := strconv.ParseBool(getEnv("STORE_STRICT_SETTINGS", "false"))
+	invitationTTL, _ := time.ParseDuration(getEnv("STORE_INVITATION_TTL", "168h"))
+	maxInvitationDays, _ := strconv.Atoi(getEnv("STORE_MAX_INVITATION_DAYS", "30"))
+	invitationEmailRetries, _ := strconv.Atoi(getEnv("STORE_INVITATION_EMAIL_RETRIES", "3"))
+	webhookMaxRetries, _ := strconv.Atoi(getEnv("STORE_WEBHOOK_MAX_RETRIES", "5"))
+	webhookTimeout, _ := time.ParseDuration(getEnv("STORE_WEBHOOK_TIMEOUT", "10s"))
+	webhookAllowPrivate, _ := strconv.ParseBool(getEnv("STORE_WEBHOOK_ALLOW_PRIVATE", "false"))
+	lowStockThreshold, _ := strconv.Atoi(getEnv("STORE_LOW_STOCK_THRESHOLD", "10"))
+	activityTouchInterval, _ := time.ParseDuration(getEnv("STORE_ACTIVITY_TOUCH_INTERVAL", "5m"))
+	invitationRateLimit, _ := strconv.Atoi(getEnv("STORE_INVITATION_RATE_LIMIT", "100"))
+	invitationRateWindow, _ := time.ParseDuration(getEnv("STORE_INVITATION_RATE_WINDOW", "1h"))
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	uploadMaxSize, _ := strconv.ParseInt(getEnv("UPLOAD_MAX_SIZE", "2097152"), 10, 64)
+	uploadMaxDimension, _ := strconv.Atoi(getEnv("UPLOAD_MAX_DIMENSION", "4096"))
+	logBodySampleRate, _ := strconv.Atoi(getEnv("LOG_BODY_SAMPLE_RATE", "1"))
+	maintenanceEnabled, _ := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	maintenanceRetryAfter, _ := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	requestTimeout, _ := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
 
 	return &Config{
 		Database: DatabaseConfig{
@@ -50,9 +135,63 @@ func Load() *Config {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       redisDB,
 		},
-		AppEnv:  getEnv("APP_ENV", "development"),
-		AppPort: getEnv("APP_PORT", "3006"),
+		Store: StoreConfig{
+			StrictSettings:         strictSettings,
+			InvitationTTL:          invitationTTL,
+			MaxInvitationDays:      maxInvitationDays,
+			InvitationURL:          getEnv("STORE_INVITATION_URL", "http://localhost:3000/invitations/accept"),
+			InvitationEmailRetries: invitationEmailRetries,
+			WebhookMaxRetries:      webhookMaxRetries,
+			WebhookTimeout:         webhookTimeout,
+			WebhookAllowPrivate:    webhookAllowPrivate,
+			LowStockThreshold:      lowStockThreshold,
+			ActivityTouchInterval:  activityTouchInterval,
+			InvitationRateLimit:    invitationRateLimit,
+			InvitationRateWindow:   invitationRateWindow,
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     smtpPort,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@localhost"),
+		},
+		Uploads: UploadConfig{
+			Dir:          getEnv("UPLOAD_DIR", "./uploads"),
+			BaseURL:      getEnv("UPLOAD_BASE_URL", "http://localhost:3006/uploads"),
+			MaxSize:      uploadMaxSize,
+			MaxDimension: uploadMaxDimension,
+		},
+		AppEnv:            getEnv("APP_ENV", "development"),
+		AppPort:           getEnv("APP_PORT", "3006"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogBodySampleRate: logBodySampleRate,
+		Maintenance: MaintenanceConfig{
+			Enabled:    maintenanceEnabled,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		RequestTimeout:    requestTimeout,
+		CORSOrigins:       getEnv("CORS_ALLOW_ORIGINS", "*"),
+		AdminToken:        getEnv("ADMIN_TOKEN", ""),
+		ReloadFile:        getEnv("CONFIG_RELOAD_FILE", ""),
+		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://product-service:3004"),
+	}
+}
+
+// maxInvitationDaysLimit is the longest any invitation may stay valid
+const maxInvitationDaysLimit = 365
+
+// Validate reports settings that would otherwise only fail on the first request, such
+// as an invitation lifetime that is not positive or exceeds the allowed maximum.
+func (c *Config) Validate() error {
+	if c.Store.MaxInvitationDays < 1 || c.Store.MaxInvitationDays > maxInvitationDaysLimit {
+		return fmt.Errorf("STORE_MAX_INVITATION_DAYS must be between 1 and %d, got %d", maxInvitationDaysLimit, c.Store.MaxInvitationDays)
+	}
+	maxTTL := time.Duration(c.Store.MaxInvitationDays) * 24 * time.Hour
+	if c.Store.InvitationTTL <= 0 || c.Store.InvitationTTL > maxTTL {
+		return fmt.Errorf("STORE_INVITATION_TTL must be a positive duration of at most %s, got %s", maxTTL, c.Store.InvitationTTL)
 	}
+	return nil
 }
 
 func getEnv(key, defaultValue string) string {
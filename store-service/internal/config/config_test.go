@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateInvitationLifetime(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		maxDays int
+		valid   bool
+	}{
+		{name: "defaults", ttl: 168 * time.Hour, maxDays: 30, valid: true},
+		{name: "ttl equal to max", ttl: 30 * 24 * time.Hour, maxDays: 30, valid: true},
+		{name: "zero ttl", ttl: 0, maxDays: 30},
+		{name: "negative ttl", ttl: -time.Hour, maxDays: 30},
+		{name: "ttl above max days", ttl: 31 * 24 * time.Hour, maxDays: 30},
+		{name: "zero max days", ttl: time.Hour, maxDays: 0},
+		{name: "max days above limit", ttl: time.Hour, maxDays: maxInvitationDaysLimit + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Store: StoreConfig{InvitationTTL: tt.ttl, MaxInvitationDays: tt.maxDays}}
+			err := cfg.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Validate accepted an invalid invitation lifetime")
+			}
+		})
+	}
+}
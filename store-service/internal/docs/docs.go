@@ -0,0 +1,45 @@
+// Package docs serves the OpenAPI description of the service's HTTP API. The
+// description lives in openapi.json next to this file and must be updated together
+// with the routes and DTOs it documents.
+package docs
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SwaggerPath is where the Swagger UI is served; the raw document is at SwaggerPath + "/doc.json"
+const SwaggerPath = "/swagger"
+
+//go:embed openapi.json
+var spec []byte
+
+// swaggerUIPage renders the Swagger UI from a CDN, pointed at the embedded document
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API documentation</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>window.ui = SwaggerUIBundle({ url: %q, dom_id: "#swagger-ui" });</script>
+</body>
+</html>`
+
+// Register mounts the Swagger UI at SwaggerPath and the OpenAPI document at
+// SwaggerPath/doc.json on the given router
+func Register(router fiber.Router) {
+	router.Get(SwaggerPath+"/doc.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(spec)
+	})
+	router.Get(SwaggerPath, func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(fmt.Sprintf(swaggerUIPage, SwaggerPath+"/doc.json"))
+	})
+}
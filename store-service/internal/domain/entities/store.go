@@ -11,25 +11,30 @@ import (
 )
 
 type Store struct {
-	ID          string         `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name        string         `json:"name" gorm:"not null;size:100"`
-	Slug        string         `json:"slug" gorm:"not null;uniqueIndex;size:100"`
-	Description string         `json:"description" gorm:"type:text"`
-	Logo        string         `json:"logo,omitempty"`
-	Banner      string         `json:"banner,omitempty"`
-	Website     string         `json:"website,omitempty"`
-	Phone       string         `json:"phone,omitempty"`
-	Email       string         `json:"email,omitempty"`
-	Address     string         `json:"address,omitempty"`
-	City        string         `json:"city,omitempty"`
-	State       string         `json:"state,omitempty"`
-	Country     string         `json:"country,omitempty"`
-	PostalCode  string         `json:"postal_code,omitempty"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	Settings    StoreSettings  `json:"settings" gorm:"type:jsonb"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID          string        `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name        string        `json:"name" gorm:"not null;size:100"`
+	Slug        string        `json:"slug" gorm:"not null;uniqueIndex;size:100"`
+	Description string        `json:"description" gorm:"type:text"`
+	Logo        string        `json:"logo,omitempty"`
+	Banner      string        `json:"banner,omitempty"`
+	Website     string        `json:"website,omitempty"`
+	Phone       string        `json:"phone,omitempty"`
+	Email       string        `json:"email,omitempty"`
+	Address     string        `json:"address,omitempty"`
+	City        string        `json:"city,omitempty"`
+	State       string        `json:"state,omitempty"`
+	Country     string        `json:"country,omitempty"`
+	PostalCode  string        `json:"postal_code,omitempty"`
+	IsActive    bool          `json:"is_active" gorm:"default:true"`
+	Settings    StoreSettings `json:"settings" gorm:"type:jsonb"`
+	// WebhookURL receives signed member-change notifications; empty disables them
+	WebhookURL    string `json:"webhook_url,omitempty"`
+	WebhookSecret string `json:"-"`
+	// Version increases on every update and guards against lost concurrent edits
+	Version   int64          `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relationships
 	Members []UserStoreRole `json:"members,omitempty" gorm:"foreignKey:StoreID"`
@@ -41,6 +46,53 @@ type StoreSettings struct {
 	AllowPublicListing bool   `json:"allow_public_listing"`
 	RequireApproval    bool   `json:"require_approval"`
 	MaxProducts        int    `json:"max_products"`
+
+	// ShippingMethod selects how carts estimate shipping for this store; see ShippingMethod* constants
+	ShippingMethod        string  `json:"shipping_method"`
+	ShippingFlatRate      float64 `json:"shipping_flat_rate"`
+	FreeShippingThreshold float64 `json:"free_shipping_threshold"`
+	// TaxRate is a fraction of the subtotal, e.g. 0.1 for 10%
+	TaxRate float64 `json:"tax_rate"`
+
+	// PaymentMethods lists the payment methods checkout offers for this store; see PaymentMethod* constants
+	PaymentMethods []string `json:"payment_methods"`
+}
+
+// Shipping methods understood by the cart's shipping estimate
+const (
+	ShippingMethodNone              = "none"
+	ShippingMethodFlatRate          = "flat_rate"
+	ShippingMethodFreeOverThreshold = "free_over_threshold"
+)
+
+// Payment methods a store can accept at checkout
+const (
+	PaymentMethodCard           = "card"
+	PaymentMethodBankTransfer   = "bank_transfer"
+	PaymentMethodEWallet        = "e_wallet"
+	PaymentMethodCashOnDelivery = "cash_on_delivery"
+)
+
+// PaymentMethods lists every supported payment method
+var PaymentMethods = []string{
+	PaymentMethodCard,
+	PaymentMethodBankTransfer,
+	PaymentMethodEWallet,
+	PaymentMethodCashOnDelivery,
+}
+
+// StoreSettingsKeys lists the JSON keys understood by StoreSettings
+var StoreSettingsKeys = []string{
+	"currency",
+	"timezone",
+	"allow_public_listing",
+	"require_approval",
+	"max_products",
+	"shipping_method",
+	"shipping_flat_rate",
+	"free_shipping_threshold",
+	"tax_rate",
+	"payment_methods",
 }
 
 // Value implements driver.Valuer interface for database storage
@@ -60,8 +112,9 @@ func (s *StoreSettings) Scan(value interface{}) error {
 		return errors.New(fmt.Sprint("Failed to unmarshal StoreSettings value:", value))
 	}
 
+	// Start from the defaults so settings saved before a key existed read with its default
+	*s = GetDefaultStoreSettings()
 	if len(bytes) == 0 {
-		*s = GetDefaultStoreSettings()
 		return nil
 	}
 
@@ -76,9 +129,11 @@ func GetDefaultStoreSettings() StoreSettings {
 		AllowPublicListing: true,
 		RequireApproval:    false,
 		MaxProducts:        1000,
+		ShippingMethod:     ShippingMethodNone,
+		PaymentMethods:     []string{PaymentMethodCard},
 	}
 }
 
 func (Store) TableName() string {
 	return "stores"
-}
\ No newline at end of file
+}
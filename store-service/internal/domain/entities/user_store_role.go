@@ -15,26 +15,45 @@ const (
 	StoreRoleMember  StoreRole = "MEMBER"
 )
 
-// GetRoleHierarchy returns role hierarchy (higher number = more permissions)
-func GetRoleHierarchy() map[StoreRole]int {
-	return map[StoreRole]int{
-		StoreRoleMember:  1,
-		StoreRoleManager: 2,
-		StoreRoleAdmin:   3,
-		StoreRoleOwner:   4,
+// Level ranks the role (higher number = more permissions):
+// MEMBER (1) < MANAGER (2) < ADMIN (3) < OWNER (4). Unknown roles rank 0.
+func (r StoreRole) Level() int {
+	switch r {
+	case StoreRoleOwner:
+		return 4
+	case StoreRoleAdmin:
+		return 3
+	case StoreRoleManager:
+		return 2
+	case StoreRoleMember:
+		return 1
+	default:
+		return 0
 	}
 }
 
-// HasPermission checks if current role has permission to perform action on target role
-func (r StoreRole) HasPermission(targetRole StoreRole) bool {
-	hierarchy := GetRoleHierarchy()
-	return hierarchy[r] > hierarchy[targetRole]
+// CanManage reports whether a member with role r may act on, or assign, the target role.
+// The check is strict: a role never manages its own level, so an ADMIN cannot act on or
+// assign ADMIN, and only an OWNER can act on ADMIN.
+func (r StoreRole) CanManage(target StoreRole) bool {
+	return target.IsValid() && r.Level() > target.Level()
+}
+
+// AtLeast reports whether r ranks the same as or above the required role
+func (r StoreRole) AtLeast(required StoreRole) bool {
+	return r.IsValid() && r.Level() >= required.Level()
+}
+
+// IsValid reports whether r is one of the known store roles
+func (r StoreRole) IsValid() bool {
+	return r.Level() > 0
 }
 
 type UserStoreRole struct {
 	ID        string         `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	UserID    string         `json:"user_id" gorm:"not null;index"`
-	StoreID   string         `json:"store_id" gorm:"not null;index"`
+	UserID    string         `json:"user_id" gorm:"not null;index;uniqueIndex:idx_user_store_roles_user_store,where:deleted_at IS NULL"`
+	StoreID   string         `json:"store_id" gorm:"not null;index;uniqueIndex:idx_user_store_roles_user_store,where:deleted_at IS NULL"`
+	Email     string         `json:"email,omitempty" gorm:"index"`
 	Role      StoreRole      `json:"role" gorm:"not null;type:varchar(20)"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
 	JoinedAt  time.Time      `json:"joined_at" gorm:"default:CURRENT_TIMESTAMP"`
@@ -42,6 +61,10 @@ type UserStoreRole struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// LastActiveAt is when the member last acted on the store, refreshed at most once per
+	// touch interval; nil until their first action
+	LastActiveAt *time.Time `json:"last_active_at,omitempty"`
+
 	// Relationships
 	Store *Store `json:"store,omitempty" gorm:"foreignKey:StoreID"`
 }
@@ -0,0 +1,30 @@
+package entities
+
+import "testing"
+
+func TestCanManage(t *testing.T) {
+	const unknown StoreRole = "GUEST"
+
+	// want[actor][target] lists every pair; a role never manages its own level, and an
+	// unknown role neither manages nor is managed
+	want := map[StoreRole]map[StoreRole]bool{
+		StoreRoleOwner:   {StoreRoleOwner: false, StoreRoleAdmin: true, StoreRoleManager: true, StoreRoleMember: true, unknown: false},
+		StoreRoleAdmin:   {StoreRoleOwner: false, StoreRoleAdmin: false, StoreRoleManager: true, StoreRoleMember: true, unknown: false},
+		StoreRoleManager: {StoreRoleOwner: false, StoreRoleAdmin: false, StoreRoleManager: false, StoreRoleMember: true, unknown: false},
+		StoreRoleMember:  {StoreRoleOwner: false, StoreRoleAdmin: false, StoreRoleManager: false, StoreRoleMember: false, unknown: false},
+		unknown:          {StoreRoleOwner: false, StoreRoleAdmin: false, StoreRoleManager: false, StoreRoleMember: false, unknown: false},
+	}
+
+	roles := []StoreRole{StoreRoleOwner, StoreRoleAdmin, StoreRoleManager, StoreRoleMember, unknown}
+	for _, actor := range roles {
+		for _, target := range roles {
+			expected, ok := want[actor][target]
+			if !ok {
+				t.Fatalf("no expectation for %s managing %s", actor, target)
+			}
+			if got := actor.CanManage(target); got != expected {
+				t.Errorf("%s.CanManage(%s) = %v, want %v", actor, target, got, expected)
+			}
+		}
+	}
+}
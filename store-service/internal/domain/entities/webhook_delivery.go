@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventMemberJoined      WebhookEvent = "member.joined"
+	WebhookEventMemberRemoved     WebhookEvent = "member.removed"
+	WebhookEventMemberRoleUpdated WebhookEvent = "member.role_updated"
+	WebhookEventMemberSuspended   WebhookEvent = "member.suspended"
+	WebhookEventMemberUnsuspended WebhookEvent = "member.unsuspended"
+)
+
+// WebhookDelivery records one outbound webhook and the outcome of its delivery attempts
+type WebhookDelivery struct {
+	ID          string       `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	StoreID     string       `json:"store_id" gorm:"not null;index"`
+	Event       WebhookEvent `json:"event" gorm:"not null;type:varchar(50)"`
+	URL         string       `json:"url" gorm:"not null"`
+	Payload     string       `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts    int          `json:"attempts" gorm:"not null;default:0"`
+	StatusCode  int          `json:"status_code,omitempty"`
+	Success     bool         `json:"success" gorm:"not null;default:false"`
+	LastError   string       `json:"last_error,omitempty" gorm:"type:text"`
+	DeliveredAt *time.Time   `json:"delivered_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
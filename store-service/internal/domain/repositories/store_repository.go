@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 )
 
@@ -9,6 +11,7 @@ type StoreRepository interface {
 	GetByID(id string) (*entities.Store, error)
 	GetBySlug(slug string) (*entities.Store, error)
 	GetByUserID(userID string, limit, offset int) ([]entities.Store, error)
+	// Update returns ErrStoreVersionConflict when the store changed since it was read
 	Update(store *entities.Store) error
 	Delete(id string) error
 	GetStoresByFilter(filter StoreFilter) ([]entities.Store, int64, error)
@@ -17,22 +20,32 @@ type StoreRepository interface {
 
 type StoreFilter struct {
 	UserID   string
+	Role     entities.StoreRole // only applies with UserID
 	IsActive *bool
 	Search   string
 	Limit    int
 	Offset   int
 }
 
+// UserStoreRoleRepository stores memberships. Lookups used for access checks, such as
+// GetUserRole, GetByUserAndStore, HasPermission and IsStoreOwner, ignore suspended
+// (inactive) memberships; only GetByStoreID and GetMembershipIncludingSuspended see them.
 type UserStoreRoleRepository interface {
 	Create(role *entities.UserStoreRole) error
 	GetByUserAndStore(userID, storeID string) (*entities.UserStoreRole, error)
+	GetMembershipIncludingSuspended(userID, storeID string) (*entities.UserStoreRole, error)
+	GetByEmailAndStore(email, storeID string) (*entities.UserStoreRole, error)
 	GetByStoreID(storeID string) ([]entities.UserStoreRole, error)
 	GetByUserID(userID string) ([]entities.UserStoreRole, error)
 	Update(role *entities.UserStoreRole) error
+	SetActive(userID, storeID string, active bool) error
 	Delete(userID, storeID string) error
 	GetUserRole(userID, storeID string) (entities.StoreRole, error)
+	GetUserRolesForStores(userID string, storeIDs []string) (map[string]entities.StoreRole, error)
 	HasPermission(userID, storeID string, requiredRole entities.StoreRole) (bool, error)
 	IsStoreOwner(userID, storeID string) (bool, error)
+	TouchLastActive(userID, storeID string, at time.Time) error
+	GetInactiveByStoreID(storeID string, since time.Time) ([]entities.UserStoreRole, error)
 }
 
 type StoreInvitationRepository interface {
@@ -45,4 +58,10 @@ type StoreInvitationRepository interface {
 	Delete(id string) error
 	ExpireOldInvitations() error
 	GetPendingByEmailAndStore(email, storeID string) (*entities.StoreInvitation, error)
-}
\ No newline at end of file
+	Accept(invitation *entities.StoreInvitation, role *entities.UserStoreRole) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(delivery *entities.WebhookDelivery) error
+	Update(delivery *entities.WebhookDelivery) error
+}
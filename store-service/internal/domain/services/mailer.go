@@ -0,0 +1,6 @@
+package services
+
+// Mailer delivers plain-text email
+type Mailer interface {
+	Send(to, subject, body string) error
+}
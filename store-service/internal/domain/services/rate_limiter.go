@@ -0,0 +1,10 @@
+package services
+
+import "time"
+
+// RateLimiter counts events per key in fixed windows shared by every instance of the service
+type RateLimiter interface {
+	// Allow records one event for key and reports whether it stays within limit for the
+	// current window. A rejected event is not counted.
+	Allow(key string, limit int, window time.Duration) (bool, error)
+}
@@ -0,0 +1,6 @@
+package services
+
+// FileStorage stores uploaded files and returns the public URL each one is served from
+type FileStorage interface {
+	Put(key, contentType string, data []byte) (string, error)
+}
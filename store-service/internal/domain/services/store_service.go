@@ -2,26 +2,86 @@ package services
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 )
 
 type StoreService interface {
-	CreateStore(userID string, req dto.CreateStoreRequest) (*dto.StoreResponse, error)
+	CreateStore(userID, userEmail string, req dto.CreateStoreRequest) (*dto.StoreResponse, error)
 	GetStore(storeID, userID string) (*dto.StoreResponse, error)
 	GetStoreBySlug(slug, userID string) (*dto.StoreResponse, error)
-	GetUserStores(userID string, page, perPage int) (*dto.StoreListResponse, error)
+	GetUserStores(userID string, role entities.StoreRole, page, perPage int) (*dto.StoreListResponse, error)
 	UpdateStore(storeID, userID string, req dto.UpdateStoreRequest) (*dto.StoreResponse, error)
 	DeleteStore(storeID, userID string) error
+	GetStoreDashboard(storeID, userID string) (*dto.StoreDashboardResponse, error)
+	UploadStoreImage(storeID, userID string, image StoreImage, data []byte) (*dto.StoreResponse, error)
 
 	// Member management
 	InviteMember(storeID, inviterID string, req dto.InviteMemberRequest) (*dto.StoreInvitationResponse, error)
+	InviteMembers(storeID, inviterID string, emails []string, role entities.StoreRole) (*dto.BulkInviteResponse, error)
+	// PreviewInvitation looks an invitation up by token without requiring a signed-in user
+	PreviewInvitation(token string) (*dto.InvitationPreviewResponse, error)
 	AcceptInvitation(userID string, req dto.AcceptInvitationRequest) error
 	GetStoreMembers(storeID, userID string) ([]dto.StoreMemberResponse, error)
+	GetInactiveMembers(storeID, userID string, days int) ([]dto.StoreMemberResponse, error)
 	UpdateMemberRole(storeID, memberUserID, requesterID string, req dto.UpdateMemberRoleRequest) error
 	RemoveMember(storeID, memberUserID, requesterID string) error
+	SuspendMember(storeID, memberUserID, requesterID string) error
+	UnsuspendMember(storeID, memberUserID, requesterID string) error
 	GetStoreInvitations(storeID, userID string) ([]dto.StoreInvitationResponse, error)
 	GetUserInvitations(userEmail string) ([]dto.StoreInvitationResponse, error)
+
+	// Internal
+	GetMembership(storeID, userID string) (*dto.StoreMembershipResponse, error)
+	GetInternalStore(storeID string) (*dto.InternalStoreResponse, error)
 }
 
+// StoreImage names an image slot on a store that can be set by upload
+type StoreImage string
+
+const (
+	StoreImageLogo   StoreImage = "logo"
+	StoreImageBanner StoreImage = "banner"
+)
+
 var ErrNotFound = errors.New("resource not found")
+
+// Kinds of failure a store service error can be, used by handlers to pick the status and
+// error code of the response
+var (
+	ErrForbidden   = errors.New("forbidden")
+	ErrConflict    = errors.New("conflict")
+	ErrInvalid     = errors.New("invalid request")
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Error is a service failure whose message is meant for the client. Kind is ErrNotFound,
+// ErrForbidden, ErrConflict, ErrInvalid or ErrRateLimited, and errors.Is matches it.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NewError returns an *Error of the given kind
+func NewError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// ValidationError reports field-level validation failures detected by the service
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + strings.Join(e.Errors, "; ")
+}
@@ -0,0 +1,6 @@
+package services
+
+// WebhookSender posts a signed payload to an external endpoint and reports the HTTP status
+type WebhookSender interface {
+	Post(url string, event string, body []byte, signature string) (int, error)
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
@@ -16,17 +17,24 @@ func Migrate(db *gorm.DB, resetDb bool) error {
 
 	if resetDb {
 		// Drop existing tables if they exist
-		err = db.Migrator().DropTable(&entities.UserStoreRole{}, &entities.StoreInvitation{}, &entities.Store{})
+		err = db.Migrator().DropTable(&entities.WebhookDelivery{}, &entities.UserStoreRole{}, &entities.StoreInvitation{}, &entities.Store{})
 		if err != nil {
 			return fmt.Errorf("failed to drop tables: %w", err)
 		}
 	}
 
+	// Duplicate memberships would block the unique (user_id, store_id) index
+	err = dedupeUserStoreRoles(db)
+	if err != nil {
+		return fmt.Errorf("failed to dedupe store memberships: %w", err)
+	}
+
 	// Create tables with new schema
 	err = db.AutoMigrate(
 		&entities.UserStoreRole{},
 		&entities.StoreInvitation{},
 		&entities.Store{},
+		&entities.WebhookDelivery{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate tables: %w", err)
@@ -56,5 +64,40 @@ func migrateStoreSettings(db *gorm.DB) error {
 		return fmt.Errorf("failed to update store settings: %w", err)
 	}
 
+	// Backfill the currency and payment methods of stores saved before they were required
+	err = db.Exec(`UPDATE stores SET settings = jsonb_set(settings, '{currency}', to_jsonb(?::text))
+		WHERE COALESCE(settings->>'currency', '') = ''`, defaultSettings.Currency).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill store currency: %w", err)
+	}
+
+	paymentMethodsJSON, err := json.Marshal(defaultSettings.PaymentMethods)
+	if err != nil {
+		return fmt.Errorf("failed to marshal default payment methods: %w", err)
+	}
+	err = db.Exec(`UPDATE stores SET settings = jsonb_set(settings, '{payment_methods}', ?::jsonb)
+		WHERE jsonb_typeof(settings->'payment_methods') IS DISTINCT FROM 'array'`, string(paymentMethodsJSON)).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill store payment methods: %w", err)
+	}
+
 	return nil
 }
+
+// dedupeUserStoreRoles soft-deletes all but the earliest membership per user and store
+func dedupeUserStoreRoles(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entities.UserStoreRole{}) {
+		return nil
+	}
+
+	return db.Exec(`
+		UPDATE user_store_roles SET deleted_at = NOW()
+		WHERE deleted_at IS NULL AND id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, store_id ORDER BY created_at ASC, id ASC) AS rn
+				FROM user_store_roles
+				WHERE deleted_at IS NULL
+			) ranked
+			WHERE rn > 1
+		)`).Error
+}
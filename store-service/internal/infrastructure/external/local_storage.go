@@ -0,0 +1,40 @@
+package external
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+// LocalStorage writes files to a directory on disk that the service itself serves
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(cfg *config.UploadConfig) services.FileStorage {
+	return &LocalStorage{
+		dir:     cfg.Dir,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+	}
+}
+
+// Put writes data under key, replacing any file already there
+func (s *LocalStorage) Put(key, contentType string, data []byte) (string, error) {
+	key = path.Clean("/" + key)[1:]
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write upload: %w", err)
+	}
+
+	return s.baseURL + "/" + key, nil
+}
@@ -0,0 +1,94 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// productPageSize is the page size used when walking a store's products
+const productPageSize = 100
+
+type ProductServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type ProductResponse struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	StoreID  string  `json:"store_id"`
+	SKU      string  `json:"sku"`
+	IsActive bool    `json:"is_active"`
+}
+
+type ServiceResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error,omitempty"`
+}
+
+func NewProductServiceClient(baseURL string) *ProductServiceClient {
+	return &ProductServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetStoreProducts returns every active product of the store, paging through the product service
+func (c *ProductServiceClient) GetStoreProducts(ctx context.Context, storeID string) ([]ProductResponse, error) {
+	var all []ProductResponse
+	for offset := 0; ; offset += productPageSize {
+		page, err := c.getStoreProductsPage(ctx, storeID, productPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+		if len(page) < productPageSize {
+			return all, nil
+		}
+	}
+}
+
+func (c *ProductServiceClient) getStoreProductsPage(ctx context.Context, storeID string, limit, offset int) ([]ProductResponse, error) {
+	url := fmt.Sprintf("%s/api/stores/%s/products?limit=%d&offset=%d", c.baseURL, storeID, limit, offset)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch store products: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	var serviceResp ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&serviceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !serviceResp.Success {
+		return nil, fmt.Errorf("product service error: %s", serviceResp.Error)
+	}
+
+	var products []ProductResponse
+	if err := json.Unmarshal(serviceResp.Data, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode product data: %w", err)
+	}
+
+	return products, nil
+}
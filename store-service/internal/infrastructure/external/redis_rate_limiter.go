@@ -0,0 +1,44 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+// rateLimitTimeout bounds each Redis round trip made by the limiter
+const rateLimitTimeout = 2 * time.Second
+
+// RedisRateLimiter keeps one counter per key and window in Redis, expiring with the window
+type RedisRateLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimiter(client *redis.Client) services.RateLimiter {
+	return &RedisRateLimiter{client: client}
+}
+
+func (l *RedisRateLimiter) Allow(key string, limit int, window time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rateLimitTimeout)
+	defer cancel()
+
+	windowStart := time.Now().Truncate(window).Unix()
+	counterKey := fmt.Sprintf("ratelimit:%s:%d", key, windowStart)
+
+	pipe := l.client.TxPipeline()
+	count := pipe.Incr(ctx, counterKey)
+	pipe.ExpireNX(ctx, counterKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	if count.Val() > int64(limit) {
+		// Give the slot back so rejected attempts do not push the window further over
+		l.client.Decr(ctx, counterKey)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package external
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewMailer returns an SMTP mailer, or one that only logs when no SMTP host is configured
+func NewMailer(cfg *config.SMTPConfig) services.Mailer {
+	if cfg.Host == "" {
+		return &LogMailer{}
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPMailer{
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	sender, recipient, msg, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, sender, []string{recipient}, msg); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", recipient, err)
+	}
+	return nil
+}
+
+// buildMessage renders a plain-text email and returns the bare sender and recipient
+// addresses for the SMTP envelope. Each address must parse as a single RFC 5322 address
+// and CR/LF is removed from the subject, so no header value can start a new header.
+func buildMessage(from, to, subject, body string) (string, string, []byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid recipient address %q: %w", to, err)
+	}
+
+	subject = strings.Join(strings.FieldsFunc(subject, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", recipient.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	return sender.Address, recipient.Address, []byte(msg.String()), nil
+}
+
+// LogMailer stands in for SMTP in development by logging instead of sending
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to, subject, body string) error {
+	log.Printf("SMTP not configured, skipping email to %s: %s", to, subject)
+	return nil
+}
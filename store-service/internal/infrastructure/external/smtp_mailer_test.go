@@ -0,0 +1,64 @@
+package external
+
+import (
+	"strings"
+	"testing"
+)
+
+// headers returns the header lines of a rendered message
+func headers(msg []byte) []string {
+	head, _, _ := strings.Cut(string(msg), "\r\n\r\n")
+	return strings.Split(head, "\r\n")
+}
+
+func TestBuildMessageStripsLineBreaksFromSubject(t *testing.T) {
+	_, _, msg, err := buildMessage("no-reply@example.com", "invitee@example.com",
+		"You're invited to join Shop\r\nBcc: victim@example.com", "body")
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	lines := headers(msg)
+	for _, line := range lines {
+		if strings.HasPrefix(strings.ToLower(line), "bcc:") {
+			t.Fatalf("subject injected a header: %q", lines)
+		}
+	}
+	if want := "Subject: You're invited to join Shop Bcc: victim@example.com"; lines[2] != want {
+		t.Errorf("subject header = %q, want %q", lines[2], want)
+	}
+}
+
+func TestBuildMessageRejectsInvalidAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "recipient with header", from: "no-reply@example.com", to: "invitee@example.com\r\nBcc: victim@example.com"},
+		{name: "recipient list", from: "no-reply@example.com", to: "a@example.com, b@example.com"},
+		{name: "recipient without domain", from: "no-reply@example.com", to: "invitee"},
+		{name: "sender with header", from: "no-reply@example.com\nBcc: victim@example.com", to: "invitee@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := buildMessage(tt.from, tt.to, "subject", "body"); err == nil {
+				t.Error("buildMessage accepted an invalid address")
+			}
+		})
+	}
+}
+
+func TestBuildMessageUsesBareEnvelopeAddresses(t *testing.T) {
+	sender, recipient, msg, err := buildMessage("Shop <no-reply@example.com>", "Invitee <invitee@example.com>", "Hello", "body")
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	if sender != "no-reply@example.com" || recipient != "invitee@example.com" {
+		t.Errorf("envelope = %q -> %q, want bare addresses", sender, recipient)
+	}
+	if lines := headers(msg); lines[0] != `From: "Shop" <no-reply@example.com>` {
+		t.Errorf("from header = %q", lines[0])
+	}
+}
@@ -0,0 +1,137 @@
+package external
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+)
+
+// maxWebhookRedirects bounds how many redirects a webhook delivery follows
+const maxWebhookRedirects = 3
+
+// ErrWebhookAddressBlocked is returned when a webhook URL resolves to an address inside the
+// deployment's own networks
+var ErrWebhookAddressBlocked = errors.New("webhook address is not publicly routable")
+
+// blockedWebhookNetworks lists ranges not covered by the net.IP helpers that webhooks must
+// not reach: "this" network, carrier-grade NAT, benchmarking and reserved space
+var blockedWebhookNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{"0.0.0.0/8", "100.64.0.0/10", "198.18.0.0/15", "240.0.0.0/4"} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+// NewWebhookClient returns a sender that only connects to public addresses unless
+// allowPrivate is set. The address is checked after DNS resolution on every connection,
+// redirects included, so a hostname cannot be pointed at internal services.
+func NewWebhookClient(timeout time.Duration, allowPrivate bool) services.WebhookSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, host)
+			}
+			return nil
+		}
+	}
+
+	return &WebhookClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			// No proxy: the dialer must see the webhook's own address
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxWebhookRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxWebhookRedirects)
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// IsPublicWebhookIP reports whether ip is a public unicast address a webhook may be sent to
+func IsPublicWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateWebhookURL checks that raw is an absolute http(s) URL. Unless allowPrivate is
+// set, hosts that are obviously internal, such as localhost or a private IP literal, are
+// rejected up front; hostnames are checked again after resolution when delivering.
+func ValidateWebhookURL(raw string, allowPrivate bool) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return errors.New("webhook_url must be an absolute http or https URL")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrWebhookAddressBlocked
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicWebhookIP(ip) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// Post sends body with the event name and HMAC signature headers; any non-2xx status is an error
+func (c *WebhookClient) Post(url string, event string, body []byte, signature string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signature)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,98 @@
+package external
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsPublicWebhookIP(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":          true,
+		"2606:4700::1111":        true,
+		"127.0.0.1":              false,
+		"::1":                    false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"fe80::1":                false,
+		"fc00::1":                false,
+		"0.0.0.0":                false,
+		"100.64.0.1":             false,
+		"::ffff:127.0.0.1":       false,
+		"::ffff:169.254.169.254": false,
+		"224.0.0.1":              false,
+	}
+
+	for raw, public := range tests {
+		if got := IsPublicWebhookIP(net.ParseIP(raw)); got != public {
+			t.Errorf("IsPublicWebhookIP(%s) = %v, want %v", raw, got, public)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://hooks.example.com/store", valid: true},
+		{url: "http://93.184.216.34/hook", valid: true},
+		{url: "ftp://hooks.example.com/store"},
+		{url: "/relative/path"},
+		{url: "https://localhost/hook"},
+		{url: "https://api.localhost/hook"},
+		{url: "http://127.0.0.1:3006/api/stores"},
+		{url: "http://169.254.169.254/latest/meta-data"},
+		{url: "http://[::1]/hook"},
+		{url: "http://10.0.0.5/hook"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateWebhookURL(tt.url, false)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateWebhookURL(%s): %v", tt.url, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ValidateWebhookURL(%s) accepted the URL", tt.url)
+		}
+	}
+
+	if err := ValidateWebhookURL("http://127.0.0.1:8080/hook", true); err != nil {
+		t.Errorf("private URL rejected with allowPrivate set: %v", err)
+	}
+}
+
+func TestPostRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	blocked := NewWebhookClient(time.Second, false)
+	if _, err := blocked.Post(server.URL, "member.joined", []byte(`{}`), "sig"); !errors.Is(err, ErrWebhookAddressBlocked) {
+		t.Errorf("err = %v, want ErrWebhookAddressBlocked", err)
+	}
+
+	allowed := NewWebhookClient(time.Second, true)
+	if status, err := allowed.Post(server.URL, "member.joined", []byte(`{}`), "sig"); err != nil || status != http.StatusNoContent {
+		t.Errorf("Post with allowPrivate = %d, %v; want 204", status, err)
+	}
+}
+
+func TestPostLimitsRedirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/again", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(time.Second, true)
+	if _, err := client.Post(server.URL, "member.joined", []byte(`{}`), "sig"); err == nil {
+		t.Error("Post followed redirects without limit")
+	}
+}
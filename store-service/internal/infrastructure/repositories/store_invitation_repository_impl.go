@@ -78,4 +78,14 @@ func (r *storeInvitationRepository) GetPendingByEmailAndStore(email, storeID str
 		return nil, err
 	}
 	return &invitation, nil
-}
\ No newline at end of file
+}
+
+// Accept adds the invitee's membership and marks the invitation accepted in a single transaction
+func (r *storeInvitationRepository) Accept(invitation *entities.StoreInvitation, role *entities.UserStoreRole) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createMembership(tx, role); err != nil {
+			return err
+		}
+		return tx.Save(invitation).Error
+	})
+}
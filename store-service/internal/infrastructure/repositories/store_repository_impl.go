@@ -11,6 +11,7 @@ import (
 
 var ErrStoreNotFound = errors.New("store not found")
 var ErrStoreSlugExists = errors.New("store slug already exists")
+var ErrStoreVersionConflict = errors.New("store was modified by another request")
 
 type storeRepository struct {
 	db *gorm.DB
@@ -26,9 +27,18 @@ func (r *storeRepository) Create(store *entities.Store) error {
 		return err
 	}
 	if exists {
-		return errors.New("store slug already exists")
+		return ErrStoreSlugExists
 	}
-	return r.db.Create(store).Error
+	return translateSlugConflict(r.db.Create(store).Error)
+}
+
+// translateSlugConflict reports a violation of the unique slug index, which catches
+// concurrent writers that passed the SlugExists check, as ErrStoreSlugExists
+func translateSlugConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrStoreSlugExists
+	}
+	return err
 }
 
 func (r *storeRepository) GetByID(id string) (*entities.Store, error) {
@@ -59,7 +69,7 @@ func (r *storeRepository) GetByUserID(userID string, limit, offset int) ([]entit
 	var stores []entities.Store
 
 	query := r.db.
-		Joins("INNER JOIN user_store_roles ON stores.id = user_store_roles.store_id").
+		Joins("INNER JOIN user_store_roles ON stores.id = user_store_roles.store_id AND user_store_roles.deleted_at IS NULL").
 		Where("user_store_roles.user_id = ? AND user_store_roles.is_active = ?", userID, true).
 		Order("stores.created_at DESC")
 
@@ -74,8 +84,23 @@ func (r *storeRepository) GetByUserID(userID string, limit, offset int) ([]entit
 	return stores, err
 }
 
+// Update saves the store only if it is still at the version it was read at, and bumps
+// the version; otherwise it returns ErrStoreVersionConflict and leaves the row untouched.
+// Selecting "*" explicitly stops Save from falling back to an upsert when no row matches.
 func (r *storeRepository) Update(store *entities.Store) error {
-	return r.db.Save(store).Error
+	version := store.Version
+	store.Version++
+
+	result := r.db.Select("*").Where("version = ?", version).Save(store)
+	if result.Error != nil {
+		store.Version = version
+		return translateSlugConflict(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		store.Version = version
+		return ErrStoreVersionConflict
+	}
+	return nil
 }
 
 func (r *storeRepository) Delete(id string) error {
@@ -90,8 +115,12 @@ func (r *storeRepository) GetStoresByFilter(filter repositories.StoreFilter) ([]
 
 	if filter.UserID != "" {
 		query = query.
-			Joins("INNER JOIN user_store_roles ON stores.id = user_store_roles.store_id").
+			Joins("INNER JOIN user_store_roles ON stores.id = user_store_roles.store_id AND user_store_roles.deleted_at IS NULL").
 			Where("user_store_roles.user_id = ? AND user_store_roles.is_active = ?", filter.UserID, true)
+
+		if filter.Role != "" {
+			query = query.Where("user_store_roles.role = ?", filter.Role)
+		}
 	}
 
 	if filter.IsActive != nil {
@@ -1,26 +1,74 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/repositories"
 	"gorm.io/gorm"
 )
 
+var ErrAlreadyMember = errors.New("user is already a member of this store")
+
 type userStoreRoleRepository struct {
 	db *gorm.DB
 }
 
+// activeMembership limits a query to memberships that grant access. Every lookup used for
+// an access check goes through it, so suspended members are denied consistently.
+func activeMembership(db *gorm.DB) *gorm.DB {
+	return db.Where("is_active = ?", true)
+}
+
 func NewUserStoreRoleRepository(db *gorm.DB) repositories.UserStoreRoleRepository {
 	return &userStoreRoleRepository{db: db}
 }
 
 func (r *userStoreRoleRepository) Create(role *entities.UserStoreRole) error {
-	return r.db.Create(role).Error
+	return createMembership(r.db, role)
+}
+
+// createMembership inserts role, reporting a unique (user_id, store_id) violation as ErrAlreadyMember
+func createMembership(db *gorm.DB, role *entities.UserStoreRole) error {
+	if err := db.Create(role).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyMember
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *userStoreRoleRepository) GetByUserAndStore(userID, storeID string) (*entities.UserStoreRole, error) {
 	var role entities.UserStoreRole
-	err := r.db.Where("user_id = ? AND store_id = ? AND is_active = ?", userID, storeID, true).
+	err := r.db.Scopes(activeMembership).
+		Where("user_id = ? AND store_id = ?", userID, storeID).
+		First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetMembershipIncludingSuspended returns the membership whether or not it is suspended.
+// Access checks must use GetUserRole or GetByUserAndStore instead.
+func (r *userStoreRoleRepository) GetMembershipIncludingSuspended(userID, storeID string) (*entities.UserStoreRole, error) {
+	var role entities.UserStoreRole
+	err := r.db.Where("user_id = ? AND store_id = ?", userID, storeID).
+		First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetByEmailAndStore matches the member email case-insensitively; memberships created
+// before emails were recorded have none and are never matched
+func (r *userStoreRoleRepository) GetByEmailAndStore(email, storeID string) (*entities.UserStoreRole, error) {
+	var role entities.UserStoreRole
+	err := r.db.Scopes(activeMembership).
+		Where("LOWER(email) = LOWER(?) AND store_id = ?", email, storeID).
 		First(&role).Error
 	if err != nil {
 		return nil, err
@@ -28,9 +76,11 @@ func (r *userStoreRoleRepository) GetByUserAndStore(userID, storeID string) (*en
 	return &role, nil
 }
 
+// GetByStoreID lists every member of the store, suspended ones included so they can be
+// found and restored
 func (r *userStoreRoleRepository) GetByStoreID(storeID string) ([]entities.UserStoreRole, error) {
 	var roles []entities.UserStoreRole
-	err := r.db.Where("store_id = ? AND is_active = ?", storeID, true).
+	err := r.db.Where("store_id = ?", storeID).
 		Order("created_at ASC").
 		Find(&roles).Error
 	return roles, err
@@ -39,16 +89,46 @@ func (r *userStoreRoleRepository) GetByStoreID(storeID string) ([]entities.UserS
 func (r *userStoreRoleRepository) GetByUserID(userID string) ([]entities.UserStoreRole, error) {
 	var roles []entities.UserStoreRole
 	err := r.db.Preload("Store").
-		Where("user_id = ? AND is_active = ?", userID, true).
+		Scopes(activeMembership).
+		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&roles).Error
 	return roles, err
 }
 
+// TouchLastActive moves the member's last activity forward to at. It only writes the one
+// column and never moves it backwards, so concurrent touches are harmless.
+func (r *userStoreRoleRepository) TouchLastActive(userID, storeID string, at time.Time) error {
+	return r.db.Model(&entities.UserStoreRole{}).
+		Scopes(activeMembership).
+		Where("user_id = ? AND store_id = ?", userID, storeID).
+		Where("last_active_at IS NULL OR last_active_at < ?", at).
+		UpdateColumn("last_active_at", at).Error
+}
+
+// GetInactiveByStoreID returns members whose last activity, or join date when they have
+// never acted, is before since, longest inactive first
+func (r *userStoreRoleRepository) GetInactiveByStoreID(storeID string, since time.Time) ([]entities.UserStoreRole, error) {
+	var roles []entities.UserStoreRole
+	err := r.db.Scopes(activeMembership).
+		Where("store_id = ?", storeID).
+		Where("COALESCE(last_active_at, joined_at) < ?", since).
+		Order("COALESCE(last_active_at, joined_at) ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
 func (r *userStoreRoleRepository) Update(role *entities.UserStoreRole) error {
 	return r.db.Save(role).Error
 }
 
+// SetActive suspends or restores a membership without touching its other columns
+func (r *userStoreRoleRepository) SetActive(userID, storeID string, active bool) error {
+	return r.db.Model(&entities.UserStoreRole{}).
+		Where("user_id = ? AND store_id = ?", userID, storeID).
+		Update("is_active", active).Error
+}
+
 func (r *userStoreRoleRepository) Delete(userID, storeID string) error {
 	return r.db.Where("user_id = ? AND store_id = ?", userID, storeID).
 		Delete(&entities.UserStoreRole{}).Error
@@ -56,7 +136,8 @@ func (r *userStoreRoleRepository) Delete(userID, storeID string) error {
 
 func (r *userStoreRoleRepository) GetUserRole(userID, storeID string) (entities.StoreRole, error) {
 	var role entities.UserStoreRole
-	err := r.db.Where("user_id = ? AND store_id = ? AND is_active = ?", userID, storeID, true).
+	err := r.db.Scopes(activeMembership).
+		Where("user_id = ? AND store_id = ?", userID, storeID).
 		First(&role).Error
 	if err != nil {
 		return "", err
@@ -64,17 +145,36 @@ func (r *userStoreRoleRepository) GetUserRole(userID, storeID string) (entities.
 	return role.Role, nil
 }
 
+// GetUserRolesForStores returns the user's role in each of storeIDs keyed by store ID;
+// stores where the user has no active role are absent from the map
+func (r *userStoreRoleRepository) GetUserRolesForStores(userID string, storeIDs []string) (map[string]entities.StoreRole, error) {
+	roles := make(map[string]entities.StoreRole, len(storeIDs))
+	if len(storeIDs) == 0 {
+		return roles, nil
+	}
+
+	var memberships []entities.UserStoreRole
+	err := r.db.Select("store_id", "role").
+		Scopes(activeMembership).
+		Where("user_id = ? AND store_id IN ?", userID, storeIDs).
+		Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, membership := range memberships {
+		roles[membership.StoreID] = membership.Role
+	}
+	return roles, nil
+}
+
 func (r *userStoreRoleRepository) HasPermission(userID, storeID string, requiredRole entities.StoreRole) (bool, error) {
 	userRole, err := r.GetUserRole(userID, storeID)
 	if err != nil {
 		return false, err
 	}
 
-	hierarchy := entities.GetRoleHierarchy()
-	userLevel := hierarchy[userRole]
-	requiredLevel := hierarchy[requiredRole]
-
-	return userLevel >= requiredLevel, nil
+	return userRole.AtLeast(requiredRole), nil
 }
 
 func (r *userStoreRoleRepository) IsStoreOwner(userID, storeID string) (bool, error) {
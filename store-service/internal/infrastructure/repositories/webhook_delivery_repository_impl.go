@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) repositories.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Create(delivery *entities.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookDeliveryRepository) Update(delivery *entities.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
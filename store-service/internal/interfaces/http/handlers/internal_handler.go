@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/utils"
+)
+
+type InternalHandler struct {
+	storeService services.StoreService
+}
+
+func NewInternalHandler(storeService services.StoreService) *InternalHandler {
+	return &InternalHandler{
+		storeService: storeService,
+	}
+}
+
+// GetMembership returns a user's role and permissions in a store for other services
+func (h *InternalHandler) GetMembership(c *fiber.Ctx) error {
+	// Only reachable service-to-service; Kong does not route /api/internal here
+	if c.Get("X-Internal-Service") == "" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied")
+	}
+
+	storeID := c.Params("id")
+	userID := c.Params("userId")
+	if storeID == "" || userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID and user ID are required")
+	}
+
+	membership, err := h.storeService.GetMembership(storeID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "User is not a member of this store")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "Store membership retrieved", membership)
+}
+
+// GetStore returns a store's settings for other services
+func (h *InternalHandler) GetStore(c *fiber.Ctx) error {
+	if c.Get("X-Internal-Service") == "" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Access denied")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	store, err := h.storeService.GetInternalStore(storeID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Store not found")
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "Store retrieved", store)
+}
@@ -2,24 +2,54 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/domain/services"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/utils"
 )
 
 type StoreHandler struct {
-	storeService services.StoreService
-	validator    *validator.Validate
+	storeService   services.StoreService
+	validator      *validator.Validate
+	maxUploadBytes int64
 }
 
-func NewStoreHandler(storeService services.StoreService) *StoreHandler {
+func NewStoreHandler(storeService services.StoreService, maxUploadBytes int64) *StoreHandler {
 	return &StoreHandler{
-		storeService: storeService,
-		validator:    validator.New(),
+		storeService:   storeService,
+		validator:      utils.NewValidator(),
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// storeError writes err with the status and code of its store service error kind, falling
+// back to fallbackStatus for errors the service does not classify
+func storeError(c *fiber.Ctx, err error, fallbackStatus int) error {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return utils.ValidationMessagesResponse(c, validationErr.Errors)
+	}
+
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return utils.ErrorResponseWithCode(c, fiber.StatusNotFound, utils.CodeNotFound, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		return utils.ErrorResponseWithCode(c, fiber.StatusForbidden, utils.CodeForbidden, err.Error())
+	case errors.Is(err, services.ErrConflict):
+		return utils.ErrorResponseWithCode(c, fiber.StatusConflict, utils.CodeConflict, err.Error())
+	case errors.Is(err, services.ErrInvalid):
+		return utils.ErrorResponseWithCode(c, fiber.StatusBadRequest, utils.CodeValidation, err.Error())
+	case errors.Is(err, services.ErrRateLimited):
+		return utils.ErrorResponseWithCode(c, fiber.StatusTooManyRequests, utils.CodeRateLimited, err.Error())
+	default:
+		return utils.ErrorResponse(c, fallbackStatus, err.Error())
 	}
 }
 
@@ -38,12 +68,13 @@ func (h *StoreHandler) CreateStore(c *fiber.Ctx) error {
 		return utils.ValidationErrorResponse(c, err)
 	}
 
-	store, err := h.storeService.CreateStore(userID, req)
+	store, err := h.storeService.CreateStore(userID, c.Get("X-User-Email"), req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
-	return utils.SuccessResponse(c, "Store created successfully", store)
+	c.Set(fiber.HeaderLocation, "/api/stores/"+store.ID)
+	return utils.CreatedResponse(c, "Store created successfully", store)
 }
 
 func (h *StoreHandler) GetStore(c *fiber.Ctx) error {
@@ -62,7 +93,7 @@ func (h *StoreHandler) GetStore(c *fiber.Ctx) error {
 		if errors.Is(err, services.ErrNotFound) {
 			return utils.ErrorResponse(c, fiber.StatusNotFound, "Store not found")
 		}
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return storeError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "Store retrieved successfully", store)
@@ -84,7 +115,7 @@ func (h *StoreHandler) GetStoreBySlug(c *fiber.Ctx) error {
 		if errors.Is(err, services.ErrNotFound) {
 			return utils.ErrorResponse(c, fiber.StatusNotFound, "Store not found")
 		}
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return storeError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "Store retrieved successfully", store)
@@ -106,9 +137,14 @@ func (h *StoreHandler) GetUserStores(c *fiber.Ctx) error {
 		perPage = 10
 	}
 
-	stores, err := h.storeService.GetUserStores(userID, page, perPage)
+	role := entities.StoreRole(strings.ToUpper(c.Query("role")))
+	if role != "" && !role.IsValid() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid role, must be one of OWNER, ADMIN, MANAGER, MEMBER")
+	}
+
+	stores, err := h.storeService.GetUserStores(userID, role, page, perPage)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return storeError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "Stores retrieved successfully", stores)
@@ -136,7 +172,7 @@ func (h *StoreHandler) UpdateStore(c *fiber.Ctx) error {
 
 	store, err := h.storeService.UpdateStore(storeID, userID, req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Store updated successfully", store)
@@ -155,7 +191,7 @@ func (h *StoreHandler) DeleteStore(c *fiber.Ctx) error {
 
 	err := h.storeService.DeleteStore(storeID, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Store deleted successfully", nil)
@@ -185,12 +221,49 @@ func (h *StoreHandler) InviteMember(c *fiber.Ctx) error {
 
 	invitation, err := h.storeService.InviteMember(storeID, userID, req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Member invited successfully", invitation)
 }
 
+func (h *StoreHandler) InviteMembers(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	var req dto.BulkInviteMembersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return utils.ValidationErrorResponse(c, err)
+	}
+
+	result, err := h.storeService.InviteMembers(storeID, userID, req.Emails, req.Role)
+	if err != nil {
+		return storeError(c, err, fiber.StatusBadRequest)
+	}
+
+	return utils.SuccessResponse(c, "Invitations processed", result)
+}
+
+func (h *StoreHandler) PreviewInvitation(c *fiber.Ctx) error {
+	invitation, err := h.storeService.PreviewInvitation(c.Params("token"))
+	if err != nil {
+		return storeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return utils.SuccessResponse(c, "Invitation retrieved successfully", invitation)
+}
+
 func (h *StoreHandler) AcceptInvitation(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
@@ -208,12 +281,80 @@ func (h *StoreHandler) AcceptInvitation(c *fiber.Ctx) error {
 
 	err := h.storeService.AcceptInvitation(userID, req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Invitation accepted successfully", nil)
 }
 
+func (h *StoreHandler) GetStoreDashboard(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	dashboard, err := h.storeService.GetStoreDashboard(storeID, userID)
+	if err != nil {
+		return storeError(c, err, fiber.StatusForbidden)
+	}
+
+	return utils.SuccessResponse(c, "Store dashboard retrieved successfully", dashboard)
+}
+
+func (h *StoreHandler) UploadLogo(c *fiber.Ctx) error {
+	return h.uploadStoreImage(c, services.StoreImageLogo, "Store logo uploaded successfully")
+}
+
+func (h *StoreHandler) UploadBanner(c *fiber.Ctx) error {
+	return h.uploadStoreImage(c, services.StoreImageBanner, "Store banner uploaded successfully")
+}
+
+// uploadStoreImage reads the multipart "file" field and hands it to the service, which
+// validates the image and sets it on the store
+func (h *StoreHandler) uploadStoreImage(c *fiber.Ctx, slot services.StoreImage, message string) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return utils.ValidationMessagesResponse(c, []string{"file is required"})
+	}
+	if header.Size > h.maxUploadBytes {
+		return utils.ErrorResponseWithCode(c, fiber.StatusRequestEntityTooLarge, utils.CodeValidation,
+			fmt.Sprintf("File must be at most %d bytes", h.maxUploadBytes))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Could not read uploaded file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxUploadBytes+1))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Could not read uploaded file")
+	}
+
+	store, err := h.storeService.UploadStoreImage(storeID, userID, slot, data)
+	if err != nil {
+		return storeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return utils.SuccessResponse(c, message, store)
+}
+
 func (h *StoreHandler) GetStoreMembers(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
@@ -227,12 +368,36 @@ func (h *StoreHandler) GetStoreMembers(c *fiber.Ctx) error {
 
 	members, err := h.storeService.GetStoreMembers(storeID, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Store members retrieved successfully", members)
 }
 
+func (h *StoreHandler) GetInactiveMembers(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "days must be a whole number")
+	}
+
+	members, err := h.storeService.GetInactiveMembers(storeID, userID, days)
+	if err != nil {
+		return storeError(c, err, fiber.StatusBadRequest)
+	}
+
+	return utils.SuccessResponse(c, "Inactive members retrieved successfully", members)
+}
+
 func (h *StoreHandler) GetStoreInvitations(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
@@ -246,7 +411,7 @@ func (h *StoreHandler) GetStoreInvitations(c *fiber.Ctx) error {
 
 	invitations, err := h.storeService.GetStoreInvitations(storeID, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Store invitations retrieved successfully", invitations)
@@ -279,7 +444,7 @@ func (h *StoreHandler) UpdateMemberRole(c *fiber.Ctx) error {
 
 	err := h.storeService.UpdateMemberRole(storeID, memberID, userID, req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Member role updated successfully", nil)
@@ -303,12 +468,43 @@ func (h *StoreHandler) RemoveMember(c *fiber.Ctx) error {
 
 	err := h.storeService.RemoveMember(storeID, memberID, userID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return storeError(c, err, fiber.StatusBadRequest)
 	}
 
 	return utils.SuccessResponse(c, "Member removed successfully", nil)
 }
 
+func (h *StoreHandler) SuspendMember(c *fiber.Ctx) error {
+	return h.setMemberActive(c, h.storeService.SuspendMember, "Member suspended successfully")
+}
+
+func (h *StoreHandler) UnsuspendMember(c *fiber.Ctx) error {
+	return h.setMemberActive(c, h.storeService.UnsuspendMember, "Member unsuspended successfully")
+}
+
+func (h *StoreHandler) setMemberActive(c *fiber.Ctx, apply func(storeID, memberUserID, requesterID string) error, message string) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User ID is required")
+	}
+
+	storeID := c.Params("id")
+	if storeID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Store ID is required")
+	}
+
+	memberID := c.Params("memberId")
+	if memberID == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Member ID is required")
+	}
+
+	if err := apply(storeID, memberID, userID); err != nil {
+		return storeError(c, err, fiber.StatusBadRequest)
+	}
+
+	return utils.SuccessResponse(c, message, nil)
+}
+
 func (h *StoreHandler) GetUserInvitations(c *fiber.Ctx) error {
 	userEmail := c.Get("X-User-Email")
 	if userEmail == "" {
@@ -317,7 +513,7 @@ func (h *StoreHandler) GetUserInvitations(c *fiber.Ctx) error {
 
 	invitations, err := h.storeService.GetUserInvitations(userEmail)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		return storeError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return utils.SuccessResponse(c, "User invitations retrieved successfully", invitations)
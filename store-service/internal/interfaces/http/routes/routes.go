@@ -5,8 +5,11 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/application/services"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/docs"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/infrastructure/external"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/middleware"
 	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/utils"
 	"gorm.io/gorm"
 )
@@ -22,12 +25,39 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 	storeRepo := repositories.NewStoreRepository(deps.Db)
 	roleRepo := repositories.NewUserStoreRoleRepository(deps.Db)
 	invitationRepo := repositories.NewStoreInvitationRepository(deps.Db)
+	webhookRepo := repositories.NewWebhookDeliveryRepository(deps.Db)
+
+	// Initialize external clients
+	mailer := external.NewMailer(&deps.Config.SMTP)
+	webhookSender := external.NewWebhookClient(deps.Config.Store.WebhookTimeout, deps.Config.Store.WebhookAllowPrivate)
+	productService := external.NewProductServiceClient(deps.Config.ProductServiceURL)
+	storage := external.NewLocalStorage(&deps.Config.Uploads)
+	rateLimiter := external.NewRedisRateLimiter(deps.RedisClient)
 
 	// Initialize services
-	storeService := services.NewStoreService(storeRepo, roleRepo, invitationRepo)
+	storeService := services.NewStoreService(
+		storeRepo,
+		roleRepo,
+		invitationRepo,
+		webhookRepo,
+		mailer,
+		webhookSender,
+		productService,
+		storage,
+		rateLimiter,
+		&deps.Config.Store,
+		&deps.Config.Uploads,
+	)
 
 	// Initialize handlers
-	storeHandler := handlers.NewStoreHandler(storeService)
+	storeHandler := handlers.NewStoreHandler(storeService, deps.Config.Uploads.MaxSize)
+	internalHandler := handlers.NewInternalHandler(storeService)
+
+	// OpenAPI description and Swagger UI
+	docs.Register(app)
+
+	// Uploaded store images, stored on local disk
+	app.Static("/uploads", deps.Config.Uploads.Dir)
 
 	// API routes
 	api := app.Group("/api")
@@ -37,8 +67,9 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 		return utils.SuccessResponse(c, "Store service is healthy", nil)
 	})
 
-	// Store routes
-	stores := api.Group("/stores")
+	// Store routes; acting on a store refreshes the caller's last activity there
+	memberActivity := middleware.NewMemberActivity(roleRepo, deps.Config.Store.ActivityTouchInterval)
+	stores := api.Group("/stores", memberActivity.Handler())
 	{
 		stores.Post("/", storeHandler.CreateStore)
 		stores.Get("/", storeHandler.GetUserStores)
@@ -46,13 +77,20 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 		stores.Get("/slug/:slug", storeHandler.GetStoreBySlug)
 		stores.Put("/:id", storeHandler.UpdateStore)
 		stores.Delete("/:id", storeHandler.DeleteStore)
+		stores.Get("/:id/dashboard", storeHandler.GetStoreDashboard)
+		stores.Post("/:id/logo", storeHandler.UploadLogo)
+		stores.Post("/:id/banner", storeHandler.UploadBanner)
 
 		// Member management
 		stores.Post("/:id/invite", storeHandler.InviteMember)
+		stores.Post("/:id/invite/bulk", storeHandler.InviteMembers)
 		stores.Get("/:id/members", storeHandler.GetStoreMembers)
+		stores.Get("/:id/members/inactive", storeHandler.GetInactiveMembers)
 		stores.Get("/:id/invitations", storeHandler.GetStoreInvitations)
 		stores.Put("/:id/members/:memberId/role", storeHandler.UpdateMemberRole)
 		stores.Delete("/:id/members/:memberId", storeHandler.RemoveMember)
+		stores.Post("/:id/members/:memberId/suspend", storeHandler.SuspendMember)
+		stores.Post("/:id/members/:memberId/unsuspend", storeHandler.UnsuspendMember)
 	}
 
 	// Invitation routes
@@ -60,6 +98,13 @@ func SetupRoutes(app *fiber.App, deps RoutesDependencies) {
 	{
 		invitations.Get("/", storeHandler.GetUserInvitations)    // Get all user's invitations
 		invitations.Post("/accept", storeHandler.AcceptInvitation) // Accept an invitation
+		invitations.Get("/:token", storeHandler.PreviewInvitation) // Public lookup by token
 	}
 
+	// Internal API for other services
+	internal := api.Group("/internal")
+	{
+		internal.Get("/stores/:id", internalHandler.GetStore)
+		internal.Get("/stores/:id/members/:userId", internalHandler.GetMembership)
+	}
 }
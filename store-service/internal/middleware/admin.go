@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/store-service/internal/utils"
+)
+
+// AdminPathPrefix groups the operator endpoints. Kong does not route it, so it is
+// only reachable from inside the network.
+const AdminPathPrefix = "/admin"
+
+// AdminOnly requires the X-Admin-Token header to match token. An empty token
+// disables the admin endpoints entirely.
+func AdminOnly(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" || subtle.ConstantTimeCompare([]byte(c.Get("X-Admin-Token")), []byte(token)) != 1 {
+			return utils.ErrorResponseWithCode(c, fiber.StatusForbidden, "ACCESS_DENIED", "Access denied")
+		}
+		return c.Next()
+	}
+}
+
+// ReloadEndpoint runs reload and reports the settings now in effect. When reload fails
+// the settings in effect are left unchanged.
+func ReloadEndpoint(reload func() (map[string]any, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		settings, err := reload()
+		if err != nil {
+			log.Error().Err(err).Str("ip", c.IP()).Msg("Configuration reload failed")
+			// Admin only, so the cause is returned to help fix the reload file
+			return utils.ErrorResponseWithCode(c, fiber.StatusInternalServerError, "CONFIG_RELOAD_FAILED", "Configuration reload failed: "+err.Error())
+		}
+		log.Info().Str("ip", c.IP()).Interface("settings", settings).Msg("Configuration reloaded")
+
+		return utils.SuccessResponse(c, "Configuration reloaded", settings)
+	}
+}
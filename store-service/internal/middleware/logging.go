@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
+	"math/rand/v2"
 	"fmt"
-	"log"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
 type LogEntry struct {
@@ -43,7 +46,41 @@ var headerMapPool = sync.Pool{
 
 const maxMaskingDepth = 5 // Limit recursion depth for performance
 
-func RequestResponseLogger() fiber.Handler {
+// SetLogLevel sets the global log level from debug, info, warn or error,
+// falling back to info for anything else
+func SetLogLevel(level string) {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || parsed == zerolog.NoLevel {
+		log.Warn().Str("log_level", level).Msg("Unknown log level, defaulting to info")
+		parsed = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(parsed)
+}
+
+// sampleBody reports whether this request falls in the 1-in-rate body logging sample;
+// a rate of 1 or less samples every request
+func sampleBody(rate int) bool {
+	return rate <= 1 || rand.IntN(rate) == 0
+}
+
+// responseStatus returns the status the client will receive. A handler error only becomes
+// a response in the app's error handler, after this middleware returns, so it is derived
+// from the error rather than read from the response.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// RequestResponseLogger always logs a summary line per request. At debug level it also logs
+// headers and bodies for every handler error and non-2xx response, and for a
+// 1-in-bodySampleRate sample of the rest.
+func RequestResponseLogger(bodySampleRate func() int) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
@@ -59,6 +96,32 @@ func RequestResponseLogger() fiber.Handler {
 			requestID = "missing"
 		}
 
+		// Process request
+		err := c.Next()
+
+		// Calculate duration
+		duration := time.Since(start)
+
+		status := responseStatus(c, err)
+		event := log.Info().
+			Str("request_id", requestID).
+			Str("method", c.Method()).
+			Str("path", c.Path()).
+			Int("status_code", status).
+			Str("duration", formatDuration(duration))
+		if err != nil {
+			event = event.Str("error", err.Error())
+		}
+		event.Send()
+
+		// Headers and bodies can carry PII, so they are only logged at debug level
+		if zerolog.GlobalLevel() > zerolog.DebugLevel {
+			return err
+		}
+		if err == nil && status >= 200 && status < 300 && !sampleBody(bodySampleRate()) {
+			return err
+		}
+
 		// Get pooled objects
 		logEntry := logEntryPool.Get().(*LogEntry)
 		headers := headerMapPool.Get().(map[string]string)
@@ -86,12 +149,6 @@ func RequestResponseLogger() fiber.Handler {
 			}
 		})
 
-		// Process request
-		err := c.Next()
-
-		// Calculate duration
-		duration := time.Since(start)
-
 		// Lazy evaluation for response body
 		var responseBody any
 		respBody := c.Response().Body()
@@ -112,7 +169,7 @@ func RequestResponseLogger() fiber.Handler {
 		logEntry.UserAgent = c.Get("User-Agent")
 		logEntry.Headers = headers
 		logEntry.RequestBody = requestBody
-		logEntry.StatusCode = c.Response().StatusCode()
+		logEntry.StatusCode = status
 		logEntry.ResponseBody = responseBody
 		logEntry.Duration = formatDuration(duration)
 		logEntry.Error = ""
@@ -122,9 +179,8 @@ func RequestResponseLogger() fiber.Handler {
 			logEntry.Error = err.Error()
 		}
 
-		// Use structured logging instead of fmt.Println
 		if logJSON, marshalErr := json.Marshal(logEntry); marshalErr == nil {
-			log.Println(string(logJSON))
+			log.Debug().RawJSON("request", logJSON).Send()
 		}
 
 		// Return objects to pool
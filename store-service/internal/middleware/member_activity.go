@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// MemberActivityStore records when a member last acted on a store
+type MemberActivityStore interface {
+	TouchLastActive(userID, storeID string, at time.Time) error
+}
+
+// maxTrackedActivity bounds how many member/store pairs the throttle remembers
+const maxTrackedActivity = 10000
+
+// MemberActivity refreshes a member's last activity after each successful request on one
+// of their stores. Writes are throttled per member and store to one per interval and run
+// in the background, so they add no latency and little database load.
+type MemberActivity struct {
+	store    MemberActivityStore
+	interval time.Duration
+
+	mu          sync.Mutex
+	lastTouched map[string]time.Time
+}
+
+func NewMemberActivity(store MemberActivityStore, interval time.Duration) *MemberActivity {
+	return &MemberActivity{
+		store:       store,
+		interval:    interval,
+		lastTouched: make(map[string]time.Time),
+	}
+}
+
+// Handler touches the activity of the X-User-Id member in the store named by the :id
+// route parameter. It runs after the route handler so the parameter is resolved.
+func (m *MemberActivity) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest {
+			return err
+		}
+
+		// Fiber reuses the memory behind header and parameter values once the request
+		// ends, so the background write needs its own copies
+		userID := utils.CopyString(c.Get("X-User-Id"))
+		storeID := utils.CopyString(c.Params("id"))
+		if userID == "" || storeID == "" {
+			return nil
+		}
+
+		now := time.Now()
+		if !m.due(userID+"|"+storeID, now) {
+			return nil
+		}
+
+		go func() {
+			if err := m.store.TouchLastActive(userID, storeID, now); err != nil {
+				log.Warn().Err(err).Str("user_id", userID).Str("store_id", storeID).Msg("failed to record member activity")
+			}
+		}()
+		return nil
+	}
+}
+
+// due reports whether key was last touched at least an interval ago and, if so, marks
+// it touched at now. Once maxTrackedActivity pairs are tracked, stale entries are pruned
+// and, if all are recent, arbitrary ones are dropped; a dropped pair is only written again
+// sooner than the interval.
+func (m *MemberActivity) due(key string, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if last, ok := m.lastTouched[key]; ok && now.Sub(last) < m.interval {
+		return false
+	}
+
+	if len(m.lastTouched) >= maxTrackedActivity {
+		for k, last := range m.lastTouched {
+			if now.Sub(last) >= m.interval {
+				delete(m.lastTouched, k)
+			}
+		}
+		for k := range m.lastTouched {
+			if len(m.lastTouched) < maxTrackedActivity {
+				break
+			}
+			delete(m.lastTouched, k)
+		}
+	}
+	m.lastTouched[key] = now
+	return true
+}
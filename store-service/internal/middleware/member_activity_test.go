@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type touch struct {
+	userID, storeID string
+}
+
+// recordingActivity sends every touch to a channel
+type recordingActivity chan touch
+
+func (r recordingActivity) TouchLastActive(userID, storeID string, at time.Time) error {
+	r <- touch{userID, storeID}
+	return nil
+}
+
+func TestMemberActivityRecordsTheRequestIDs(t *testing.T) {
+	touches := make(recordingActivity, 10)
+	activity := NewMemberActivity(touches, time.Hour)
+	app := fiber.New()
+	app.Get("/stores/:id", activity.Handler(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	want := []touch{{"user-1", "store-1"}, {"user-2", "store-2"}, {"user-1", "store-1"}}
+	for _, request := range want {
+		req := httptest.NewRequest(fiber.MethodGet, "/stores/"+request.storeID, nil)
+		req.Header.Set("X-User-Id", request.userID)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// The third request repeats the first within the interval, so only two are written,
+	// in whichever order their goroutines ran
+	got := map[touch]bool{}
+	for range 2 {
+		select {
+		case recorded := <-touches:
+			got[recorded] = true
+		case <-time.After(time.Second):
+			t.Fatalf("touched %+v, want %+v", got, want[:2])
+		}
+	}
+	for _, expected := range want[:2] {
+		if !got[expected] {
+			t.Errorf("touched %+v, want %+v", got, want[:2])
+		}
+	}
+	select {
+	case got := <-touches:
+		t.Errorf("throttled request touched %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMemberActivityTracksAtMostMaxPairs(t *testing.T) {
+	activity := NewMemberActivity(nil, time.Hour)
+	now := time.Now()
+
+	for i := range maxTrackedActivity + 100 {
+		if !activity.due(fmt.Sprintf("user-%d|store", i), now) {
+			t.Fatalf("pair %d was throttled before it was touched", i)
+		}
+	}
+	if tracked := len(activity.lastTouched); tracked > maxTrackedActivity {
+		t.Errorf("tracking %d pairs, want at most %d", tracked, maxTrackedActivity)
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "github.com/gofiber/fiber/v2"
+
+// Machine-readable error codes returned in the code field of error responses, so
+// clients can branch on them instead of matching message text
+const (
+	CodeValidation         = "VALIDATION"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeMethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	CodeConflict           = "CONFLICT"
+	CodeInsufficientStock  = "INSUFFICIENT_STOCK"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	CodeInternal           = "INTERNAL"
+)
+
+// CodeForStatus returns the default error code for an HTTP status, used when a handler
+// does not pick a more specific one
+func CodeForStatus(statusCode int) string {
+	switch statusCode {
+	case fiber.StatusBadRequest, fiber.StatusUnprocessableEntity:
+		return CodeValidation
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusTooManyRequests:
+		return CodeRateLimited
+	case fiber.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		if statusCode >= fiber.StatusInternalServerError {
+			return CodeInternal
+		}
+		return ""
+	}
+}
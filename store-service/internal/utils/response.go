@@ -1,15 +1,16 @@
 package utils
 
 import (
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 type Response struct {
 	Success   bool        `json:"success"`
+	Code      string      `json:"code,omitempty"`
 	Message   string      `json:"message"`
 	Data      interface{} `json:"data,omitempty"`
 	Error     interface{} `json:"error,omitempty"`
+	Errors    interface{} `json:"errors,omitempty"`
 	RequestID string      `json:"request_id,omitempty"`
 }
 
@@ -23,52 +24,28 @@ func SuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
 	})
 }
 
-func ErrorResponse(c *fiber.Ctx, statusCode int, message string) error {
+func CreatedResponse(c *fiber.Ctx, message string, data interface{}) error {
 	requestID := getRequestID(c)
-	return c.Status(statusCode).JSON(Response{
-		Success:   false,
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success:   true,
 		Message:   message,
+		Data:      data,
 		RequestID: requestID,
 	})
 }
 
-func ValidationErrorResponse(c *fiber.Ctx, err error) error {
-	var validationErrors []string
-
-	if validationErrs, ok := err.(validator.ValidationErrors); ok {
-		for _, validationErr := range validationErrs {
-			field := validationErr.Field()
-			tag := validationErr.Tag()
-
-			var message string
-			switch tag {
-			case "required":
-				message = field + " is required"
-			case "email":
-				message = field + " must be a valid email address"
-			case "min":
-				message = field + " must be at least " + validationErr.Param() + " characters long"
-			case "max":
-				message = field + " must be at most " + validationErr.Param() + " characters long"
-			case "url":
-				message = field + " must be a valid URL"
-			case "alphanum":
-				message = field + " must contain only alphanumeric characters"
-			case "oneof":
-				message = field + " must be one of: " + validationErr.Param()
-			default:
-				message = field + " is invalid"
-			}
-
-			validationErrors = append(validationErrors, message)
-		}
-	}
+func ErrorResponse(c *fiber.Ctx, statusCode int, message string) error {
+	return ErrorResponseWithCode(c, statusCode, CodeForStatus(statusCode), message)
+}
 
+// ErrorResponseWithCode writes an error envelope carrying a machine-readable code alongside the message
+func ErrorResponseWithCode(c *fiber.Ctx, statusCode int, code string, message string) error {
 	requestID := getRequestID(c)
-	return c.Status(fiber.StatusBadRequest).JSON(Response{
+	return c.Status(statusCode).JSON(Response{
 		Success:   false,
-		Message:   "Validation failed",
-		Error:     validationErrors,
+		Code:      code,
+		Message:   message,
+		Error:     message,
 		RequestID: requestID,
 	})
 }
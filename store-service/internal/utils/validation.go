@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldError describes a single failed rule on a request field; Field and Tag are empty
+// for failures that are not tied to a struct tag
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewValidator returns a validator that reports fields by the JSON names clients send
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
+
+// ValidationErrorResponse writes one FieldError per failed rule in err, which is normally
+// the validator.ValidationErrors returned by Struct
+func ValidationErrorResponse(c *fiber.Ctx, err error) error {
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return validationResponse(c, []FieldError{{Message: err.Error()}})
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   validationErr.Field(),
+			Tag:     validationErr.Tag(),
+			Message: fieldErrorMessage(validationErr),
+		})
+	}
+	return validationResponse(c, fieldErrors)
+}
+
+// ValidationMessagesResponse writes a validation failure envelope from already-formatted messages
+func ValidationMessagesResponse(c *fiber.Ctx, messages []string) error {
+	fieldErrors := make([]FieldError, 0, len(messages))
+	for _, message := range messages {
+		fieldErrors = append(fieldErrors, FieldError{Message: message})
+	}
+	return validationResponse(c, fieldErrors)
+}
+
+// validationResponse lists the failures under errors and keeps their messages under error
+// for clients that predate the structured form
+func validationResponse(c *fiber.Ctx, fieldErrors []FieldError) error {
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		messages = append(messages, fieldErr.Message)
+	}
+
+	requestID := getRequestID(c)
+	return c.Status(fiber.StatusBadRequest).JSON(Response{
+		Success:   false,
+		Code:      CodeValidation,
+		Message:   "Validation failed",
+		Error:     messages,
+		Errors:    fieldErrors,
+		RequestID: requestID,
+	})
+}
+
+func fieldErrorMessage(validationErr validator.FieldError) string {
+	field := validationErr.Field()
+	param := validationErr.Param()
+
+	switch validationErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "min":
+		switch validationErr.Kind() {
+		case reflect.String:
+			return field + " must be at least " + param + " characters long"
+		case reflect.Slice, reflect.Map:
+			return field + " must contain at least " + param + " items"
+		default:
+			return field + " must be at least " + param
+		}
+	case "max":
+		switch validationErr.Kind() {
+		case reflect.String:
+			return field + " must be at most " + param + " characters long"
+		case reflect.Slice, reflect.Map:
+			return field + " must contain at most " + param + " items"
+		default:
+			return field + " must be at most " + param
+		}
+	case "url":
+		return field + " must be a valid URL"
+	case "alphanum":
+		return field + " must contain only alphanumeric characters"
+	case "oneof":
+		return field + " must be one of: " + param
+	case "uuid":
+		return field + " must be a valid UUID"
+	default:
+		return field + " is invalid"
+	}
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type validationBody struct {
+	Code   string       `json:"code"`
+	Error  []string     `json:"error"`
+	Errors []FieldError `json:"errors"`
+}
+
+// validationFailure runs respond in a request and decodes the 400 response it writes
+func validationFailure(t *testing.T, respond func(c *fiber.Ctx) error) validationBody {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", respond)
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	var body validationBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return body
+}
+
+func TestValidationErrorResponseListsEveryFailedRule(t *testing.T) {
+	request := struct {
+		Name    string   `json:"name" validate:"required"`
+		Email   string   `json:"email" validate:"email"`
+		Slug    string   `json:"slug" validate:"min=3,alphanum"`
+		Tags    []string `json:"tags" validate:"max=1"`
+		Ignored string   `json:"-" validate:"required"`
+	}{Email: "not-an-email", Slug: "a", Tags: []string{"x", "y"}, Ignored: "set"}
+
+	err := NewValidator().Struct(request)
+	if err == nil {
+		t.Fatal("Struct accepted an invalid request")
+	}
+	body := validationFailure(t, func(c *fiber.Ctx) error { return ValidationErrorResponse(c, err) })
+
+	want := []FieldError{
+		{Field: "name", Tag: "required", Message: "name is required"},
+		{Field: "email", Tag: "email", Message: "email must be a valid email address"},
+		{Field: "slug", Tag: "min", Message: "slug must be at least 3 characters long"},
+		{Field: "tags", Tag: "max", Message: "tags must contain at most 1 items"},
+	}
+	if !slices.Equal(body.Errors, want) {
+		t.Errorf("errors = %+v\nwant %+v", body.Errors, want)
+	}
+	if len(body.Error) != len(want) || body.Error[0] != want[0].Message {
+		t.Errorf("error = %v, want the messages of every failure", body.Error)
+	}
+	if body.Code != CodeValidation {
+		t.Errorf("code = %q, want %q", body.Code, CodeValidation)
+	}
+}
+
+func TestValidationResponsesWithoutFieldRules(t *testing.T) {
+	body := validationFailure(t, func(c *fiber.Ctx) error {
+		return ValidationErrorResponse(c, errors.New("request body is empty"))
+	})
+	if want := []FieldError{{Message: "request body is empty"}}; !slices.Equal(body.Errors, want) {
+		t.Errorf("errors = %+v, want %+v", body.Errors, want)
+	}
+
+	body = validationFailure(t, func(c *fiber.Ctx) error {
+		return ValidationMessagesResponse(c, []string{"currency is not supported", "timezone is unknown"})
+	})
+	want := []FieldError{{Message: "currency is not supported"}, {Message: "timezone is unknown"}}
+	if !slices.Equal(body.Errors, want) {
+		t.Errorf("errors = %+v, want %+v", body.Errors, want)
+	}
+}
@@ -1,9 +1,23 @@
 package dto
 
+import "github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
+
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	// Length and complexity are checked against the configured password policy
+	Password string `json:"password" validate:"required"`
 	Name     string `json:"name" validate:"required,min=2"`
+	// Profile, when present, is created together with the user in one transaction
+	Profile *CreateProfileRequest `json:"profile,omitempty"`
+}
+
+// Normalize trims the email and name and lowercases the email before validation.
+func (r *RegisterRequest) Normalize() {
+	r.Email = entities.NormalizeEmail(r.Email)
+	r.Name = entities.NormalizeName(r.Name)
+	if r.Profile != nil {
+		r.Profile.Normalize()
+	}
 }
 
 type LoginRequest struct {
@@ -11,6 +25,11 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// Normalize trims and lowercases the email so it matches the stored form.
+func (r *LoginRequest) Normalize() {
+	r.Email = entities.NormalizeEmail(r.Email)
+}
+
 type AuthResponse struct {
 	User         *UserResponse `json:"user"`
 	AccessToken  string        `json:"access_token"`
@@ -9,3 +9,10 @@ type PaginatedResponse struct {
 	HasNext    bool        `json:"has_next"`
 	HasPrev    bool        `json:"has_prev"`
 }
+
+type CursorPaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasNext    bool        `json:"has_next"`
+}
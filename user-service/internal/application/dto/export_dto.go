@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserDataExport is everything held about a user across services, returned for data
+// portability requests. Sections another service could not provide are left out and
+// explained in Errors.
+type UserDataExport struct {
+	ExportedAt time.Time `json:"exported_at"`
+	// User includes the profile, roles and permissions but never the password hash
+	User   *UserResponse   `json:"user"`
+	Stores json.RawMessage `json:"stores,omitempty"`
+	Cart   json.RawMessage `json:"cart,omitempty"`
+	// Errors maps each section that could not be exported to the reason
+	Errors map[string]string `json:"errors,omitempty"`
+}
@@ -1,39 +1,64 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 )
 
 type CreateProfileRequest struct {
-	FirstName   string     `json:"first_name"`
-	LastName    string     `json:"last_name"`
-	Phone       string     `json:"phone"`
-	Avatar      string     `json:"avatar"`
+	FirstName   string     `json:"first_name" validate:"max=100"`
+	LastName    string     `json:"last_name" validate:"max=100"`
+	Phone       string     `json:"phone" validate:"max=20"`
+	Avatar      string     `json:"avatar" validate:"omitempty,url"`
 	DateOfBirth *time.Time `json:"date_of_birth"`
-	Gender      string     `json:"gender"`
-	Address     string     `json:"address"`
-	City        string     `json:"city"`
-	State       string     `json:"state"`
-	Country     string     `json:"country"`
-	ZipCode     string     `json:"zip_code"`
-	Bio         string     `json:"bio"`
+	Gender      string     `json:"gender" validate:"max=10"`
+	Address     string     `json:"address" validate:"max=255"`
+	City        string     `json:"city" validate:"max=100"`
+	State       string     `json:"state" validate:"max=100"`
+	Country     string     `json:"country" validate:"max=100"`
+	ZipCode     string     `json:"zip_code" validate:"max=20"`
+	Bio         string     `json:"bio" validate:"max=1000"`
 }
 
 type UpdateProfileRequest struct {
-	FirstName   *string    `json:"first_name"`
-	LastName    *string    `json:"last_name"`
-	Phone       *string    `json:"phone"`
-	Avatar      *string    `json:"avatar"`
+	FirstName   *string    `json:"first_name" validate:"omitempty,max=100"`
+	LastName    *string    `json:"last_name" validate:"omitempty,max=100"`
+	Phone       *string    `json:"phone" validate:"omitempty,max=20"`
+	Avatar      *string    `json:"avatar" validate:"omitempty,url"`
 	DateOfBirth *time.Time `json:"date_of_birth"`
-	Gender      *string    `json:"gender"`
-	Address     *string    `json:"address"`
-	City        *string    `json:"city"`
-	State       *string    `json:"state"`
-	Country     *string    `json:"country"`
-	ZipCode     *string    `json:"zip_code"`
-	Bio         *string    `json:"bio"`
+	Gender      *string    `json:"gender" validate:"omitempty,max=10"`
+	Address     *string    `json:"address" validate:"omitempty,max=255"`
+	City        *string    `json:"city" validate:"omitempty,max=100"`
+	State       *string    `json:"state" validate:"omitempty,max=100"`
+	Country     *string    `json:"country" validate:"omitempty,max=100"`
+	ZipCode     *string    `json:"zip_code" validate:"omitempty,max=20"`
+	Bio         *string    `json:"bio" validate:"omitempty,max=1000"`
+}
+
+// Normalize trims every text field and collapses whitespace in the first and last name.
+func (r *CreateProfileRequest) Normalize() {
+	r.FirstName = entities.NormalizeName(r.FirstName)
+	r.LastName = entities.NormalizeName(r.LastName)
+	for _, field := range []*string{&r.Phone, &r.Avatar, &r.Gender, &r.Address, &r.City, &r.State, &r.Country, &r.ZipCode, &r.Bio} {
+		*field = strings.TrimSpace(*field)
+	}
+}
+
+// Normalize applies the same rules as CreateProfileRequest.Normalize to the fields
+// that are present.
+func (r *UpdateProfileRequest) Normalize() {
+	for _, field := range []*string{r.FirstName, r.LastName} {
+		if field != nil {
+			*field = entities.NormalizeName(*field)
+		}
+	}
+	for _, field := range []*string{r.Phone, r.Avatar, r.Gender, r.Address, r.City, r.State, r.Country, r.ZipCode, r.Bio} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
 }
 
 type ProfileResponse struct {
@@ -53,6 +78,10 @@ type ProfileResponse struct {
 	Bio         string     `json:"bio"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	// PartialRead is set when some encrypted fields could not be decrypted; they are
+	// listed in UndecryptableFields and returned empty
+	PartialRead         bool     `json:"partial_read,omitempty"`
+	UndecryptableFields []string `json:"undecryptable_fields,omitempty"`
 }
 
 // NewProfileResponse creates a ProfileResponse DTO from the given entities.UserProfile.
@@ -76,5 +105,8 @@ func NewProfileResponse(profile *entities.UserProfile) *ProfileResponse {
 		Bio:         profile.Bio,
 		CreatedAt:   profile.CreatedAt,
 		UpdatedAt:   profile.UpdatedAt,
+
+		PartialRead:         len(profile.UndecryptableFields) > 0,
+		UndecryptableFields: profile.UndecryptableFields,
 	}
 }
@@ -0,0 +1,37 @@
+package dto
+
+import "testing"
+
+func TestUpdateProfileRequestNormalize(t *testing.T) {
+	firstName, city := "  Jane  Mary ", " Berlin\t"
+	req := UpdateProfileRequest{FirstName: &firstName, City: &city}
+
+	req.Normalize()
+
+	if *req.FirstName != "Jane Mary" {
+		t.Errorf("first name = %q, want Jane Mary", *req.FirstName)
+	}
+	if *req.City != "Berlin" {
+		t.Errorf("city = %q, want Berlin", *req.City)
+	}
+	if req.LastName != nil || req.Bio != nil {
+		t.Error("absent fields were set")
+	}
+}
+
+func TestUpdateUserRequestNormalize(t *testing.T) {
+	name := "   Jane    Doe  "
+	req := UpdateUserRequest{Name: &name}
+
+	req.Normalize()
+
+	if *req.Name != "Jane Doe" {
+		t.Errorf("name = %q, want Jane Doe", *req.Name)
+	}
+
+	empty := UpdateUserRequest{}
+	empty.Normalize()
+	if empty.Name != nil {
+		t.Error("absent name was set")
+	}
+}
@@ -5,3 +5,8 @@ type UserRBACResponse struct {
 	Roles       []string `json:"roles"`
 	Permissions []string `json:"permissions"`
 }
+
+type UserPermissionsResponse struct {
+	UserID      string   `json:"user_id"`
+	Permissions []string `json:"permissions"`
+}
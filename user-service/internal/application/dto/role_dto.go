@@ -7,15 +7,19 @@ import (
 )
 
 type CreateRoleRequest struct {
-	Name        string   `json:"name" validate:"required"`
-	Description string   `json:"description"`
-	Permissions []string `json:"permissions"` // permission IDs
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,uuid"` // permission IDs
 }
 
 type UpdateRoleRequest struct {
-	Name        *string  `json:"name"`
-	Description *string  `json:"description"`
-	Permissions []string `json:"permissions"` // permission IDs
+	Name        *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Permissions []string `json:"permissions" validate:"omitempty,dive,uuid"` // permission IDs
+}
+
+type CloneRoleRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
 }
 
 type RoleResponse struct {
@@ -28,16 +32,18 @@ type RoleResponse struct {
 }
 
 type PermissionResponse struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Resource    string `json:"resource"`
-	Action      string `json:"action"`
-	Description string `json:"description"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type AssignRoleRequest struct {
-	UserID  string   `json:"user_id" validate:"required"`
-	RoleIDs []string `json:"role_ids" validate:"required"`
+	UserID  string   `json:"user_id" validate:"required,uuid"`
+	RoleIDs []string `json:"role_ids" validate:"required,min=1,dive,uuid"`
 }
 
 // NewRoleResponse creates a RoleResponse from a domain Role.
@@ -53,6 +59,8 @@ func NewRoleResponse(role *entities.Role) *RoleResponse {
 			Resource:    perm.Resource,
 			Action:      perm.Action,
 			Description: perm.Description,
+			CreatedAt:   perm.CreatedAt,
+			UpdatedAt:   perm.UpdatedAt,
 		}
 	}
 
@@ -75,5 +83,7 @@ func NewPermissionResponse(permission *entities.Permission) *PermissionResponse
 		Resource:    permission.Resource,
 		Action:      permission.Action,
 		Description: permission.Description,
+		CreatedAt:   permission.CreatedAt,
+		UpdatedAt:   permission.UpdatedAt,
 	}
 }
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 )
 
@@ -10,42 +12,98 @@ type UserResponse struct {
 	Name        string           `json:"name"`
 	IsActive    bool             `json:"is_active"`
 	Profile     *ProfileResponse `json:"profile,omitempty"`
+	HasProfile  bool             `json:"has_profile"`
+	RoleNames   []string         `json:"role_names"`
 	Roles       []RoleResponse   `json:"roles,omitempty"`
 	Permissions []string         `json:"permissions,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+
+	// StatusReason, StatusChangedBy and StatusChangedAt describe the last suspension or activation
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedBy string     `json:"status_changed_by,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
 }
 
 type UserListResponse struct {
-	ID       string         `json:"id"`
-	Email    string         `json:"email"`
-	Name     string         `json:"name"`
-	IsActive bool           `json:"is_active"`
-	Roles    []RoleResponse `json:"roles"`
+	ID        string         `json:"id"`
+	Email     string         `json:"email"`
+	Name      string         `json:"name"`
+	IsActive  bool           `json:"is_active"`
+	Roles     []RoleResponse `json:"roles"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// PublicUserResponse is the minimal, non-sensitive view of a user shared with other services.
+type PublicUserResponse struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar,omitempty"`
 }
 
 type UpdateUserRequest struct {
-	Name     *string `json:"name"`
+	Name     *string `json:"name" validate:"omitempty,min=2,max=100"`
 	IsActive *bool   `json:"is_active"`
 }
 
+// DeleteAccountRequest confirms self-service account deletion with the current password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+	// DeleteProfile also deletes the user's profile
+	DeleteProfile bool `json:"delete_profile"`
+}
+
+// SuspendUserRequest carries the reason an admin gives for suspending an account
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// ActivateUserRequest optionally explains why a suspended account is being restored
+type ActivateUserRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// Normalize trims and collapses whitespace in the name, if present.
+func (r *UpdateUserRequest) Normalize() {
+	if r.Name != nil {
+		name := entities.NormalizeName(*r.Name)
+		r.Name = &name
+	}
+}
+
 // NewUserResponse creates a UserResponse DTO from a domain User.
 // 
-// It copies ID, Email, Name, and IsActive. If the domain user has a Profile,
-// the response.Profile is populated via NewProfileResponse. If the domain user
+// It copies ID, Email, Name, IsActive and the role names. If the domain user has a Profile,
+// the response.Profile is populated via NewProfileResponse and HasProfile is set. If the domain user
 // has Roles, those are converted to RoleResponse entries and response.Permissions
 // is set from user.GetPermissions().
 // 
 // The input `user` must be non-nil.
 func NewUserResponse(user *entities.User) *UserResponse {
 	response := &UserResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		Name:     user.Name,
-		IsActive: user.IsActive,
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+
+		StatusReason:    user.StatusReason,
+		StatusChangedBy: user.StatusChangedBy,
+		StatusChangedAt: user.StatusChangedAt,
+	}
+
+	response.RoleNames = make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		response.RoleNames[i] = role.Name
 	}
 
-	// Add profile if exists
+	// Add profile if exists; callers load the profile with the user, so a nil
+	// Profile means the user has not created one yet
 	if user.Profile != nil {
 		response.Profile = NewProfileResponse(user.Profile)
+		response.HasProfile = true
 	}
 
 	// Add roles
@@ -62,6 +120,13 @@ func NewUserResponse(user *entities.User) *UserResponse {
 	return response
 }
 
+// WithoutPermissions drops the flattened permission set from the response to keep
+// payloads small when callers do not need it. Roles are kept.
+func (r *UserResponse) WithoutPermissions() *UserResponse {
+	r.Permissions = nil
+	return r
+}
+
 // NewUserListResponse creates a UserListResponse DTO from a domain User.
 // 
 // The returned DTO contains the user's ID, email, name, active status, and a
@@ -69,10 +134,12 @@ func NewUserResponse(user *entities.User) *UserResponse {
 // non-nil.
 func NewUserListResponse(user *entities.User) *UserListResponse {
 	response := &UserListResponse{
-		ID:       user.ID,
-		Email:    user.Email,
-		Name:     user.Name,
-		IsActive: user.IsActive,
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
 	}
 
 	// Add roles
@@ -85,3 +152,16 @@ func NewUserListResponse(user *entities.User) *UserListResponse {
 
 	return response
 }
+
+// NewPublicUserResponse creates a PublicUserResponse from a domain User, taking the
+// avatar from the profile when one is loaded.
+func NewPublicUserResponse(user *entities.User) *PublicUserResponse {
+	response := &PublicUserResponse{
+		ID:   user.ID,
+		Name: user.Name,
+	}
+	if user.Profile != nil {
+		response.Avatar = user.Profile.Avatar
+	}
+	return response
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
+)
+
+func TestWithoutPermissionsKeepsRoles(t *testing.T) {
+	user := &entities.User{
+		ID:    "user",
+		Email: "jane@example.com",
+		Roles: []entities.Role{{
+			ID:          "role",
+			Name:        "admin",
+			Permissions: []entities.Permission{{Name: "user:read"}},
+		}},
+	}
+
+	full := NewUserResponse(user)
+	if !slices.Equal(full.Permissions, []string{"user:read"}) {
+		t.Fatalf("permissions = %v, want [user:read]", full.Permissions)
+	}
+
+	trimmed := NewUserResponse(user).WithoutPermissions()
+	if trimmed.Permissions != nil {
+		t.Errorf("permissions = %v, want them left out", trimmed.Permissions)
+	}
+	if len(trimmed.Roles) != 1 || trimmed.Roles[0].Name != "admin" {
+		t.Errorf("roles = %+v, want the admin role kept", trimmed.Roles)
+	}
+	if !slices.Equal(trimmed.RoleNames, []string{"admin"}) {
+		t.Errorf("role names = %v, want [admin]", trimmed.RoleNames)
+	}
+}
@@ -20,20 +20,30 @@ type authService struct {
 	redisClient *redis.Client
 	jwtConfig   *config.JWTConfig
 	jwtManager  *jwt.TokenManager
+	// passwordValidator is enforced on every new password
+	passwordValidator *password.Validator
 }
 
-func NewAuthService(userRepo repositories.UserRepository, redisClient *redis.Client, jwtConfig *config.JWTConfig, jwtManager *jwt.TokenManager) services.AuthService {
+func NewAuthService(userRepo repositories.UserRepository, redisClient *redis.Client, jwtConfig *config.JWTConfig, jwtManager *jwt.TokenManager, passwordValidator *password.Validator) services.AuthService {
 	return &authService{
-		userRepo:    userRepo,
-		redisClient: redisClient,
-		jwtConfig:   jwtConfig,
-		jwtManager:  jwtManager,
+		userRepo:          userRepo,
+		redisClient:       redisClient,
+		jwtConfig:         jwtConfig,
+		jwtManager:        jwtManager,
+		passwordValidator: passwordValidator,
 	}
 }
 
+// PasswordPolicy implements services.AuthService.
+func (s *authService) PasswordPolicy() password.Policy {
+	return s.passwordValidator.Policy
+}
+
 // Login implements services.AuthService.
 func (s *authService) Login(ctx *fiber.Ctx, req *dto.LoginRequest) (*dto.AuthResponse, error) {
-	user, err := s.userRepo.GetByEmail(ctx.Context(), req.Email)
+	req.Normalize()
+
+	user, err := s.userRepo.GetByEmail(ctx.UserContext(), req.Email)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +52,11 @@ func (s *authService) Login(ctx *fiber.Ctx, req *dto.LoginRequest) (*dto.AuthRes
 		return nil, errors.New("invalid password")
 	}
 
+	// Checked after the password so the response does not reveal which accounts are suspended
+	if !user.IsActive {
+		return nil, services.ErrAccountDisabled
+	}
+
 	return s.generateAuthResponse(user)
 }
 
@@ -55,6 +70,15 @@ func (s *authService) RefreshToken(ctx *fiber.Ctx, refreshToken string) (*dto.Au
 	// Remove "Bearer " prefix if present
 	token = strings.TrimPrefix(token, "Bearer ")
 
+	// A refresh token outlives the checks made at login, so the account is checked again
+	claims, err := s.jwtManager.ValidateToken(token, jwt.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureActive(ctx, claims.UserID); err != nil {
+		return nil, err
+	}
+
 	tokens, err := s.jwtManager.RefreshToken(token)
 	if err != nil {
 		return nil, err
@@ -64,13 +88,19 @@ func (s *authService) RefreshToken(ctx *fiber.Ctx, refreshToken string) (*dto.Au
 		AccessToken:  tokens[jwt.AccessToken],
 		RefreshToken: tokens[jwt.RefreshToken],
 		TokenType:    "Bearer",
-		ExpiresIn:    15 * 60, // 15 minutes in seconds
+		ExpiresIn:    int64(s.jwtConfig.Expiration.Seconds()),
 	}, nil
 }
 
 // Register implements services.AuthService.
 func (s *authService) Register(ctx *fiber.Ctx, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
-	exsist, err := s.userRepo.ExistsByEmail(ctx.Context(), req.Email)
+	req.Normalize()
+
+	if err := s.passwordValidator.Validate(ctx.UserContext(), req.Password); err != nil {
+		return nil, err
+	}
+
+	exsist, err := s.userRepo.ExistsByEmail(ctx.UserContext(), req.Email)
 	if err != nil {
 		return nil, err
 	}
@@ -89,8 +119,12 @@ func (s *authService) Register(ctx *fiber.Ctx, req *dto.RegisterRequest) (*dto.A
 		Name:     req.Name,
 		IsActive: true,
 	}
+	if req.Profile != nil {
+		// Created in the same transaction as the user, so neither exists without the other
+		user.Profile = newProfileEntity("", req.Profile)
+	}
 
-	if err := s.userRepo.Create(ctx.Context(), user); err != nil {
+	if err := s.userRepo.Create(ctx.UserContext(), user); err != nil {
 		return nil, errors.New("failed to create user")
 	}
 
@@ -104,14 +138,35 @@ func (s *authService) ValidateToken(ctx *fiber.Ctx, token string) (*dto.UserResp
 		return nil, err
 	}
 
-	user, err := s.userRepo.GetByID(ctx.Context(), claims.UserID)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), claims.UserID)
 	if err != nil {
 		return nil, err
 	}
+	if user == nil {
+		return nil, services.ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, services.ErrAccountDisabled
+	}
 
 	return dto.NewUserResponse(user), nil
 }
 
+// ensureActive returns services.ErrAccountDisabled when the user has been suspended
+func (s *authService) ensureActive(ctx *fiber.Ctx, userID string) error {
+	user, err := s.userRepo.GetByID(ctx.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return services.ErrUserNotFound
+	}
+	if !user.IsActive {
+		return services.ErrAccountDisabled
+	}
+	return nil
+}
+
 func (s *authService) Logout(ctx *fiber.Ctx) error {
 	userID := ctx.Get("X-User-Id")
 	if userID == "" {
@@ -139,6 +194,6 @@ func (s *authService) generateAuthResponse(user *entities.User) (*dto.AuthRespon
 		AccessToken:  tokens[jwt.AccessToken],
 		RefreshToken: tokens[jwt.RefreshToken],
 		TokenType:    "Bearer",
-		ExpiresIn:    s.jwtConfig.Expiration.Milliseconds(),
+		ExpiresIn:    int64(s.jwtConfig.Expiration.Seconds()),
 	}, nil
 }
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+)
+
+func TestRegisterRejectsEmailDifferingOnlyInCase(t *testing.T) {
+	service, users, _ := newTestAuthService(t)
+	addUser(t, users, "jane@example.com", true)
+
+	for _, email := range []string{"Jane@Example.com", "JANE@EXAMPLE.COM"} {
+		var err error
+		withCtx(t, nil, func(c *fiber.Ctx) {
+			_, err = service.Register(c, &dto.RegisterRequest{Email: email, Password: testPassword, Name: "Jane"})
+		})
+
+		if err == nil || err.Error() != "email already exists" {
+			t.Errorf("register %s: err = %v, want email already exists", email, err)
+		}
+	}
+	if users.created != 1 {
+		t.Errorf("%d users created, want only the original", users.created)
+	}
+}
+
+func TestLoginMatchesEmailCaseInsensitively(t *testing.T) {
+	service, users, _ := newTestAuthService(t)
+	addUser(t, users, "jane@example.com", true)
+
+	var (
+		response *dto.AuthResponse
+		err      error
+	)
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		response, err = service.Login(c, &dto.LoginRequest{Email: "Jane@Example.COM", Password: testPassword})
+	})
+
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if response.User.Email != "jane@example.com" {
+		t.Errorf("logged in as %s, want jane@example.com", response.User.Email)
+	}
+}
+
+func TestRegisterNormalizesPaddedInput(t *testing.T) {
+	service, users, _ := newTestAuthService(t)
+
+	var err error
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.Register(c, &dto.RegisterRequest{
+			Email:    "  Jane.Doe@Example.COM \t",
+			Password: testPassword,
+			Name:     "  Jane   Doe ",
+			Profile:  &dto.CreateProfileRequest{FirstName: " Jane ", LastName: "  van   Doe", City: " Berlin  "},
+		})
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	user, _ := users.GetByEmail(context.Background(), "jane.doe@example.com")
+	if user == nil {
+		t.Fatal("user not stored under the normalized email")
+	}
+	if user.Email != "jane.doe@example.com" {
+		t.Errorf("email = %q, want jane.doe@example.com", user.Email)
+	}
+	if user.Name != "Jane Doe" {
+		t.Errorf("name = %q, want Jane Doe", user.Name)
+	}
+	if user.Profile == nil || user.Profile.FirstName != "Jane" || user.Profile.LastName != "van Doe" || user.Profile.City != "Berlin" {
+		t.Errorf("profile = %+v, want trimmed fields", user.Profile)
+	}
+
+	// The padded address still finds the account, and cannot register it again
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.Login(c, &dto.LoginRequest{Email: " jane.doe@example.com  ", Password: testPassword})
+	})
+	if err != nil {
+		t.Errorf("Login with padded email: %v", err)
+	}
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.Register(c, &dto.RegisterRequest{Email: " JANE.DOE@example.com", Password: testPassword, Name: "Jane"})
+	})
+	if err == nil {
+		t.Error("padded duplicate email registered")
+	}
+}
+
+func TestLoginRejectsInactiveUser(t *testing.T) {
+	service, users, store := newTestAuthService(t)
+	addUser(t, users, "jane@example.com", false)
+
+	var loginErr, wrongPasswordErr error
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, loginErr = service.Login(c, &dto.LoginRequest{Email: "jane@example.com", Password: testPassword})
+	})
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, wrongPasswordErr = service.Login(c, &dto.LoginRequest{Email: "jane@example.com", Password: "Wrong-Horse-9"})
+	})
+
+	if !errors.Is(loginErr, services.ErrAccountDisabled) {
+		t.Errorf("login err = %v, want ErrAccountDisabled", loginErr)
+	}
+	// A wrong password must not reveal that the account is disabled
+	if wrongPasswordErr == nil || errors.Is(wrongPasswordErr, services.ErrAccountDisabled) {
+		t.Errorf("wrong password err = %v, want invalid password", wrongPasswordErr)
+	}
+	if keys := store.Keys(""); len(keys) != 0 {
+		t.Errorf("tokens stored for an inactive user: %v", keys)
+	}
+}
+
+// loginThenDeactivate logs in as an active user and then clears is_active directly, the
+// way a deactivation that skipped session revocation would leave it
+func loginThenDeactivate(t *testing.T, service *authService, users *fakeUserRepo) *dto.AuthResponse {
+	t.Helper()
+
+	user := addUser(t, users, "jane@example.com", true)
+
+	var (
+		session *dto.AuthResponse
+		err     error
+	)
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		session, err = service.Login(c, &dto.LoginRequest{Email: "jane@example.com", Password: testPassword})
+	})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	user.IsActive = false
+	if err := users.Update(context.Background(), user); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	return session
+}
+
+func TestRefreshTokenRejectsInactiveUser(t *testing.T) {
+	service, users, _ := newTestAuthService(t)
+	session := loginThenDeactivate(t, service, users)
+
+	var (
+		response *dto.AuthResponse
+		err      error
+	)
+	withCtx(t, map[string]string{"Authorization": "Bearer " + session.RefreshToken}, func(c *fiber.Ctx) {
+		response, err = service.RefreshToken(c, session.RefreshToken)
+	})
+
+	if !errors.Is(err, services.ErrAccountDisabled) {
+		t.Errorf("err = %v, want ErrAccountDisabled", err)
+	}
+	if response != nil {
+		t.Errorf("refresh issued tokens for an inactive user: %+v", response)
+	}
+}
+
+func TestValidateTokenRejectsInactiveUser(t *testing.T) {
+	service, users, _ := newTestAuthService(t)
+	session := loginThenDeactivate(t, service, users)
+
+	var (
+		user *dto.UserResponse
+		err  error
+	)
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		user, err = service.ValidateToken(c, session.AccessToken)
+	})
+
+	if !errors.Is(err, services.ErrAccountDisabled) {
+		t.Errorf("err = %v, want ErrAccountDisabled", err)
+	}
+	if user != nil {
+		t.Errorf("token validated for inactive user %s", user.Email)
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/external"
+)
+
+type dataExportService struct {
+	userRepo     repositories.UserRepository
+	storeService *external.StoreServiceClient
+	cartService  *external.CartServiceClient
+}
+
+// NewDataExportService returns a services.DataExportService that combines the user's own
+// records with what the store and shopping cart services hold about them.
+func NewDataExportService(userRepo repositories.UserRepository, storeService *external.StoreServiceClient, cartService *external.CartServiceClient) services.DataExportService {
+	return &dataExportService{
+		userRepo:     userRepo,
+		storeService: storeService,
+		cartService:  cartService,
+	}
+}
+
+// ExportUserData gathers the user's record, profile and roles along with their stores and
+// cart. A failing downstream service is reported in the export rather than failing it.
+func (s *dataExportService) ExportUserData(ctx *fiber.Ctx, userID string) (*dto.UserDataExport, error) {
+	user, err := s.userRepo.GetByID(ctx.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, services.ErrUserNotFound
+	}
+
+	export := &dto.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		User:       dto.NewUserResponse(user),
+		Errors:     map[string]string{},
+	}
+
+	if export.Stores, err = s.storeService.GetUserStores(ctx.UserContext(), userID); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Data export could not read stores")
+		export.Errors["stores"] = "store data is temporarily unavailable"
+	}
+	if export.Cart, err = s.cartService.GetCart(ctx.UserContext(), userID); err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Data export could not read cart")
+		export.Errors["cart"] = "cart data is temporarily unavailable"
+	}
+
+	return export, nil
+}
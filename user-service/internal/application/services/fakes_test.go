@@ -0,0 +1,456 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/jwt"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/pagination"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
+)
+
+// fakeUserRepo keeps users in memory and, like the real repository, matches emails
+// case-insensitively
+type fakeUserRepo struct {
+	mu      sync.Mutex
+	users   map[string]entities.User
+	nextID  int
+	created int
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{users: make(map[string]entities.User)}
+}
+
+func (r *fakeUserRepo) findByEmail(email string) *entities.User {
+	for _, user := range r.users {
+		if strings.ToLower(user.Email) == entities.NormalizeEmail(email) {
+			return &user
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findByEmail(user.Email) != nil {
+		return errors.New("duplicate key value violates unique constraint \"idx_users_email_lower\"")
+	}
+	if user.ID == "" {
+		r.nextID++
+		user.ID = "user-" + strconv.Itoa(r.nextID)
+	}
+	r.created++
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.findByEmail(email), nil
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.users[id]; ok {
+		return &user, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) GetWithRoles(ctx context.Context, id string) (*entities.User, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeUserRepo) GetByIDs(ctx context.Context, ids []string) ([]entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []entities.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *entities.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepo) DeleteWithProfile(ctx context.Context, id string) error {
+	return r.Delete(ctx, id)
+}
+
+func (r *fakeUserRepo) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.findByEmail(email) != nil, nil
+}
+
+func (r *fakeUserRepo) ListByCursor(ctx context.Context, cursor *pagination.Cursor, limit int) ([]entities.User, error) {
+	return nil, errors.New("not implemented")
+}
+
+// addUser stores a user with the given email, active state and roles
+func addUser(t *testing.T, users *fakeUserRepo, email string, active bool, roles ...string) *entities.User {
+	t.Helper()
+
+	hash, err := password.HashPassword(testPassword)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user := &entities.User{Email: email, Name: "Test User", Password: hash, IsActive: active}
+	for _, role := range roles {
+		user.Roles = append(user.Roles, entities.Role{Name: role})
+	}
+	if err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("add %s: %v", email, err)
+	}
+	return user
+}
+
+// testPassword satisfies testPasswordPolicy
+const testPassword = "Correct-Horse-9"
+
+var testPasswordPolicy = password.Policy{MinLength: 8, MaxLength: 72}
+
+// newTestAuthService returns an auth service over an in-memory user repository and a
+// fake Redis
+func newTestAuthService(t *testing.T) (*authService, *fakeUserRepo, *fakeRedis) {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	client, store := startFakeRedis(t)
+	manager, jwtConfig := newTestTokenManager(t, client)
+
+	service := &authService{
+		userRepo:          users,
+		redisClient:       client,
+		jwtConfig:         jwtConfig,
+		jwtManager:        manager,
+		passwordValidator: password.NewValidator(testPasswordPolicy, nil),
+	}
+	return service, users, store
+}
+
+// newTestUserService returns a user service over an in-memory repository whose tokens live
+// in a fake Redis. It has no database, so only paths that never open a transaction can
+// be exercised.
+func newTestUserService(t *testing.T) (*userService, *fakeUserRepo, *fakeRedis) {
+	t.Helper()
+
+	users := newFakeUserRepo()
+	client, store := startFakeRedis(t)
+	manager, _ := newTestTokenManager(t, client)
+	return &userService{userRepo: users, jwtManager: manager}, users, store
+}
+
+// newTestTokenManager returns a JWT manager keeping its tokens in client
+func newTestTokenManager(t *testing.T, client *redis.Client) (*jwt.TokenManager, *config.JWTConfig) {
+	t.Helper()
+
+	jwtConfig := &config.JWTConfig{PrivateKey: "test-secret", Expiration: 15 * time.Minute, RefreshExpiration: 24 * time.Hour}
+	manager, err := jwt.NewTokenManager(jwtConfig, client)
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	return manager, jwtConfig
+}
+
+// withCtx runs fn inside a request so it gets a *fiber.Ctx like the handlers pass the
+// services. headers are set on the request.
+func withCtx(t *testing.T, headers map[string]string, fn func(c *fiber.Ctx)) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		fn(c)
+		return nil
+	})
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+// fakeRedis is an in-memory server speaking enough of the Redis protocol (RESP2) for the
+// string, counter and expiry commands the services use, including MULTI/EXEC
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+// startFakeRedis serves a fakeRedis on a local port for the duration of the test and
+// returns a client connected to it
+func startFakeRedis(t *testing.T) (*redis.Client, *fakeRedis) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	store := &fakeRedis{values: make(map[string]string), expires: make(map[string]time.Time)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go store.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() {
+		client.Close()
+		listener.Close()
+	})
+	return client, store
+}
+
+// Has reports whether key is set and not expired
+func (f *fakeRedis) Has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.get(key)
+	return ok
+}
+
+// Set stores value under key without expiry
+func (f *fakeRedis) Set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[key] = value
+	delete(f.expires, key)
+}
+
+// Keys returns the live keys starting with prefix
+func (f *fakeRedis) Keys(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for key := range f.values {
+		if _, ok := f.get(key); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply bytes.Buffer
+		switch name := strings.ToUpper(args[0]); {
+		case name == "MULTI":
+			inMulti = true
+			reply.WriteString("+OK\r\n")
+		case name == "EXEC":
+			fmt.Fprintf(&reply, "*%d\r\n", len(queued))
+			f.mu.Lock()
+			for _, command := range queued {
+				f.exec(&reply, command)
+			}
+			f.mu.Unlock()
+			queued, inMulti = nil, false
+		case name == "DISCARD":
+			queued, inMulti = nil, false
+			reply.WriteString("+OK\r\n")
+		case inMulti:
+			queued = append(queued, args)
+			reply.WriteString("+QUEUED\r\n")
+		default:
+			f.mu.Lock()
+			f.exec(&reply, args)
+			f.mu.Unlock()
+		}
+
+		if _, err := conn.Write(reply.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("unexpected command line %q", line)
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected argument header %q", header)
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+// get returns the live value of key, dropping it if it has expired. f.mu must be held.
+func (f *fakeRedis) get(key string) (string, bool) {
+	if expiry, ok := f.expires[key]; ok && !time.Now().Before(expiry) {
+		delete(f.values, key)
+		delete(f.expires, key)
+	}
+	value, ok := f.values[key]
+	return value, ok
+}
+
+// exec runs one command and writes its reply. f.mu must be held.
+func (f *fakeRedis) exec(reply *bytes.Buffer, args []string) {
+	bulk := func(value string) { fmt.Fprintf(reply, "$%d\r\n%s\r\n", len(value), value) }
+	integer := func(n int64) { fmt.Fprintf(reply, ":%d\r\n", n) }
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		reply.WriteString("+PONG\r\n")
+	case "GET":
+		if value, ok := f.get(args[1]); ok {
+			bulk(value)
+		} else {
+			reply.WriteString("$-1\r\n")
+		}
+	case "SET":
+		key := args[1]
+		_, exists := f.get(key)
+		var ttl time.Duration
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				if exists {
+					reply.WriteString("$-1\r\n")
+					return
+				}
+			case "EX", "PX":
+				n, _ := strconv.ParseInt(args[i+1], 10, 64)
+				ttl = time.Duration(n) * time.Second
+				if strings.ToUpper(args[i]) == "PX" {
+					ttl = time.Duration(n) * time.Millisecond
+				}
+				i++
+			}
+		}
+		f.values[key] = args[2]
+		delete(f.expires, key)
+		if ttl > 0 {
+			f.expires[key] = time.Now().Add(ttl)
+		}
+		reply.WriteString("+OK\r\n")
+	case "DEL", "EXISTS":
+		var n int64
+		for _, key := range args[1:] {
+			if _, ok := f.get(key); ok {
+				n++
+				if strings.ToUpper(args[0]) == "DEL" {
+					delete(f.values, key)
+					delete(f.expires, key)
+				}
+			}
+		}
+		integer(n)
+	case "INCR":
+		value, _ := f.get(args[1])
+		n, _ := strconv.ParseInt(value, 10, 64)
+		n++
+		f.values[args[1]] = strconv.FormatInt(n, 10)
+		integer(n)
+	case "EXPIRE", "PEXPIRE":
+		if _, ok := f.get(args[1]); !ok {
+			integer(0)
+			return
+		}
+		n, _ := strconv.ParseInt(args[2], 10, 64)
+		ttl := time.Duration(n) * time.Second
+		if strings.ToUpper(args[0]) == "PEXPIRE" {
+			ttl = time.Duration(n) * time.Millisecond
+		}
+		f.expires[args[1]] = time.Now().Add(ttl)
+		integer(1)
+	case "TTL", "PTTL":
+		if _, ok := f.get(args[1]); !ok {
+			integer(-2)
+			return
+		}
+		expiry, ok := f.expires[args[1]]
+		if !ok {
+			integer(-1)
+			return
+		}
+		unit := time.Second
+		if strings.ToUpper(args[0]) == "PTTL" {
+			unit = time.Millisecond
+		}
+		integer(int64(time.Until(expiry) / unit))
+	default:
+		fmt.Fprintf(reply, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
@@ -26,14 +26,16 @@ func NewProfileService(profileRepo repositories.ProfileRepository, userRepo repo
 }
 
 func (s *profileService) CreateProfile(ctx *fiber.Ctx, userID string, req *dto.CreateProfileRequest) (*dto.ProfileResponse, error) {
+	req.Normalize()
+
 	// Check if user exists
-	user, err := s.userRepo.GetByID(ctx.Context(), userID)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), userID)
 	if err != nil || user == nil {
 		return nil, errors.New("user not found")
 	}
 
 	// Check if profile already exists
-	exists, err := s.profileRepo.ExistsByUserID(ctx.Context(), userID)
+	exists, err := s.profileRepo.ExistsByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +43,18 @@ func (s *profileService) CreateProfile(ctx *fiber.Ctx, userID string, req *dto.C
 		return nil, errors.New("profile already exists for this user")
 	}
 
-	profile := &entities.UserProfile{
+	profile := newProfileEntity(userID, req)
+
+	if err := s.profileRepo.Create(ctx.UserContext(), profile); err != nil {
+		return nil, err
+	}
+
+	return dto.NewProfileResponse(profile), nil
+}
+
+// newProfileEntity builds the profile described by req for the given user
+func newProfileEntity(userID string, req *dto.CreateProfileRequest) *entities.UserProfile {
+	return &entities.UserProfile{
 		UserID:      userID,
 		FirstName:   req.FirstName,
 		LastName:    req.LastName,
@@ -56,16 +69,10 @@ func (s *profileService) CreateProfile(ctx *fiber.Ctx, userID string, req *dto.C
 		ZipCode:     req.ZipCode,
 		Bio:         req.Bio,
 	}
-
-	if err := s.profileRepo.Create(ctx.Context(), profile); err != nil {
-		return nil, err
-	}
-
-	return dto.NewProfileResponse(profile), nil
 }
 
 func (s *profileService) GetProfile(ctx *fiber.Ctx, userID string) (*dto.ProfileResponse, error) {
-	profile, err := s.profileRepo.GetByUserID(ctx.Context(), userID)
+	profile, err := s.profileRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +93,9 @@ func (s *profileService) GetMyProfile(ctx *fiber.Ctx) (*dto.ProfileResponse, err
 }
 
 func (s *profileService) UpdateProfile(ctx *fiber.Ctx, userID string, req *dto.UpdateProfileRequest) (*dto.ProfileResponse, error) {
-	profile, err := s.profileRepo.GetByUserID(ctx.Context(), userID)
+	req.Normalize()
+
+	profile, err := s.profileRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +112,7 @@ func (s *profileService) UpdateProfile(ctx *fiber.Ctx, userID string, req *dto.U
 	}
 	if req.Phone != nil {
 		profile.Phone = *req.Phone
+		profile.ReplaceUndecryptable("phone")
 	}
 	if req.Avatar != nil {
 		profile.Avatar = *req.Avatar
@@ -115,6 +125,7 @@ func (s *profileService) UpdateProfile(ctx *fiber.Ctx, userID string, req *dto.U
 	}
 	if req.Address != nil {
 		profile.Address = *req.Address
+		profile.ReplaceUndecryptable("address")
 	}
 	if req.City != nil {
 		profile.City = *req.City
@@ -132,7 +143,7 @@ func (s *profileService) UpdateProfile(ctx *fiber.Ctx, userID string, req *dto.U
 		profile.Bio = *req.Bio
 	}
 
-	if err := s.profileRepo.Update(ctx.Context(), profile); err != nil {
+	if err := s.profileRepo.Update(ctx.UserContext(), profile); err != nil {
 		return nil, err
 	}
 
@@ -149,7 +160,7 @@ func (s *profileService) UpdateMyProfile(ctx *fiber.Ctx, req *dto.UpdateProfileR
 }
 
 func (s *profileService) DeleteProfile(ctx *fiber.Ctx, userID string) error {
-	profile, err := s.profileRepo.GetByUserID(ctx.Context(), userID)
+	profile, err := s.profileRepo.GetByUserID(ctx.UserContext(), userID)
 	if err != nil {
 		return err
 	}
@@ -157,5 +168,5 @@ func (s *profileService) DeleteProfile(ctx *fiber.Ctx, userID string) error {
 		return errors.New("profile not found")
 	}
 
-	return s.profileRepo.Delete(ctx.Context(), profile.ID)
+	return s.profileRepo.Delete(ctx.UserContext(), profile.ID)
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
@@ -9,6 +10,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type roleService struct {
@@ -36,7 +38,7 @@ func NewRoleService(
 
 func (s *roleService) CreateRole(ctx *fiber.Ctx, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
 	// Check if role already exists
-	exists, err := s.roleRepo.ExistsByName(ctx.Context(), req.Name)
+	exists, err := s.roleRepo.ExistsByName(ctx.UserContext(), req.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +47,7 @@ func (s *roleService) CreateRole(ctx *fiber.Ctx, req *dto.CreateRoleRequest) (*d
 	}
 
 	// Get permissions
-	permissions, err := s.permissionRepo.GetByIDs(ctx.Context(), req.Permissions)
+	permissions, err := s.permissionRepo.GetByIDs(ctx.UserContext(), req.Permissions)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +58,7 @@ func (s *roleService) CreateRole(ctx *fiber.Ctx, req *dto.CreateRoleRequest) (*d
 		Permissions: permissions,
 	}
 
-	if err := s.roleRepo.Create(ctx.Context(), role); err != nil {
+	if err := s.roleRepo.Create(ctx.UserContext(), role); err != nil {
 		return nil, err
 	}
 
@@ -64,7 +66,7 @@ func (s *roleService) CreateRole(ctx *fiber.Ctx, req *dto.CreateRoleRequest) (*d
 }
 
 func (s *roleService) GetRole(ctx *fiber.Ctx, id string) (*dto.RoleResponse, error) {
-	role, err := s.roleRepo.GetByID(ctx.Context(), id)
+	role, err := s.roleRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +78,7 @@ func (s *roleService) GetRole(ctx *fiber.Ctx, id string) (*dto.RoleResponse, err
 }
 
 func (s *roleService) GetAllRoles(ctx *fiber.Ctx) ([]dto.RoleResponse, error) {
-	roles, err := s.roleRepo.GetAll(ctx.Context())
+	roles, err := s.roleRepo.GetAll(ctx.UserContext())
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +92,7 @@ func (s *roleService) GetAllRoles(ctx *fiber.Ctx) ([]dto.RoleResponse, error) {
 }
 
 func (s *roleService) UpdateRole(ctx *fiber.Ctx, id string, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
-	role, err := s.roleRepo.GetByID(ctx.Context(), id)
+	role, err := s.roleRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +103,7 @@ func (s *roleService) UpdateRole(ctx *fiber.Ctx, id string, req *dto.UpdateRoleR
 	// Update fields
 	if req.Name != nil {
 		// Check if new name already exists (excluding current role)
-		existingRole, err := s.roleRepo.GetByName(ctx.Context(), *req.Name)
+		existingRole, err := s.roleRepo.GetByName(ctx.UserContext(), *req.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -114,14 +116,14 @@ func (s *roleService) UpdateRole(ctx *fiber.Ctx, id string, req *dto.UpdateRoleR
 		role.Description = *req.Description
 	}
 	if req.Permissions != nil {
-		permissions, err := s.permissionRepo.GetByIDs(ctx.Context(), req.Permissions)
+		permissions, err := s.permissionRepo.GetByIDs(ctx.UserContext(), req.Permissions)
 		if err != nil {
 			return nil, err
 		}
 		role.Permissions = permissions
 	}
 
-	if err := s.roleRepo.Update(ctx.Context(), role); err != nil {
+	if err := s.roleRepo.Update(ctx.UserContext(), role); err != nil {
 		return nil, err
 	}
 
@@ -129,7 +131,7 @@ func (s *roleService) UpdateRole(ctx *fiber.Ctx, id string, req *dto.UpdateRoleR
 }
 
 func (s *roleService) DeleteRole(ctx *fiber.Ctx, id string) error {
-	role, err := s.roleRepo.GetByID(ctx.Context(), id)
+	role, err := s.roleRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return err
 	}
@@ -137,24 +139,76 @@ func (s *roleService) DeleteRole(ctx *fiber.Ctx, id string) error {
 		return errors.New("role not found")
 	}
 
-	return s.roleRepo.Delete(ctx.Context(), id)
+	if role.Name != entities.RoleSuperAdmin {
+		return s.roleRepo.Delete(ctx.UserContext(), id)
+	}
+
+	return s.db.WithContext(ctx.UserContext()).Transaction(func(tx *gorm.DB) error {
+		holders, err := lockSuperAdminHolders(tx)
+		if err != nil {
+			return err
+		}
+		if holders > 0 {
+			return services.ErrLastSuperAdmin
+		}
+		return tx.Where("id = ?", id).Delete(&entities.Role{}).Error
+	})
+}
+
+// CloneRole creates a new role named newName carrying the same description and
+// permissions as the source role. It fails if the source does not exist or the name is taken.
+func (s *roleService) CloneRole(ctx *fiber.Ctx, sourceID string, newName string) (*dto.RoleResponse, error) {
+	source, err := s.roleRepo.GetByID(ctx.UserContext(), sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, errors.New("role not found")
+	}
+
+	exists, err := s.roleRepo.ExistsByName(ctx.UserContext(), newName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("role with this name already exists")
+	}
+
+	permissions := make([]entities.Permission, len(source.Permissions))
+	copy(permissions, source.Permissions)
+
+	role := &entities.Role{
+		Name:        newName,
+		Description: source.Description,
+		Permissions: permissions,
+	}
+
+	if err := s.roleRepo.Create(ctx.UserContext(), role); err != nil {
+		return nil, err
+	}
+
+	return dto.NewRoleResponse(role), nil
 }
 
 func (s *roleService) AssignRolesToUser(ctx *fiber.Ctx, req *dto.AssignRoleRequest) error {
 	// Get user with current roles
-	user, err := s.userRepo.GetByID(ctx.Context(), req.UserID)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), req.UserID)
 	if err != nil || user == nil {
 		return errors.New("user not found")
 	}
 
 	// Get roles
-	roles, err := s.roleRepo.GetByIDs(ctx.Context(), req.RoleIDs)
+	roles, err := s.roleRepo.GetByIDs(ctx.UserContext(), req.RoleIDs)
 	if err != nil {
 		return err
 	}
 
 	// Use transaction to assign roles
-	return s.db.Transaction(func(tx *gorm.DB) error {
+	return s.db.WithContext(ctx.UserContext()).Transaction(func(tx *gorm.DB) error {
+		if err := guardSuperAdminRemoval(tx, user, roles); err != nil {
+			return err
+		}
+
 		// Clear existing roles
 		if err := tx.Model(user).Association("Roles").Clear(); err != nil {
 			return err
@@ -171,28 +225,93 @@ func (s *roleService) AssignRolesToUser(ctx *fiber.Ctx, req *dto.AssignRoleReque
 	})
 }
 
-func (s *roleService) GetUserRoles(ctx *fiber.Ctx, userID string) ([]dto.RoleResponse, error) {
-	user, err := s.userRepo.GetByID(ctx.Context(), userID)
-	if err != nil || user == nil {
-		return nil, errors.New("user not found")
+// guardSuperAdminRemoval refuses, inside tx, to replace a user's roles with newRoles when
+// that would take super_admin away from the last active user holding it. Suspended users
+// do not count, since they cannot act as super admin anyway.
+func guardSuperAdminRemoval(tx *gorm.DB, user *entities.User, newRoles []entities.Role) error {
+	if !user.IsActive || !user.HasRole(entities.RoleSuperAdmin) {
+		return nil
+	}
+	for _, role := range newRoles {
+		if role.Name == entities.RoleSuperAdmin {
+			return nil
+		}
 	}
 
-	// Load user roles
-	var userWithRoles entities.User
-	if err := s.db.Preload("Roles.Permissions").Where("id = ?", userID).First(&userWithRoles).Error; err != nil {
+	holders, err := lockSuperAdminHolders(tx)
+	if err != nil {
+		return err
+	}
+	if holders <= 1 {
+		return services.ErrLastSuperAdmin
+	}
+	return nil
+}
+
+// lockSuperAdminHolders locks the super_admin role row until tx ends and returns how many
+// active users hold the role. Every change that can take away the last super_admin takes
+// this lock first, so two such changes cannot both see the other's holder and proceed.
+func lockSuperAdminHolders(tx *gorm.DB) (int64, error) {
+	var role entities.Role
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", entities.RoleSuperAdmin).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var holders int64
+	err = tx.Model(&entities.User{}).
+		Joins("JOIN user_roles ON user_roles.user_id = users.id").
+		Where("user_roles.role_id = ? AND users.is_active", role.ID).
+		Distinct("users.id").
+		Count(&holders).Error
+	return holders, err
+}
+
+func (s *roleService) GetUserRoles(ctx *fiber.Ctx, userID string) ([]dto.RoleResponse, error) {
+	user, err := s.userRepo.GetWithRoles(ctx.UserContext(), userID)
+	if err != nil {
 		return nil, err
 	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
 
-	responses := make([]dto.RoleResponse, len(userWithRoles.Roles))
-	for i, role := range userWithRoles.Roles {
+	responses := make([]dto.RoleResponse, len(user.Roles))
+	for i, role := range user.Roles {
 		responses[i] = *dto.NewRoleResponse(&role)
 	}
 
 	return responses, nil
 }
 
+// GetUserPermissions returns the effective permission set of a user, flattened across
+// all of their roles, deduplicated and sorted by name.
+func (s *roleService) GetUserPermissions(ctx *fiber.Ctx, userID string) (*dto.UserPermissionsResponse, error) {
+	user, err := s.userRepo.GetWithRoles(ctx.UserContext(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	permissions := user.GetPermissions()
+	if permissions == nil {
+		permissions = []string{}
+	}
+	sort.Strings(permissions)
+
+	return &dto.UserPermissionsResponse{
+		UserID:      user.ID,
+		Permissions: permissions,
+	}, nil
+}
+
 func (s *roleService) GetAllPermissions(ctx *fiber.Ctx) ([]dto.PermissionResponse, error) {
-	permissions, err := s.permissionRepo.GetAll(ctx.Context())
+	permissions, err := s.permissionRepo.GetAll(ctx.UserContext())
 	if err != nil {
 		return nil, err
 	}
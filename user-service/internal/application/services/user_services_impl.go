@@ -1,42 +1,84 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/jwt"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/pagination"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
 	"gorm.io/gorm"
 )
 
 type userService struct {
-	userRepo repositories.UserRepository
-	db       *gorm.DB
+	userRepo   repositories.UserRepository
+	db         *gorm.DB
+	jwtManager *jwt.TokenManager
 }
 
 // NewUserService creates and returns a services.UserService backed by the provided
 // UserRepository and GORM DB instance. The returned service uses the repository for
-// data access and the DB for queries that require gorm operations.
-func NewUserService(userRepo repositories.UserRepository, db *gorm.DB) services.UserService {
+// data access, the DB for queries that require gorm operations and jwtManager to
+// revoke sessions of deleted accounts.
+func NewUserService(userRepo repositories.UserRepository, db *gorm.DB, jwtManager *jwt.TokenManager) services.UserService {
 	return &userService{
-		userRepo: userRepo,
-		db:       db,
+		userRepo:   userRepo,
+		db:         db,
+		jwtManager: jwtManager,
 	}
 }
 
-func (s *userService) GetUser(ctx *fiber.Ctx, id string) (*dto.UserResponse, error) {
-	user, err := s.userRepo.GetByID(ctx.Context(), id)
+// GetUser returns the user with their roles. The flattened permission set is only
+// included when includePermissions is true.
+func (s *userService) GetUser(ctx *fiber.Ctx, id string, includePermissions bool) (*dto.UserResponse, error) {
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, services.ErrUserNotFound
 	}
 
-	return dto.NewUserResponse(user), nil
+	response := dto.NewUserResponse(user)
+	if !includePermissions {
+		response.WithoutPermissions()
+	}
+
+	return response, nil
+}
+
+// GetUsersByIDs resolves a set of user IDs to their public profiles in one query.
+// Duplicate IDs are collapsed and IDs that match no user are omitted.
+func (s *userService) GetUsersByIDs(ctx *fiber.Ctx, ids []string) ([]dto.PublicUserResponse, error) {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx.UserContext(), unique)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PublicUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *dto.NewPublicUserResponse(&user)
+	}
+
+	return responses, nil
 }
 
 func (s *userService) GetAllUsers(ctx *fiber.Ctx, page, limit int) (*dto.PaginatedResponse, error) {
@@ -46,14 +88,14 @@ func (s *userService) GetAllUsers(ctx *fiber.Ctx, page, limit int) (*dto.Paginat
 	var totalCount int64
 
 	// Get total count
-	if err := s.db.WithContext(ctx.Context()).
+	if err := s.db.WithContext(ctx.UserContext()).
 		Model(&entities.User{}).
 		Count(&totalCount).Error; err != nil {
 		return nil, err
 	}
 
 	// Get users with pagination
-	if err := s.db.WithContext(ctx.Context()).
+	if err := s.db.WithContext(ctx.UserContext()).
 		Preload("Roles").
 		Preload("Profile").
 		Offset(offset).
@@ -83,49 +125,223 @@ func (s *userService) GetAllUsers(ctx *fiber.Ctx, page, limit int) (*dto.Paginat
 	}, nil
 }
 
+// GetAllUsersByCursor returns a page of users using keyset pagination on (created_at, id),
+// which stays fast on deep pages where offset pagination degrades.
+func (s *userService) GetAllUsersByCursor(ctx *fiber.Ctx, cursor string, limit int) (*dto.CursorPaginatedResponse, error) {
+	after, err := pagination.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	users, err := s.userRepo.ListByCursor(ctx.UserContext(), after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(users) > limit
+	if hasNext {
+		users = users[:limit]
+	}
+
+	userResponses := make([]dto.UserListResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = *dto.NewUserListResponse(&user)
+	}
+
+	response := &dto.CursorPaginatedResponse{
+		Data:    userResponses,
+		Limit:   limit,
+		HasNext: hasNext,
+	}
+	if hasNext {
+		last := users[len(users)-1]
+		response.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	return response, nil
+}
+
 func (s *userService) UpdateUser(ctx *fiber.Ctx, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepo.GetByID(ctx.Context(), id)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, services.ErrUserNotFound
 	}
 
 	// Update fields
+	deactivated := false
 	if req.Name != nil {
 		user.Name = *req.Name
 	}
 	if req.IsActive != nil {
+		deactivated = user.IsActive && !*req.IsActive
 		user.IsActive = *req.IsActive
 	}
 
-	if err := s.userRepo.Update(ctx.Context(), user); err != nil {
+	if err := s.saveUser(ctx.UserContext(), user, deactivated); err != nil {
 		return nil, err
 	}
 
+	// Deactivating through a plain update signs the user out just like SuspendUser
+	if deactivated {
+		if err := s.jwtManager.RevokeUser(id); err != nil {
+			return nil, fmt.Errorf("user deactivated but failed to revoke sessions: %w", err)
+		}
+	}
+
 	return dto.NewUserResponse(user), nil
 }
 
 func (s *userService) DeleteUser(ctx *fiber.Ctx, id string) error {
-	user, err := s.userRepo.GetByID(ctx.Context(), id)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return services.ErrUserNotFound
+	}
+
+	if err := s.userRepo.Delete(ctx.UserContext(), id); err != nil {
+		return translateConstraintError(err)
+	}
+	return nil
+}
+
+func (s *userService) SuspendUser(ctx *fiber.Ctx, id, actorID, reason string) (*dto.UserResponse, error) {
+	user, err := s.setUserStatus(ctx, id, actorID, reason, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The gateway only rejects access tokens that are blacklisted, so the user's current
+	// one is blacklisted along with the refresh token, and their cached roles are dropped
+	if err := s.jwtManager.RevokeUser(id); err != nil {
+		return nil, fmt.Errorf("user suspended but failed to revoke sessions: %w", err)
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+func (s *userService) ActivateUser(ctx *fiber.Ctx, id, actorID, reason string) (*dto.UserResponse, error) {
+	user, err := s.setUserStatus(ctx, id, actorID, reason, true)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewUserResponse(user), nil
+}
+
+// setUserStatus sets IsActive and records who changed it, when and why
+func (s *userService) setUserStatus(ctx *fiber.Ctx, id, actorID, reason string, active bool) (*entities.User, error) {
+	if id == actorID {
+		return nil, services.ErrSelfStatusChange
+	}
+
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, services.ErrUserNotFound
+	}
+
+	now := time.Now()
+	deactivated := user.IsActive && !active
+	user.IsActive = active
+	user.StatusReason = strings.TrimSpace(reason)
+	user.StatusChangedBy = actorID
+	user.StatusChangedAt = &now
+
+	if err := s.saveUser(ctx.UserContext(), user, deactivated); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// saveUser stores changes to user. Suspended users do not count as super admins, so when
+// the change deactivates a super_admin it holds the same lock as role changes and fails
+// with services.ErrLastSuperAdmin unless another active one remains.
+func (s *userService) saveUser(ctx context.Context, user *entities.User, deactivated bool) error {
+	if !deactivated || !user.HasRole(entities.RoleSuperAdmin) {
+		return translateConstraintError(s.userRepo.Update(ctx, user))
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The user is still active in the database, so they are among the holders
+		holders, err := lockSuperAdminHolders(tx)
+		if err != nil {
+			return err
+		}
+		if holders <= 1 {
+			return services.ErrLastSuperAdmin
+		}
+		return tx.Save(user).Error
+	})
+	return translateConstraintError(err)
+}
+
+// DeleteMe soft-deletes the caller's own account after checking their password, and
+// revokes all of their sessions. The last active super_admin cannot delete themselves.
+func (s *userService) DeleteMe(ctx *fiber.Ctx, id string, req *dto.DeleteAccountRequest) error {
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return err
 	}
 	if user == nil {
-		return errors.New("user not found")
+		return services.ErrUserNotFound
 	}
 
-	return s.userRepo.Delete(ctx.Context(), id)
+	if err := password.CheckPassword(req.Password, user.Password); err != nil {
+		return services.ErrPasswordMismatch
+	}
+
+	switch {
+	case user.IsActive && user.HasRole(entities.RoleSuperAdmin):
+		// Deleting a super_admin takes the role away, so it holds the same lock as role
+		// changes while checking that another one remains
+		err = s.db.WithContext(ctx.UserContext()).Transaction(func(tx *gorm.DB) error {
+			if err := guardSuperAdminRemoval(tx, user, nil); err != nil {
+				return err
+			}
+			if req.DeleteProfile {
+				if err := tx.Where("user_id = ?", id).Delete(&entities.UserProfile{}).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Where("id = ?", id).Delete(&entities.User{}).Error
+		})
+	case req.DeleteProfile:
+		err = s.userRepo.DeleteWithProfile(ctx.UserContext(), id)
+	default:
+		err = s.userRepo.Delete(ctx.UserContext(), id)
+	}
+	if err != nil {
+		return translateConstraintError(err)
+	}
+
+	if err := s.jwtManager.RevokeUser(id); err != nil {
+		return fmt.Errorf("account deleted but failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// translateConstraintError reports constraint violations as services.ErrUserConflict
+// and passes every other error through unchanged
+func translateConstraintError(err error) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("%w: %v", services.ErrUserConflict, err)
+	}
+	return err
 }
 
 func (s *userService) GetUserRBACInfo(ctx *fiber.Ctx, id string) (*dto.UserRBACResponse, error) {
-	user, err := s.userRepo.GetByID(ctx.Context(), id)
+	user, err := s.userRepo.GetByID(ctx.UserContext(), id)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, services.ErrUserNotFound
 	}
 
 	// Extract role names
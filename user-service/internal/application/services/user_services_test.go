@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/jwt"
+)
+
+func TestDeleteMeRevokesSessions(t *testing.T) {
+	service, users, store := newTestUserService(t)
+	user := addUser(t, users, "ada@example.com", true, "customer")
+	tokens, err := service.jwtManager.GenerateTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	store.Set("user_rbac:"+user.ID, `{"roles":["customer"],"permissions":[]}`)
+
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		err = service.DeleteMe(c, user.ID, &dto.DeleteAccountRequest{Password: testPassword})
+	})
+	if err != nil {
+		t.Fatalf("DeleteMe: %v", err)
+	}
+
+	if stored, _ := users.GetByID(context.Background(), user.ID); stored != nil {
+		t.Error("user still stored")
+	}
+	if _, err := service.jwtManager.ValidateToken(tokens[jwt.AccessToken], jwt.AccessToken); err == nil {
+		t.Error("access token still valid after deletion")
+	}
+	if _, err := service.jwtManager.ValidateToken(tokens[jwt.RefreshToken], jwt.RefreshToken); err == nil {
+		t.Error("refresh token still valid after deletion")
+	}
+	if store.Has("user_rbac:" + user.ID) {
+		t.Error("cached roles and permissions were not dropped")
+	}
+}
+
+func TestDeleteMeWithWrongPasswordKeepsAccount(t *testing.T) {
+	service, users, _ := newTestUserService(t)
+	user := addUser(t, users, "ada@example.com", true, "customer")
+	tokens, err := service.jwtManager.GenerateTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		err = service.DeleteMe(c, user.ID, &dto.DeleteAccountRequest{Password: "wrong-password"})
+	})
+
+	if !errors.Is(err, services.ErrPasswordMismatch) {
+		t.Fatalf("err = %v, want ErrPasswordMismatch", err)
+	}
+	if stored, _ := users.GetByID(context.Background(), user.ID); stored == nil {
+		t.Error("user was deleted")
+	}
+	if _, err := service.jwtManager.ValidateToken(tokens[jwt.AccessToken], jwt.AccessToken); err != nil {
+		t.Errorf("access token revoked: %v", err)
+	}
+}
+
+func TestSuspendUserRevokesSessions(t *testing.T) {
+	service, users, store := newTestUserService(t)
+	user := addUser(t, users, "ada@example.com", true, "customer")
+	tokens, err := service.jwtManager.GenerateTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	store.Set("user_rbac:"+user.ID, `{"roles":["customer"],"permissions":[]}`)
+
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.SuspendUser(c, user.ID, "admin", "chargebacks")
+	})
+	if err != nil {
+		t.Fatalf("SuspendUser: %v", err)
+	}
+
+	if stored, _ := users.GetByID(context.Background(), user.ID); stored.IsActive {
+		t.Error("user is still active")
+	}
+	if _, err := service.jwtManager.ValidateToken(tokens[jwt.AccessToken], jwt.AccessToken); err == nil {
+		t.Error("access token still valid after suspension")
+	}
+	if _, err := service.jwtManager.ValidateToken(tokens[jwt.RefreshToken], jwt.RefreshToken); err == nil {
+		t.Error("refresh token still valid after suspension")
+	}
+	if store.Has("user_rbac:" + user.ID) {
+		t.Error("cached roles and permissions were not dropped")
+	}
+}
+
+func TestSuspendedUserCannotLogInOrRefresh(t *testing.T) {
+	auth, users, _ := newTestAuthService(t)
+	service := &userService{userRepo: users, jwtManager: auth.jwtManager}
+	user := addUser(t, users, "ada@example.com", true, "customer")
+
+	var (
+		session *dto.AuthResponse
+		err     error
+	)
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		session, err = auth.Login(c, &dto.LoginRequest{Email: "ada@example.com", Password: testPassword})
+	})
+	if err != nil {
+		t.Fatalf("Login before suspension: %v", err)
+	}
+
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.SuspendUser(c, user.ID, "admin", "chargebacks")
+	})
+	if err != nil {
+		t.Fatalf("SuspendUser: %v", err)
+	}
+
+	var loginErr, refreshErr error
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, loginErr = auth.Login(c, &dto.LoginRequest{Email: "ada@example.com", Password: testPassword})
+	})
+	withCtx(t, map[string]string{"Authorization": "Bearer " + session.RefreshToken}, func(c *fiber.Ctx) {
+		_, refreshErr = auth.RefreshToken(c, session.RefreshToken)
+	})
+
+	if !errors.Is(loginErr, services.ErrAccountDisabled) {
+		t.Errorf("login err = %v, want ErrAccountDisabled", loginErr)
+	}
+	if refreshErr == nil {
+		t.Error("refresh succeeded after suspension")
+	}
+}
+
+func TestSuspendSelfIsRejected(t *testing.T) {
+	service, users, _ := newTestUserService(t)
+	user := addUser(t, users, "ada@example.com", true, "super_admin")
+
+	var err error
+	withCtx(t, nil, func(c *fiber.Ctx) {
+		_, err = service.SuspendUser(c, user.ID, user.ID, "")
+	})
+
+	if !errors.Is(err, services.ErrSelfStatusChange) {
+		t.Fatalf("err = %v, want ErrSelfStatusChange", err)
+	}
+	if stored, _ := users.GetByID(context.Background(), user.ID); !stored.IsActive {
+		t.Error("user suspended themselves")
+	}
+}
@@ -4,20 +4,70 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	AppEnv    string
-	AppPort   string
+	Database          DatabaseConfig
+	Redis             RedisConfig
+	JWT               JWTConfig
+	AppEnv            string
+	AppPort           string
+	LogLevel          string
+	LogBodySampleRate int
+	Maintenance       MaintenanceConfig
+	RequestTimeout    time.Duration
+	CORSOrigins       string
+	AdminToken        string
+	ReloadFile        string
+	DefaultUserRole   string
+	StoreServiceURL   string
+	CartServiceURL    string
+	DataExport        DataExportConfig
+	PasswordPolicy    password.Policy
+	BreachedPasswords BreachedPasswordsConfig
+	InternalSigning   InternalSigningConfig
+}
+
+// InternalSigningConfig holds the shared secret used to verify HMAC-signed requests
+// from other services and how far their timestamps may drift. Without a secret the
+// routes that require a signature reject every request.
+type InternalSigningConfig struct {
+	Secret  string
+	MaxSkew time.Duration
+}
+
+// BreachedPasswordsConfig controls rejecting passwords found by a Have I Been Pwned
+// style range API. Only a 5 character hash prefix is sent, and the check is skipped
+// if the API cannot be reached within Timeout.
+type BreachedPasswordsConfig struct {
+	Enabled  bool
+	RangeURL string
+	Timeout  time.Duration
+}
+
+// DataExportConfig limits how often a user can download their data export, since each
+// export reads from several services
+type DataExportConfig struct {
+	MaxRequests int
+	Window      time.Duration
+}
+
+// MaintenanceConfig controls maintenance mode. Enabled forces it on; otherwise it
+// can be toggled at runtime through the admin endpoint.
+type MaintenanceConfig struct {
+	Enabled    bool
+	RetryAfter time.Duration
 }
 
 type JWTConfig struct {
-	PrivateKey string
-	Expiration time.Duration
+	PrivateKey        string
+	Expiration        time.Duration
 	RefreshExpiration time.Duration
+	// StrictAccessTokenCheck requires access tokens to match the one stored in Redis,
+	// so Logout revokes them immediately instead of when they expire.
+	StrictAccessTokenCheck bool
 }
 
 type DatabaseConfig struct {
@@ -27,6 +77,11 @@ type DatabaseConfig struct {
 	DBName   string
 	Port     int
 	SSLMode  string
+	// QueryTimeout bounds each repository operation
+	QueryTimeout time.Duration
+	// FieldEncryptionKey is a base64 AES-256 key used to encrypt sensitive profile
+	// fields at rest. Leave empty to store them in plaintext.
+	FieldEncryptionKey string
 }
 
 type RedisConfig struct {
@@ -45,18 +100,38 @@ func Load() *Config {
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	redisPort, _ := strconv.Atoi(getEnv("REDIS_PORT", "6379"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	queryTimeout, _ := time.ParseDuration(getEnv("DB_QUERY_TIMEOUT", "5s"))
 
 	expiration, _ := time.ParseDuration(getEnv("JWT_EXPIRATION", "15m"))
 	refreshExpiration, _ := time.ParseDuration(getEnv("JWT_REFRESH_EXPIRATION", "720h"))
+	strictAccessTokenCheck, _ := strconv.ParseBool(getEnv("JWT_STRICT_ACCESS_TOKEN_CHECK", "false"))
+	logBodySampleRate, _ := strconv.Atoi(getEnv("LOG_BODY_SAMPLE_RATE", "1"))
+	maintenanceEnabled, _ := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	maintenanceRetryAfter, _ := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "2m"))
+	requestTimeout, _ := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "30s"))
+	dataExportMaxRequests, _ := strconv.Atoi(getEnv("DATA_EXPORT_RATE_LIMIT", "3"))
+	dataExportWindow, _ := time.ParseDuration(getEnv("DATA_EXPORT_RATE_WINDOW", "1h"))
+	passwordMinLength, _ := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+	passwordMaxLength, _ := strconv.Atoi(getEnv("PASSWORD_MAX_LENGTH", "72"))
+	passwordRequireUpper, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_UPPER", "true"))
+	passwordRequireLower, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_LOWER", "true"))
+	passwordRequireDigit, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_DIGIT", "true"))
+	passwordRequireSymbol, _ := strconv.ParseBool(getEnv("PASSWORD_REQUIRE_SYMBOL", "false"))
+	breachedPasswordsEnabled, _ := strconv.ParseBool(getEnv("BREACHED_PASSWORD_CHECK", "false"))
+	breachedPasswordsTimeout, _ := time.ParseDuration(getEnv("BREACHED_PASSWORD_CHECK_TIMEOUT", "2s"))
+	internalSignatureMaxSkew, _ := time.ParseDuration(getEnv("INTERNAL_SIGNATURE_MAX_SKEW", "5m"))
 
 	return &Config{
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "postgres"),
-			Port:     dbPort,
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:         getEnv("DB_HOST", "localhost"),
+			User:         getEnv("DB_USER", "postgres"),
+			Password:     getEnv("DB_PASSWORD", "postgres"),
+			DBName:       getEnv("DB_NAME", "postgres"),
+			Port:         dbPort,
+			SSLMode:      getEnv("DB_SSLMODE", "disable"),
+			QueryTimeout: queryTimeout,
+
+			FieldEncryptionKey: getEnv("PROFILE_ENCRYPTION_KEY", ""),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -65,12 +140,49 @@ func Load() *Config {
 			DB:       redisDB,
 		},
 		JWT: JWTConfig{
-			PrivateKey:        getEnv("JWT_PRIVATE_KEY", "your-private-key"),
-			Expiration:        expiration,
-			RefreshExpiration: refreshExpiration,
+			PrivateKey:             getEnv("JWT_PRIVATE_KEY", "your-private-key"),
+			Expiration:             expiration,
+			RefreshExpiration:      refreshExpiration,
+			StrictAccessTokenCheck: strictAccessTokenCheck,
+		},
+		AppEnv:            getEnv("APP_ENV", "development"),
+		AppPort:           getEnv("APP_PORT", "3000"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		LogBodySampleRate: logBodySampleRate,
+		Maintenance: MaintenanceConfig{
+			Enabled:    maintenanceEnabled,
+			RetryAfter: maintenanceRetryAfter,
+		},
+		RequestTimeout: requestTimeout,
+		CORSOrigins:    getEnv("CORS_ALLOW_ORIGINS", "*"),
+		AdminToken:     getEnv("ADMIN_TOKEN", ""),
+		ReloadFile:     getEnv("CONFIG_RELOAD_FILE", ""),
+
+		DefaultUserRole: getEnv("DEFAULT_USER_ROLE", "customer"),
+		StoreServiceURL: getEnv("STORE_SERVICE_URL", "http://store-service:3006"),
+		CartServiceURL:  getEnv("CART_SERVICE_URL", "http://shopping-cart-service:3005"),
+		DataExport: DataExportConfig{
+			MaxRequests: dataExportMaxRequests,
+			Window:      dataExportWindow,
+		},
+		// bcrypt rejects passwords over 72 bytes, so MaxLength is checked in bytes
+		PasswordPolicy: password.Policy{
+			MinLength:     passwordMinLength,
+			MaxLength:     passwordMaxLength,
+			RequireUpper:  passwordRequireUpper,
+			RequireLower:  passwordRequireLower,
+			RequireDigit:  passwordRequireDigit,
+			RequireSymbol: passwordRequireSymbol,
+		},
+		BreachedPasswords: BreachedPasswordsConfig{
+			Enabled:  breachedPasswordsEnabled,
+			RangeURL: getEnv("BREACHED_PASSWORD_RANGE_URL", "https://api.pwnedpasswords.com/range"),
+			Timeout:  breachedPasswordsTimeout,
+		},
+		InternalSigning: InternalSigningConfig{
+			Secret:  getEnv("INTERNAL_SIGNING_SECRET", ""),
+			MaxSkew: internalSignatureMaxSkew,
 		},
-		AppEnv:  getEnv("APP_ENV", "development"),
-		AppPort: getEnv("APP_PORT", "3000"),
 	}
 }
 
@@ -7,6 +7,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// RoleSuperAdmin is the role that grants full system access. At least one active
+// user must always hold it.
+const RoleSuperAdmin = "super_admin"
+
 type Role struct {
 	ID          string         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
 	Name        string         `json:"name" gorm:"unique;not null"`
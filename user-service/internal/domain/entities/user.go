@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,7 +10,7 @@ import (
 
 type User struct {
 	ID        string         `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Email     string         `json:"email" gorm:"unique;not null"`
+	Email     string         `json:"email" gorm:"not null"`
 	Password  string         `json:"-" gorm:"not null"`
 	Name      string         `json:"name" gorm:"not null"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
@@ -18,12 +19,30 @@ type User struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// StatusReason, StatusChangedBy and StatusChangedAt record the last admin suspension
+	// or activation; they are empty for users never suspended
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedBy string     `json:"status_changed_by,omitempty" gorm:"type:varchar(36)"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
 
+// NormalizeEmail returns the canonical, trimmed and lowercase form of an email address
+// used for storage and lookups, so addresses differing only in case map to the same account.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeName trims a person's name and collapses internal runs of whitespace to a
+// single space, so "  Jane   Doe " is stored as "Jane Doe".
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
 // BeforeCreate hook to set default values
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == "" {
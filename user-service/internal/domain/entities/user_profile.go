@@ -13,11 +13,11 @@ type UserProfile struct {
 	User        User           `json:"user" gorm:"foreignKey:UserID"`
 	FirstName   string         `json:"first_name"`
 	LastName    string         `json:"last_name"`
-	Phone       string         `json:"phone"`
+	Phone       string         `json:"phone" gorm:"serializer:encrypted"`
 	Avatar      string         `json:"avatar"`
 	DateOfBirth *time.Time     `json:"date_of_birth"`
 	Gender      string         `json:"gender" gorm:"type:varchar(10)"`
-	Address     string         `json:"address"`
+	Address     string         `json:"address" gorm:"serializer:encrypted"`
 	City        string         `json:"city"`
 	State       string         `json:"state"`
 	Country     string         `json:"country"`
@@ -26,6 +26,10 @@ type UserProfile struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// UndecryptableFields lists the columns whose stored value could not be decrypted.
+	// They are read as empty and left untouched on save unless replaced.
+	UndecryptableFields []string `json:"-" gorm:"-"`
 }
 
 func (UserProfile) TableName() string {
@@ -38,3 +42,18 @@ func (up *UserProfile) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// MarkUndecryptable records that column could not be decrypted when the profile was read
+func (up *UserProfile) MarkUndecryptable(column string) {
+	up.UndecryptableFields = append(up.UndecryptableFields, column)
+}
+
+// ReplaceUndecryptable drops column from UndecryptableFields once it has been given a new value
+func (up *UserProfile) ReplaceUndecryptable(column string) {
+	for i, field := range up.UndecryptableFields {
+		if field == column {
+			up.UndecryptableFields = append(up.UndecryptableFields[:i], up.UndecryptableFields[i+1:]...)
+			return
+		}
+	}
+}
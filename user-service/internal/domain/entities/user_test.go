@@ -0,0 +1,31 @@
+package entities
+
+import "testing"
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := map[string]string{
+		"jane@example.com":         "jane@example.com",
+		"  Jane@Example.COM ":      "jane@example.com",
+		"\tJANE@EXAMPLE.COM\n":     "jane@example.com",
+		"jane.doe+shop@example.io": "jane.doe+shop@example.io",
+	}
+	for input, want := range tests {
+		if got := NormalizeEmail(input); got != want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"Jane Doe":            "Jane Doe",
+		"  Jane   Doe ":       "Jane Doe",
+		"\tJane \n Mary Doe ": "Jane Mary Doe",
+		"   ":                 "",
+	}
+	for input, want := range tests {
+		if got := NormalizeName(input); got != want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
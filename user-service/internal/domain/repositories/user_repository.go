@@ -4,13 +4,19 @@ import (
 	"context"
 
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/pagination"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	GetByID(ctx context.Context, id string) (*entities.User, error)
+	GetWithRoles(ctx context.Context, id string) (*entities.User, error)
+	GetByIDs(ctx context.Context, ids []string) ([]entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
 	Delete(ctx context.Context, id string) error
+	// DeleteWithProfile deletes the user and their profile in one transaction
+	DeleteWithProfile(ctx context.Context, id string) error
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ListByCursor(ctx context.Context, cursor *pagination.Cursor, limit int) ([]entities.User, error)
 }
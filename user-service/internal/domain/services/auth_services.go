@@ -1,14 +1,22 @@
 package services
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
 )
 
+// ErrAccountDisabled is returned when a suspended user tries to sign in
+var ErrAccountDisabled = errors.New("account disabled")
+
 type AuthService interface {
 	Register(ctx *fiber.Ctx, req *dto.RegisterRequest) (*dto.AuthResponse, error)
 	Login(ctx *fiber.Ctx, req *dto.LoginRequest) (*dto.AuthResponse, error)
 	Logout(ctx *fiber.Ctx) error
 	RefreshToken(ctx *fiber.Ctx, refreshToken string) (*dto.AuthResponse, error)
 	ValidateToken(ctx *fiber.Ctx, token string) (*dto.UserResponse, error)
+	// PasswordPolicy returns the rules new passwords must satisfy
+	PasswordPolicy() password.Policy
 }
@@ -0,0 +1,10 @@
+package services
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+)
+
+type DataExportService interface {
+	ExportUserData(ctx *fiber.Ctx, userID string) (*dto.UserDataExport, error)
+}
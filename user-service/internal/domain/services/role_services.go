@@ -11,7 +11,9 @@ type RoleService interface {
 	GetAllRoles(ctx *fiber.Ctx) ([]dto.RoleResponse, error)
 	UpdateRole(ctx *fiber.Ctx, id string, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error)
 	DeleteRole(ctx *fiber.Ctx, id string) error
+	CloneRole(ctx *fiber.Ctx, sourceID string, newName string) (*dto.RoleResponse, error)
 	AssignRolesToUser(ctx *fiber.Ctx, req *dto.AssignRoleRequest) error
 	GetUserRoles(ctx *fiber.Ctx, userID string) ([]dto.RoleResponse, error)
+	GetUserPermissions(ctx *fiber.Ctx, userID string) (*dto.UserPermissionsResponse, error)
 	GetAllPermissions(ctx *fiber.Ctx) ([]dto.PermissionResponse, error)
 }
@@ -1,14 +1,39 @@
 package services
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 )
 
+// ErrUserNotFound is returned when the requested user does not exist
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrUserConflict is returned when a user change violates a database constraint,
+// such as other records still referencing the user
+var ErrUserConflict = errors.New("user cannot be changed because it conflicts with existing data")
+
+// ErrSelfStatusChange is returned when an admin tries to suspend or activate their own account
+var ErrSelfStatusChange = errors.New("you cannot change the status of your own account")
+
+// ErrPasswordMismatch is returned when a sensitive action is confirmed with the wrong password
+var ErrPasswordMismatch = errors.New("password is incorrect")
+
+// ErrLastSuperAdmin is returned when an operation would leave the system without any super admin.
+var ErrLastSuperAdmin = errors.New("operation would remove the last super_admin; assign super_admin to another user first")
+
 type UserService interface {
-	GetUser(ctx *fiber.Ctx, id string) (*dto.UserResponse, error)
+	GetUser(ctx *fiber.Ctx, id string, includePermissions bool) (*dto.UserResponse, error)
+	GetUsersByIDs(ctx *fiber.Ctx, ids []string) ([]dto.PublicUserResponse, error)
 	GetAllUsers(ctx *fiber.Ctx, page, limit int) (*dto.PaginatedResponse, error)
+	GetAllUsersByCursor(ctx *fiber.Ctx, cursor string, limit int) (*dto.CursorPaginatedResponse, error)
 	UpdateUser(ctx *fiber.Ctx, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error)
 	DeleteUser(ctx *fiber.Ctx, id string) error
+	// SuspendUser deactivates the account, records the reason and acting admin, and revokes
+	// its sessions; ActivateUser reverses it
+	SuspendUser(ctx *fiber.Ctx, id, actorID, reason string) (*dto.UserResponse, error)
+	ActivateUser(ctx *fiber.Ctx, id, actorID, reason string) (*dto.UserResponse, error)
+	DeleteMe(ctx *fiber.Ctx, id string, req *dto.DeleteAccountRequest) error
 	GetUserRBACInfo(ctx *fiber.Ctx, id string) (*dto.UserRBACResponse, error) // New method
 }
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/crypto"
+	"gorm.io/gorm/schema"
+)
+
+// encryptedPrefix marks column values written by the encrypted serializer, so rows stored
+// before encryption was enabled can still be read as plaintext
+const encryptedPrefix = "enc:v1:"
+
+// fieldEncryptionKey encrypts string columns tagged serializer:encrypted; it is set from
+// config on connect. Without a key those columns are stored in plaintext.
+var fieldEncryptionKey []byte
+
+func init() {
+	schema.RegisterSerializer("encrypted", encryptedSerializer{})
+}
+
+// SetFieldEncryptionKey sets the AES key for encrypted columns from its base64 encoding.
+// An empty value disables encryption of new writes.
+func SetFieldEncryptionKey(encoded string) error {
+	if encoded == "" {
+		fieldEncryptionKey = nil
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("field encryption key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("field encryption key must be 32 bytes, got %d", len(key))
+	}
+	fieldEncryptionKey = key
+	return nil
+}
+
+// undecryptableRecorder is implemented by entities that want a failed decryption reported
+// instead of failing the whole read
+type undecryptableRecorder interface {
+	MarkUndecryptable(column string)
+}
+
+// encryptedSerializer transparently encrypts string columns on write and decrypts them on
+// read, including when they are loaded through Preload
+type encryptedSerializer struct{}
+
+func (encryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported value %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	if strings.HasPrefix(value, encryptedPrefix) {
+		plaintext, err := decryptField(value)
+		if err != nil {
+			recorder, ok := asUndecryptableRecorder(dst)
+			if !ok {
+				return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+			}
+			// Never log the ciphertext itself
+			log.Warn().Err(err).Str("table", field.Schema.Table).Str("field", field.DBName).Msg("Failed to decrypt field")
+			recorder.MarkUndecryptable(field.DBName)
+			plaintext = ""
+		}
+		value = plaintext
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+func decryptField(value string) (string, error) {
+	if fieldEncryptionKey == nil {
+		return "", errors.New("no encryption key is configured")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := crypto.DecryptAES(fieldEncryptionKey, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func asUndecryptableRecorder(dst reflect.Value) (undecryptableRecorder, bool) {
+	if dst.Kind() != reflect.Ptr {
+		if !dst.CanAddr() {
+			return nil, false
+		}
+		dst = dst.Addr()
+	}
+	recorder, ok := dst.Interface().(undecryptableRecorder)
+	return recorder, ok
+}
+
+func (encryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, errors.New("encrypted serializer only supports string fields")
+	}
+	if fieldEncryptionKey == nil || value == "" {
+		return value, nil
+	}
+
+	ciphertext, err := crypto.EncryptAES(fieldEncryptionKey, []byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt field %s: %w", field.Name, err)
+	}
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
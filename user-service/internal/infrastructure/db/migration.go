@@ -47,6 +47,20 @@ func Migrate(db *gorm.DB, resetDb bool) error {
 		return fmt.Errorf("failed to migrate tables: %w", err)
 	}
 
+	// Emails are unique regardless of case among users that are not deleted, so a deleted
+	// account's address can register again. Drop the old constraint covering every row,
+	// then create the index; existing rows that differ only in case must be merged
+	// manually before it can be created.
+	for _, constraint := range []string{"uni_users_email", "users_email_key"} {
+		if err := db.Exec("ALTER TABLE users DROP CONSTRAINT IF EXISTS " + constraint).Error; err != nil {
+			return fmt.Errorf("failed to drop email constraint %s: %w", constraint, err)
+		}
+	}
+	err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email)) WHERE deleted_at IS NULL").Error
+	if err != nil {
+		return fmt.Errorf("failed to create case-insensitive email index: %w", err)
+	}
+
 	// Seed default roles and permissions if they don't exist
 	if err := seedDefaultRolesAndPermissions(db); err != nil {
 		return fmt.Errorf("failed to seed default roles and permissions: %w", err)
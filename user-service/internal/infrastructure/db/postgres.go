@@ -46,12 +46,18 @@ func ConnectWithoutMigration(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
+		Logger:         logger.Default.LogMode(logLevel),
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	SetQueryTimeout(cfg.Database.QueryTimeout)
+	if err := SetFieldEncryptionKey(cfg.Database.FieldEncryptionKey); err != nil {
+		return nil, fmt.Errorf("invalid PROFILE_ENCRYPTION_KEY: %w", err)
+	}
+
 	log.Println("Database connected successfully")
 	return db, nil
 }
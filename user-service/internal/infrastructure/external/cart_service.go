@@ -0,0 +1,29 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type CartServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewCartServiceClient(baseURL string) *CartServiceClient {
+	return &CartServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetCart returns the user's cart, as the shopping cart service reports it
+func (c *CartServiceClient) GetCart(ctx context.Context, userID string) (json.RawMessage, error) {
+	url := fmt.Sprintf("%s/api/cart/", c.baseURL)
+	return getAsUser(ctx, c.httpClient, "shopping cart service", url, userID)
+}
@@ -0,0 +1,47 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ServiceResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// getAsUser performs a GET on behalf of userID, the way the gateway forwards an
+// authenticated request, and returns the data of the service's response envelope
+func getAsUser(ctx context.Context, httpClient *http.Client, service, url, userID string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-User-Id", userID)
+	req.Header.Set("X-Internal-Service", "user-service")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", service, resp.StatusCode)
+	}
+
+	var serviceResp ServiceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&serviceResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !serviceResp.Success {
+		return nil, fmt.Errorf("%s error: %s", service, serviceResp.Error)
+	}
+
+	return serviceResp.Data, nil
+}
@@ -0,0 +1,29 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type StoreServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewStoreServiceClient(baseURL string) *StoreServiceClient {
+	return &StoreServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetUserStores returns the stores the user belongs to, as the store service reports them
+func (c *StoreServiceClient) GetUserStores(ctx context.Context, userID string) (json.RawMessage, error) {
+	url := fmt.Sprintf("%s/api/stores/?per_page=100", c.baseURL)
+	return getAsUser(ctx, c.httpClient, "store service", url, userID)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/db"
 	"gorm.io/gorm"
 )
 
@@ -20,10 +21,16 @@ func NewPermissionRepository(db *gorm.DB) repositories.PermissionRepository {
 }
 
 func (r *permissionRepository) Create(ctx context.Context, permission *entities.Permission) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Create(permission).Error
 }
 
 func (r *permissionRepository) GetByID(ctx context.Context, id string) (*entities.Permission, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var permission entities.Permission
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&permission).Error
 	if err != nil {
@@ -36,6 +43,9 @@ func (r *permissionRepository) GetByID(ctx context.Context, id string) (*entitie
 }
 
 func (r *permissionRepository) GetByName(ctx context.Context, name string) (*entities.Permission, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var permission entities.Permission
 	err := r.db.WithContext(ctx).Where("name = ?", name).First(&permission).Error
 	if err != nil {
@@ -48,20 +58,32 @@ func (r *permissionRepository) GetByName(ctx context.Context, name string) (*ent
 }
 
 func (r *permissionRepository) GetAll(ctx context.Context) ([]entities.Permission, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var permissions []entities.Permission
 	err := r.db.WithContext(ctx).Find(&permissions).Error
 	return permissions, err
 }
 
 func (r *permissionRepository) Update(ctx context.Context, permission *entities.Permission) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Save(permission).Error
 }
 
 func (r *permissionRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.Permission{}).Error
 }
 
 func (r *permissionRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var count int64
 	err := r.db.WithContext(ctx).Model(&entities.Permission{}).Where("name = ?", name).Count(&count).Error
 	if err != nil {
@@ -71,6 +93,9 @@ func (r *permissionRepository) ExistsByName(ctx context.Context, name string) (b
 }
 
 func (r *permissionRepository) GetByIDs(ctx context.Context, ids []string) ([]entities.Permission, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var permissions []entities.Permission
 	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&permissions).Error
 	return permissions, err
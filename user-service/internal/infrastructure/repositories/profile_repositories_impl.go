@@ -6,6 +6,7 @@ import (
 
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/db"
 	"gorm.io/gorm"
 )
 
@@ -19,10 +20,16 @@ func NewProfileRepository(db *gorm.DB) repositories.ProfileRepository {
 }
 
 func (r *profileRepository) Create(ctx context.Context, profile *entities.UserProfile) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Create(profile).Error
 }
 
 func (r *profileRepository) GetByUserID(ctx context.Context, userID string) (*entities.UserProfile, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var profile entities.UserProfile
 	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error
 	if err != nil {
@@ -35,6 +42,9 @@ func (r *profileRepository) GetByUserID(ctx context.Context, userID string) (*en
 }
 
 func (r *profileRepository) GetByID(ctx context.Context, id string) (*entities.UserProfile, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var profile entities.UserProfile
 	err := r.db.WithContext(ctx).Where("id = ?", id).First(&profile).Error
 	if err != nil {
@@ -47,14 +57,29 @@ func (r *profileRepository) GetByID(ctx context.Context, id string) (*entities.U
 }
 
 func (r *profileRepository) Update(ctx context.Context, profile *entities.UserProfile) error {
-	return r.db.WithContext(ctx).Save(profile).Error
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	// Keep the stored ciphertext of fields that failed to decrypt rather than overwrite it
+	// with the empty value they were read as
+	query := r.db.WithContext(ctx)
+	if len(profile.UndecryptableFields) > 0 {
+		query = query.Omit(profile.UndecryptableFields...)
+	}
+	return query.Save(profile).Error
 }
 
 func (r *profileRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.UserProfile{}).Error
 }
 
 func (r *profileRepository) ExistsByUserID(ctx context.Context, userID string) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var count int64
 	err := r.db.WithContext(ctx).Model(&entities.UserProfile{}).Where("user_id = ?", userID).Count(&count).Error
 	if err != nil {
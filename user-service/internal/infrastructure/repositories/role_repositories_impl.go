@@ -6,6 +6,7 @@ import (
 
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/db"
 	"gorm.io/gorm"
 )
 
@@ -20,10 +21,16 @@ func NewRoleRepository(db *gorm.DB) repositories.RoleRepository {
 }
 
 func (r *roleRepository) Create(ctx context.Context, role *entities.Role) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Create(role).Error
 }
 
 func (r *roleRepository) GetByID(ctx context.Context, id string) (*entities.Role, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var role entities.Role
 	err := r.db.WithContext(ctx).Preload("Permissions").Where("id = ?", id).First(&role).Error
 	if err != nil {
@@ -36,6 +43,9 @@ func (r *roleRepository) GetByID(ctx context.Context, id string) (*entities.Role
 }
 
 func (r *roleRepository) GetByName(ctx context.Context, name string) (*entities.Role, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var role entities.Role
 	err := r.db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).First(&role).Error
 	if err != nil {
@@ -48,20 +58,32 @@ func (r *roleRepository) GetByName(ctx context.Context, name string) (*entities.
 }
 
 func (r *roleRepository) GetAll(ctx context.Context) ([]entities.Role, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var roles []entities.Role
 	err := r.db.WithContext(ctx).Preload("Permissions").Find(&roles).Error
 	return roles, err
 }
 
 func (r *roleRepository) Update(ctx context.Context, role *entities.Role) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Save(role).Error
 }
 
 func (r *roleRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.Role{}).Error
 }
 
 func (r *roleRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var count int64
 	err := r.db.WithContext(ctx).Model(&entities.Role{}).Where("name = ?", name).Count(&count).Error
 	if err != nil {
@@ -71,6 +93,9 @@ func (r *roleRepository) ExistsByName(ctx context.Context, name string) (bool, e
 }
 
 func (r *roleRepository) GetByIDs(ctx context.Context, ids []string) ([]entities.Role, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var roles []entities.Role
 	err := r.db.WithContext(ctx).Preload("Permissions").Where("id IN ?", ids).Find(&roles).Error
 	return roles, err
@@ -5,36 +5,48 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/entities"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/repositories"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/db"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/pagination"
 	"gorm.io/gorm"
 )
 
 type userRepository struct {
-	db *gorm.DB
+	db          *gorm.DB
+	defaultRole string
 }
 
 // NewUserRepository creates and returns a repositories.UserRepository backed by the provided *gorm.DB.
-// The returned repository uses the given DB connection for all user-related persistence operations.
-func NewUserRepository(db *gorm.DB) repositories.UserRepository {
+// The returned repository uses the given DB connection for all user-related persistence operations,
+// and assigns the role named by cfg.DefaultUserRole to users created without any roles.
+func NewUserRepository(db *gorm.DB, cfg *config.Config) repositories.UserRepository {
 	return &userRepository{
-		db: db,
+		db:          db,
+		defaultRole: cfg.DefaultUserRole,
 	}
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	// Start transaction
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create user
-		if err := tx.Create(user).Error; err != nil {
+		// Create user; the profile is created explicitly below once the user ID is known
+		if err := tx.Omit("Profile").Create(user).Error; err != nil {
 			return err
 		}
 
-		// Assign default "customer" role if no roles specified
+		// Assign the configured default role if no roles specified
 		if len(user.Roles) == 0 {
 			var defaultRole entities.Role
-			if err := tx.Where("name = ?", "customer").First(&defaultRole).Error; err != nil {
-				return fmt.Errorf("failed to get default role: %w", err)
+			if err := tx.Where("name = ?", r.defaultRole).First(&defaultRole).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("default role %q does not exist; create it or set DEFAULT_USER_ROLE to an existing role", r.defaultRole)
+				}
+				return fmt.Errorf("failed to get default role %q: %w", r.defaultRole, err)
 			}
 
 			if err := tx.Model(user).Association("Roles").Append(&defaultRole); err != nil {
@@ -42,16 +54,26 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) error
 			}
 		}
 
+		if user.Profile != nil {
+			user.Profile.UserID = user.ID
+			if err := tx.Omit("User").Create(user.Profile).Error; err != nil {
+				return fmt.Errorf("failed to create profile: %w", err)
+			}
+		}
+
 		return nil
 	})
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var user entities.User
 	if err := r.db.WithContext(ctx).
 		Preload("Roles.Permissions").
 		Preload("Profile").
-		Where("email = ?", email).First(&user).Error; err != nil {
+		Where("LOWER(email) = ?", entities.NormalizeEmail(email)).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -61,6 +83,9 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entitie
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var user entities.User
 	if err := r.db.WithContext(ctx).
 		Preload("Roles.Permissions").
@@ -74,18 +99,93 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*entities.User
 	return &user, nil
 }
 
+// GetWithRoles loads a user together with their roles and each role's permissions,
+// skipping the profile. It returns nil, nil when the user does not exist.
+func (r *userRepository) GetWithRoles(ctx context.Context, id string) (*entities.User, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var user entities.User
+	if err := r.db.WithContext(ctx).
+		Preload("Roles.Permissions").
+		Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByIDs returns the users matching ids with their profiles loaded. Unknown IDs are
+// skipped, so the result may be shorter than ids.
+func (r *userRepository) GetByIDs(ctx context.Context, ids []string) ([]entities.User, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var users []entities.User
+	err := r.db.WithContext(ctx).Preload("Profile").Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 func (r *userRepository) Update(ctx context.Context, user *entities.User) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
 func (r *userRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.User{}).Error
 }
 
+func (r *userRepository) DeleteWithProfile(ctx context.Context, id string) error {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&entities.UserProfile{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&entities.User{}).Error
+	})
+}
+
 func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&entities.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&entities.User{}).Where("LOWER(email) = ?", entities.NormalizeEmail(email)).Count(&count).Error; err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// ListByCursor returns up to limit users ordered by created_at DESC, id DESC, starting
+// after the given cursor. A nil cursor starts from the newest user.
+func (r *userRepository) ListByCursor(ctx context.Context, cursor *pagination.Cursor, limit int) ([]entities.User, error) {
+	ctx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+
+	var users []entities.User
+	query := r.db.WithContext(ctx).
+		Preload("Roles").
+		Preload("Profile")
+
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -1,16 +1,19 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/validator"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
 )
 
 type AuthHandler struct {
 	authService services.AuthService
-	validator   *validator.AuthValidator
+	validator   *validator.RequestValidator
 }
 
 const INVALID_REQUEST_BODY = "Invalid request body"
@@ -18,8 +21,26 @@ const INVALID_REQUEST_BODY = "Invalid request body"
 func NewAuthHandler(authService services.AuthService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
-		validator:   validator.NewAuthValidator(),
+		validator:   validator.NewRequestValidator(),
+	}
+}
+
+// passwordPolicyErrorResponse reports each failed password rule as a validation error
+func passwordPolicyErrorResponse(c *fiber.Ctx, err *password.PolicyError) error {
+	fieldErrors := make([]utils.FieldError, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		fieldErrors = append(fieldErrors, utils.FieldError{
+			Field:   "password",
+			Tag:     "password_policy",
+			Message: "Password " + violation,
+		})
 	}
+	return utils.ValidationErrorResponse(c, fieldErrors)
+}
+
+// GetPasswordPolicy returns the password rules so clients can check passwords before submitting them
+func (h *AuthHandler) GetPasswordPolicy(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, "Password policy retrieved successfully", h.authService.PasswordPolicy())
 }
 
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
@@ -27,15 +48,20 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, INVALID_REQUEST_BODY)
 	}
+	req.Normalize()
 
 	// Validate request
-	if errors := h.validator.ValidateRegister(&req); len(errors) > 0 {
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
 		return utils.ValidationErrorResponse(c, errors)
 	}
 
 	// Register user
 	response, err := h.authService.Register(c, &req)
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			return passwordPolicyErrorResponse(c, policyErr)
+		}
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
@@ -47,15 +73,19 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, INVALID_REQUEST_BODY)
 	}
+	req.Normalize()
 
 	// Validate request
-	if errors := h.validator.ValidateLogin(&req); len(errors) > 0 {
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
 		return utils.ValidationErrorResponse(c, errors)
 	}
 
 	// Login user
 	response, err := h.authService.Login(c, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrAccountDisabled) {
+			return utils.ErrorResponseWithCode(c, fiber.StatusForbidden, "ACCOUNT_DISABLED", err.Error())
+		}
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, err.Error())
 	}
 
@@ -77,13 +107,16 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// Validate request
-	if errors := h.validator.ValidateRefreshToken(&req); len(errors) > 0 {
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
 		return utils.ValidationErrorResponse(c, errors)
 	}
 
 	// Refresh token
 	response, err := h.authService.RefreshToken(c, req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, services.ErrAccountDisabled) {
+			return utils.ErrorResponseWithCode(c, fiber.StatusForbidden, "ACCOUNT_DISABLED", err.Error())
+		}
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, err.Error())
 	}
 
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
+)
+
+// registerFailing is an AuthService whose Register always fails with err; its other
+// methods are not implemented
+type registerFailing struct {
+	services.AuthService
+	err error
+}
+
+func (s registerFailing) Register(ctx *fiber.Ctx, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+	return nil, s.err
+}
+
+func TestRegisterReportsWrappedPolicyErrorsPerRule(t *testing.T) {
+	policyErr := &password.PolicyError{Violations: []string{"must contain a digit", "must contain a symbol"}}
+	handler := NewAuthHandler(registerFailing{err: fmt.Errorf("register: %w", policyErr)})
+	app := fiber.New()
+	app.Post("/register", handler.Register)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/register", strings.NewReader(`{"email":"ada@example.com","password":"password","name":"Ada"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Message string             `json:"message"`
+		Errors  []utils.FieldError `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest || body.Message != "Validation failed" {
+		t.Fatalf("status %d, message %q, want a validation failure", resp.StatusCode, body.Message)
+	}
+	if len(body.Errors) != len(policyErr.Violations) {
+		t.Errorf("errors = %+v, want one per failed rule", body.Errors)
+	}
+}
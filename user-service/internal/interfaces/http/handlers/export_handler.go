@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+)
+
+type ExportHandler struct {
+	exportService services.DataExportService
+}
+
+func NewExportHandler(exportService services.DataExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportMe streams everything held about the caller as a JSON file download
+func (h *ExportHandler) ExportMe(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	export, err := h.exportService.ExportUserData(c, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to export user data")
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="user-%s-export.json"`, userID))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(export); err != nil {
+			log.Error().Err(err).Str("user_id", userID).Msg("Failed to write data export")
+		}
+		w.Flush()
+	})
+	return nil
+}
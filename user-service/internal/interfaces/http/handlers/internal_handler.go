@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
 )
 
+// MaxBatchUserIDs caps how many users a single batch lookup may resolve.
+const MaxBatchUserIDs = 100
+
 type InternalHandler struct {
 	userService services.UserService
 }
@@ -36,3 +43,34 @@ func (h *InternalHandler) GetUserRBACInfo(c *fiber.Ctx) error {
 
 	return utils.SuccessResponse(c, "User RBAC info retrieved", rbacInfo)
 }
+
+// GetUsersBatch resolves the comma-separated user IDs in the "ids" query parameter
+// to minimal public profiles for other services. The route verifies the caller's
+// signature before this runs.
+func (h *InternalHandler) GetUsersBatch(c *fiber.Ctx) error {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("Invalid user ID: %s", id))
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "At least one user ID is required")
+	}
+	if len(ids) > MaxBatchUserIDs {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("At most %d user IDs can be requested at once", MaxBatchUserIDs))
+	}
+
+	users, err := h.userService.GetUsersByIDs(c, ids)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "Users retrieved successfully", users)
+}
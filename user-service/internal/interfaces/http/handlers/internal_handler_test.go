@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/middleware"
+)
+
+// usersByIDs is a UserService that resolves every ID to a user; its other methods are
+// not implemented
+type usersByIDs struct {
+	services.UserService
+}
+
+func (usersByIDs) GetUsersByIDs(ctx *fiber.Ctx, ids []string) ([]dto.PublicUserResponse, error) {
+	users := make([]dto.PublicUserResponse, 0, len(ids))
+	for _, id := range ids {
+		users = append(users, dto.PublicUserResponse{ID: id, Name: "Ada"})
+	}
+	return users, nil
+}
+
+func TestGetUsersBatchRequiresInternalSignature(t *testing.T) {
+	const (
+		secret = "shared"
+		uri    = "/internal/users/batch?ids=6f1c1e4a-3b1d-4c55-9d43-2f5e6a7b8c9d"
+	)
+	app := fiber.New()
+	app.Get("/internal/users/batch", middleware.InternalSignature(secret, time.Minute, true), NewInternalHandler(usersByIDs{}).GetUsersBatch)
+
+	sign := func(secret string) map[string]string {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		return map[string]string{
+			middleware.InternalServiceHeader:   "store-service",
+			middleware.InternalTimestampHeader: timestamp,
+			middleware.InternalSignatureHeader: hex.EncodeToString(middleware.SignInternalRequest(secret, "store-service", fiber.MethodGet, uri, timestamp, nil)),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "no headers", status: fiber.StatusForbidden},
+		{name: "service header alone", headers: map[string]string{middleware.InternalServiceHeader: "kong-auth"}, status: fiber.StatusForbidden},
+		{name: "wrong secret", headers: sign("guess"), status: fiber.StatusForbidden},
+		{name: "signed", headers: sign(secret), status: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, uri, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
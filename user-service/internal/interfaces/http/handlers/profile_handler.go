@@ -4,17 +4,20 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/validator"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
 )
 
 type ProfileHandler struct {
 	profileService services.ProfileService
+	validator      *validator.RequestValidator
 }
 
 // NewProfileHandler creates and returns a ProfileHandler wired with the provided ProfileService.
 func NewProfileHandler(profileService services.ProfileService) *ProfileHandler {
 	return &ProfileHandler{
 		profileService: profileService,
+		validator:      validator.NewRequestValidator(),
 	}
 }
 
@@ -28,12 +31,18 @@ func (h *ProfileHandler) CreateProfile(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	req.Normalize()
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
 
 	response, err := h.profileService.CreateProfile(c, userID, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	c.Set(fiber.HeaderLocation, "/api/profiles/users/"+response.UserID+"/profile")
 	return utils.CreatedResponse(c, "Profile created successfully", response)
 }
 
@@ -70,6 +79,11 @@ func (h *ProfileHandler) UpdateProfile(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	req.Normalize()
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
 
 	response, err := h.profileService.UpdateProfile(c, userID, &req)
 	if err != nil {
@@ -84,6 +98,11 @@ func (h *ProfileHandler) UpdateMyProfile(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	req.Normalize()
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
 
 	response, err := h.profileService.UpdateMyProfile(c, &req)
 	if err != nil {
@@ -4,11 +4,13 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/validator"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
 )
 
 type RoleHandler struct {
 	roleService services.RoleService
+	validator   *validator.RequestValidator
 }
 
 // NewRoleHandler creates a RoleHandler wired with the provided RoleService.
@@ -16,6 +18,7 @@ type RoleHandler struct {
 func NewRoleHandler(roleService services.RoleService) *RoleHandler {
 	return &RoleHandler{
 		roleService: roleService,
+		validator:   validator.NewRequestValidator(),
 	}
 }
 
@@ -25,11 +28,16 @@ func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
 	response, err := h.roleService.CreateRole(c, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	c.Set(fiber.HeaderLocation, "/api/roles/"+response.ID)
 	return utils.CreatedResponse(c, "Role created successfully", response)
 }
 
@@ -67,6 +75,10 @@ func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
 	response, err := h.roleService.UpdateRole(c, id, &req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
@@ -88,12 +100,40 @@ func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "Role deleted successfully", nil)
 }
 
+func (h *RoleHandler) CloneRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Role ID is required")
+	}
+
+	var req dto.CloneRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
+	response, err := h.roleService.CloneRole(c, id, req.Name)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	c.Set(fiber.HeaderLocation, "/api/roles/"+response.ID)
+	return utils.CreatedResponse(c, "Role cloned successfully", response)
+}
+
 func (h *RoleHandler) AssignRolesToUser(c *fiber.Ctx) error {
 	var req dto.AssignRoleRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
 	if err := h.roleService.AssignRolesToUser(c, &req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -115,6 +155,20 @@ func (h *RoleHandler) GetUserRoles(c *fiber.Ctx) error {
 	return utils.SuccessResponse(c, "User roles retrieved successfully", response)
 }
 
+func (h *RoleHandler) GetMyPermissions(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "user not authenticated")
+	}
+
+	response, err := h.roleService.GetUserPermissions(c, userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+
+	return utils.SuccessResponse(c, "User permissions retrieved successfully", response)
+}
+
 func (h *RoleHandler) GetAllPermissions(c *fiber.Ctx) error {
 	response, err := h.roleService.GetAllPermissions(c)
 	if err != nil {
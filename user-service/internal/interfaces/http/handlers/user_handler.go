@@ -1,16 +1,21 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/validator"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/pagination"
 )
 
 type UserHandler struct {
 	userService services.UserService
+	validator   *validator.RequestValidator
 }
 
 // NewUserHandler creates a UserHandler wired with the provided UserService.
@@ -18,16 +23,39 @@ type UserHandler struct {
 func NewUserHandler(userService services.UserService) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		validator:   validator.NewRequestValidator(),
 	}
 }
 
+// userMutationError maps a user update or delete failure to a response
+func userMutationError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrUserNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, services.ErrUserConflict) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, services.ErrUserConflict.Error())
+	}
+	if errors.Is(err, services.ErrSelfStatusChange) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, services.ErrPasswordMismatch) {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, services.ErrLastSuperAdmin) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	// Anything else is a database failure whose message should not reach the client
+	log.Error().Err(err).Str("path", c.Path()).Msg("User mutation failed")
+	return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to modify user")
+}
+
 func (h *UserHandler) GetMe(c *fiber.Ctx) error {
 	userID := c.Get("X-User-Id")
 	if userID == "" {
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
 	}
 
-	response, err := h.userService.GetUser(c, userID)
+	response, err := h.userService.GetUser(c, userID, c.QueryBool("include_permissions"))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
 	}
@@ -41,7 +69,7 @@ func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "User ID is required")
 	}
 
-	response, err := h.userService.GetUser(c, userID)
+	response, err := h.userService.GetUser(c, userID, c.QueryBool("include_permissions"))
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, err.Error())
 	}
@@ -60,6 +88,18 @@ func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 		limit = 10
 	}
 
+	// Cursor mode is selected by the presence of the cursor param; an empty value starts from the first page
+	if c.Context().QueryArgs().Has("cursor") {
+		response, err := h.userService.GetAllUsersByCursor(c, c.Query("cursor"), limit)
+		if err != nil {
+			if errors.Is(err, pagination.ErrInvalidCursor) {
+				return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+			}
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return utils.SuccessResponse(c, "Users retrieved successfully", response)
+	}
+
 	response, err := h.userService.GetAllUsers(c, page, limit)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
@@ -78,10 +118,15 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	req.Normalize()
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
 
 	response, err := h.userService.UpdateUser(c, userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return userMutationError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "User updated successfully", response)
@@ -97,15 +142,44 @@ func (h *UserHandler) UpdateMe(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
 	}
+	req.Normalize()
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
 
 	response, err := h.userService.UpdateUser(c, userID, &req)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return userMutationError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "User updated successfully", response)
 }
 
+// DeleteMe deletes the caller's own account. The current password must be sent to
+// confirm it.
+func (h *UserHandler) DeleteMe(c *fiber.Ctx) error {
+	userID := c.Get("X-User-Id")
+	if userID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
+	if err := h.userService.DeleteMe(c, userID, &req); err != nil {
+		return userMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "Account deleted successfully", nil)
+}
+
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	userID := c.Params("id")
 	if userID == "" {
@@ -113,8 +187,58 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	}
 
 	if err := h.userService.DeleteUser(c, userID); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+		return userMutationError(c, err)
 	}
 
 	return utils.SuccessResponse(c, "User deleted successfully", nil)
 }
+
+// SuspendUser deactivates an account on behalf of the calling admin and signs it out
+func (h *UserHandler) SuspendUser(c *fiber.Ctx) error {
+	actorID := c.Get("X-User-Id")
+	if actorID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	var req dto.SuspendUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
+	response, err := h.userService.SuspendUser(c, c.Params("id"), actorID, req.Reason)
+	if err != nil {
+		return userMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "User suspended successfully", response)
+}
+
+// ActivateUser restores a suspended account on behalf of the calling admin. The body is optional.
+func (h *UserHandler) ActivateUser(c *fiber.Ctx) error {
+	actorID := c.Get("X-User-Id")
+	if actorID == "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "User not authenticated")
+	}
+
+	var req dto.ActivateUserRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	if errors := h.validator.Validate(&req); len(errors) > 0 {
+		return utils.ValidationErrorResponse(c, errors)
+	}
+
+	response, err := h.userService.ActivateUser(c, c.Params("id"), actorID, req.Reason)
+	if err != nil {
+		return userMutationError(c, err)
+	}
+
+	return utils.SuccessResponse(c, "User activated successfully", response)
+}
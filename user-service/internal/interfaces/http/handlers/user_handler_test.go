@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/dto"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/domain/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+)
+
+// mutationFailing is a UserService whose updates and deletes fail with err; its other
+// methods are not implemented
+type mutationFailing struct {
+	services.UserService
+	err error
+}
+
+func (s mutationFailing) UpdateUser(ctx *fiber.Ctx, id string, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	return nil, s.err
+}
+
+func (s mutationFailing) DeleteUser(ctx *fiber.Ctx, id string) error {
+	return s.err
+}
+
+func TestUserMutationErrors(t *testing.T) {
+	const rawErr = `pq: duplicate key value violates unique constraint "idx_users_email_lower"`
+
+	errs := []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{services.ErrUserNotFound, fiber.StatusNotFound, services.ErrUserNotFound.Error()},
+		{fmt.Errorf("update user: %w", services.ErrUserConflict), fiber.StatusConflict, services.ErrUserConflict.Error()},
+		{services.ErrLastSuperAdmin, fiber.StatusConflict, services.ErrLastSuperAdmin.Error()},
+		{errors.New(rawErr), fiber.StatusInternalServerError, "Failed to modify user"},
+	}
+	routes := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"UpdateUser", fiber.MethodPut, "/users/6f1c1e4a-3b1d-4c55-9d43-2f5e6a7b8c9d", `{"name":"Ada Lovelace"}`},
+		{"UpdateMe", fiber.MethodPut, "/users/me", `{"name":"Ada Lovelace"}`},
+		{"DeleteUser", fiber.MethodDelete, "/users/6f1c1e4a-3b1d-4c55-9d43-2f5e6a7b8c9d", ""},
+	}
+
+	for _, route := range routes {
+		for _, tt := range errs {
+			t.Run(fmt.Sprintf("%s %d", route.name, tt.status), func(t *testing.T) {
+				handler := NewUserHandler(mutationFailing{err: tt.err})
+				app := fiber.New()
+				app.Put("/users/me", handler.UpdateMe)
+				app.Put("/users/:id", handler.UpdateUser)
+				app.Delete("/users/:id", handler.DeleteUser)
+
+				req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+				req.Header.Set("X-User-Id", "6f1c1e4a-3b1d-4c55-9d43-2f5e6a7b8c9d")
+				resp, err := app.Test(req, -1)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				defer resp.Body.Close()
+
+				var body utils.Response
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if resp.StatusCode != tt.status {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+				}
+				if body.Message != tt.message {
+					t.Errorf("message = %q, want %q", body.Message, tt.message)
+				}
+				if strings.Contains(body.Message+body.Error, "pq:") {
+					t.Errorf("database error sent to the client: %+v", body)
+				}
+			})
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/services"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/password"
 )
 
 // SetupAuthRoutes registers authentication endpoints on the provided Fiber router.
@@ -14,13 +15,19 @@ import (
 //   POST /auth/login     -> authHandler.Login
 //   POST /auth/refresh   -> authHandler.RefreshToken
 //   POST /auth/logout    -> authHandler.Logout
+//   GET  /auth/password-policy -> authHandler.GetPasswordPolicy
 // The function performs setup only and does not return an error; route handlers handle
 // request-level errors. The provided dependencies are used to construct the repository,
 // service, and handler instances.
 func SetupAuthRoutes(api fiber.Router, deps RoutesDependencies) {
 
-	userRepo := repositories.NewUserRepository(deps.Db)
-	authService := services.NewAuthService(userRepo, deps.RedisClient, &deps.Config.JWT, deps.JWTManager)
+	userRepo := repositories.NewUserRepository(deps.Db, deps.Config)
+	var breaches password.BreachChecker
+	if cfg := deps.Config.BreachedPasswords; cfg.Enabled {
+		breaches = password.NewRangeBreachChecker(cfg.RangeURL, cfg.Timeout)
+	}
+	passwordValidator := password.NewValidator(deps.Config.PasswordPolicy, breaches)
+	authService := services.NewAuthService(userRepo, deps.RedisClient, &deps.Config.JWT, deps.JWTManager, passwordValidator)
 	authHandler := handlers.NewAuthHandler(authService)
 
 	auth := api.Group("/auth")
@@ -28,4 +35,5 @@ func SetupAuthRoutes(api fiber.Router, deps RoutesDependencies) {
 	auth.Post("/login", authHandler.Login)
 	auth.Post("/refresh", authHandler.RefreshToken)
 	auth.Post("/logout", authHandler.Logout)
+	auth.Get("/password-policy", authHandler.GetPasswordPolicy)
 }
@@ -5,11 +5,12 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/services"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/middleware"
 )
 
 func SetupInternalRoutes(api fiber.Router, deps RoutesDependencies) {
-	userRepo := repositories.NewUserRepository(deps.Db)
-	userService := services.NewUserService(userRepo, deps.Db)
+	userRepo := repositories.NewUserRepository(deps.Db, deps.Config)
+	userService := services.NewUserService(userRepo, deps.Db, deps.JWTManager)
 	internalHandler := handlers.NewInternalHandler(userService)
 
 	// Internal API for Kong and other services
@@ -17,4 +18,9 @@ func SetupInternalRoutes(api fiber.Router, deps RoutesDependencies) {
 
 	// RBAC endpoint for Kong
 	internal.Get("/users/:userId/rbac", internalHandler.GetUserRBACInfo)
+
+	// Batch lookup of public user profiles for other services, which must sign their requests
+	signing := deps.Config.InternalSigning
+	requireInternal := middleware.InternalSignature(signing.Secret, signing.MaxSkew, true)
+	internal.Get("/users/batch", requireInternal, internalHandler.GetUsersBatch)
 }
@@ -23,7 +23,7 @@ import (
 // Note: the GET /profiles/users/:userId/profile endpoint is intentionally registered in both
 // admin and owner groups in the current routing setup.
 func SetupProfileRoutes(api fiber.Router, deps RoutesDependencies) {
-	userRepo := repositories.NewUserRepository(deps.Db)
+	userRepo := repositories.NewUserRepository(deps.Db, deps.Config)
 	profileRepo := repositories.NewProfileRepository(deps.Db)
 	profileService := services.NewProfileService(profileRepo, userRepo)
 	profileHandler := handlers.NewProfileHandler(profileService)
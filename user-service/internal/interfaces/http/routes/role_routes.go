@@ -5,19 +5,21 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/services"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
 )
 
 // SetupRoleRoutes registers role-related HTTP endpoints on the provided Fiber router.
 // It constructs role, permission, and user repositories and a RoleService/RoleHandler
 // from the provided dependencies, then mounts admin routes under "/roles" for CRUD,
 // assignment, and permissions (POST "/", GET "/", GET "/:id", PUT "/:id", DELETE "/:id",
-// POST "/assign", GET "/users/:userId", GET "/permissions/all"). It also exposes a
-// public endpoint at "/user/roles" that delegates to the handler but returns HTTP 401
-// with JSON {"error":"user not authenticated"} if the "X-User-Id" request header is absent.
+// POST "/:id/clone", POST "/assign", GET "/users/:userId", GET "/permissions/all"). It also exposes a
+// public endpoint at "/user/roles" that delegates to the handler but returns an HTTP 401
+// error envelope if the "X-User-Id" request header is absent, and "/users/me/permissions"
+// which returns the caller's effective, deduplicated permission names.
 func SetupRoleRoutes(api fiber.Router, deps RoutesDependencies) {
 	roleRepo := repositories.NewRoleRepository(deps.Db)
 	permissionRepo := repositories.NewPermissionRepository(deps.Db)
-	userRepo := repositories.NewUserRepository(deps.Db)
+	userRepo := repositories.NewUserRepository(deps.Db, deps.Config)
 	roleService := services.NewRoleService(roleRepo, permissionRepo, userRepo, deps.Db)
 	roleHandler := handlers.NewRoleHandler(roleService)
 
@@ -30,6 +32,7 @@ func SetupRoleRoutes(api fiber.Router, deps RoutesDependencies) {
 	roles.Get("/:id", roleHandler.GetRole)
 	roles.Put("/:id", roleHandler.UpdateRole)
 	roles.Delete("/:id", roleHandler.DeleteRole)
+	roles.Post("/:id/clone", roleHandler.CloneRole)
 
 	// User role assignment
 	roles.Post("/assign", roleHandler.AssignRolesToUser)
@@ -43,10 +46,11 @@ func SetupRoleRoutes(api fiber.Router, deps RoutesDependencies) {
 	userRoles.Get("/roles", func(c *fiber.Ctx) error {
 		userID := c.Get("X-User-Id")
 		if userID == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "user not authenticated",
-			})
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "user not authenticated")
 		}
 		return roleHandler.GetUserRoles(c)
 	})
+
+	// Effective permissions of the current user, used by the frontend to toggle UI
+	api.Get("/users/me/permissions", roleHandler.GetMyPermissions)
 }
\ No newline at end of file
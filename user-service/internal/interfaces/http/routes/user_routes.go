@@ -3,24 +3,39 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/application/services"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/external"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/repositories"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/http/handlers"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/middleware"
 )
 
 // SetupUserRoutes registers user-related HTTP routes on the given Fiber router.
 // It constructs the repository, service, and handler from the provided dependencies
 // and mounts routes under "/users":
 //   - GET, PUT /users/me          : access and update the current user's profile
+//   - DELETE /users/me            : delete the current user's account (password required)
+//   - GET /users/me/export        : download everything held about the current user (rate limited)
 //   - (admin) GET  /users/        : list users
 //   - (admin) GET  /users/:id     : get a user by ID
 //   - (admin) PUT  /users/:id     : update a user by ID
 //   - (admin) DELETE /users/:id   : delete a user by ID
+//   - (admin) POST /users/:id/suspend  : deactivate a user with a reason and revoke their sessions
+//   - (admin) POST /users/:id/activate : reactivate a suspended user
 //
 // The admin routes are protected by AdminOnlyMiddleware using deps.Db.
+//
+// GET /users/me and GET /users/:id return the user's roles; pass include_permissions=true
+// to also get the flattened permission set.
 func SetupUserRoutes(api fiber.Router, deps RoutesDependencies) {
-	userRepo := repositories.NewUserRepository(deps.Db)
-	userService := services.NewUserService(userRepo, deps.Db)
+	userRepo := repositories.NewUserRepository(deps.Db, deps.Config)
+	userService := services.NewUserService(userRepo, deps.Db, deps.JWTManager)
 	userHandler := handlers.NewUserHandler(userService)
+	exportService := services.NewDataExportService(
+		userRepo,
+		external.NewStoreServiceClient(deps.Config.StoreServiceURL),
+		external.NewCartServiceClient(deps.Config.CartServiceURL),
+	)
+	exportHandler := handlers.NewExportHandler(exportService)
 
 	// Protected routes
 	users := api.Group("/users")
@@ -28,6 +43,8 @@ func SetupUserRoutes(api fiber.Router, deps RoutesDependencies) {
 	// User can access their own info
 	users.Get("/me", userHandler.GetMe)
 	users.Put("/me", userHandler.UpdateMe)
+	users.Delete("/me", userHandler.DeleteMe)
+	users.Get("/me/export", middleware.DataExportLimiter(middleware.NewRedisRateCounter(deps.RedisClient), deps.Config.DataExport), exportHandler.ExportMe)
 
 	// Admin routes
 	adminUsers := users.Group("/")
@@ -35,4 +52,6 @@ func SetupUserRoutes(api fiber.Router, deps RoutesDependencies) {
 	adminUsers.Get("/:id", userHandler.GetUser)
 	adminUsers.Put("/:id", userHandler.UpdateUser)
 	adminUsers.Delete("/:id", userHandler.DeleteUser)
+	adminUsers.Post("/:id/suspend", userHandler.SuspendUser)
+	adminUsers.Post("/:id/activate", userHandler.ActivateUser)
 }
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+)
+
+// adminResponse sends a request to app and decodes the response envelope
+func adminResponse(t *testing.T, app *fiber.App, method, path, body string, headers map[string]string) (int, utils.Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope utils.Response
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp.StatusCode, envelope
+}
+
+func TestAdminEndpointsUseResponseEnvelope(t *testing.T) {
+	maintenance := NewMaintenance(NewMemoryMaintenanceFlag(), func() bool { return false }, func() time.Duration { return time.Minute })
+
+	app := fiber.New()
+	app.Use(maintenance.Handler())
+	admin := app.Group(AdminPathPrefix, AdminOnly("secret"))
+	admin.Post("/maintenance", maintenance.UpdateEndpoint())
+	admin.Post("/config/reload", ReloadEndpoint(func() (map[string]any, error) {
+		return nil, errors.New("invalid LOG_BODY_SAMPLE_RATE")
+	}))
+	app.Post("/users", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	authorized := map[string]string{"X-Admin-Token": "secret"}
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		headers    map[string]string
+		wantStatus int
+		wantCode   string
+	}{
+		{"wrong admin token", fiber.MethodPost, "/admin/maintenance", `{"enabled":true}`, map[string]string{"X-Admin-Token": "guess"}, fiber.StatusForbidden, "ACCESS_DENIED"},
+		{"missing enabled", fiber.MethodPost, "/admin/maintenance", `{}`, authorized, fiber.StatusBadRequest, "INVALID_REQUEST"},
+		{"failed reload", fiber.MethodPost, "/admin/config/reload", "", authorized, fiber.StatusInternalServerError, "CONFIG_RELOAD_FAILED"},
+		{"enable maintenance", fiber.MethodPost, "/admin/maintenance", `{"enabled":true}`, authorized, fiber.StatusOK, ""},
+		{"write during maintenance", fiber.MethodPost, "/users", "", nil, fiber.StatusServiceUnavailable, "MAINTENANCE"},
+	}
+	for _, tt := range tests {
+		status, envelope := adminResponse(t, app, tt.method, tt.path, tt.body, tt.headers)
+
+		if status != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, status, tt.wantStatus)
+		}
+		if envelope.Code != tt.wantCode {
+			t.Errorf("%s: code = %q, want %q", tt.name, envelope.Code, tt.wantCode)
+		}
+		if envelope.Success != (status == fiber.StatusOK) || envelope.Message == "" {
+			t.Errorf("%s: envelope = %+v", tt.name, envelope)
+		}
+	}
+}
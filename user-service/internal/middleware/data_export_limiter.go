@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
+)
+
+// RateCounter counts requests per key in fixed windows
+type RateCounter interface {
+	// Increment adds one to the count of key, opening a window of the given length if
+	// none is open, and returns the new count and the time left in the window
+	Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error)
+}
+
+type redisRateCounter struct {
+	client *redis.Client
+}
+
+// NewRedisRateCounter keeps the counts in Redis, so every instance of the service
+// enforces the same limit
+func NewRedisRateCounter(client *redis.Client) RateCounter {
+	return &redisRateCounter{client: client}
+}
+
+func (r *redisRateCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	var (
+		count *redis.IntCmd
+		ttl   *redis.DurationCmd
+	)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, key)
+		ttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// A new key has no expiry yet. Setting it here rather than only on the first count
+	// also repairs a key left without one by a failure between the two calls.
+	left := ttl.Val()
+	if left < 0 {
+		if err := r.client.PExpire(ctx, key, window).Err(); err != nil {
+			return 0, 0, err
+		}
+		left = window
+	}
+	return count.Val(), left, nil
+}
+
+// DataExportLimiter allows each user cfg.MaxRequests data exports per cfg.Window,
+// counted in counter. A non-positive limit or window disables it. If the counter is
+// unavailable exports are refused, since each one fans out to other services.
+func DataExportLimiter(counter RateCounter, cfg config.DataExportConfig) fiber.Handler {
+	if cfg.MaxRequests <= 0 || cfg.Window <= 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+
+	return func(c *fiber.Ctx) error {
+		count, left, err := counter.Increment(c.UserContext(), "export:"+c.Get("X-User-Id"), cfg.Window)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to count data export requests")
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"success": false,
+				"message": "Data export is temporarily unavailable",
+				"error":   "Data export rate limit could not be checked, try again later",
+			})
+		}
+
+		if count > int64(cfg.MaxRequests) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(left.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"message": "Too many data export requests",
+				"error":   "Data export rate limit exceeded, try again later",
+			})
+		}
+
+		return c.Next()
+	}
+}
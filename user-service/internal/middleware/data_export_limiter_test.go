@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
+)
+
+// memoryRateCounter counts in a map and never closes a window
+type memoryRateCounter struct {
+	counts map[string]int64
+	err    error
+}
+
+func (m *memoryRateCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	if m.err != nil {
+		return 0, 0, m.err
+	}
+	m.counts[key]++
+	return m.counts[key], window, nil
+}
+
+func exportStatus(t *testing.T, app *fiber.App, userID string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, "/export", nil)
+	req.Header.Set("X-User-Id", userID)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter)
+}
+
+func newExportApp(counter RateCounter, cfg config.DataExportConfig) *fiber.App {
+	app := fiber.New()
+	app.Get("/export", DataExportLimiter(counter, cfg), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestDataExportLimiterLimitsEachUser(t *testing.T) {
+	counter := &memoryRateCounter{counts: make(map[string]int64)}
+	app := newExportApp(counter, config.DataExportConfig{MaxRequests: 2, Window: time.Hour})
+
+	for i := range 2 {
+		if status, _ := exportStatus(t, app, "ada"); status != fiber.StatusOK {
+			t.Fatalf("export %d: status = %d, want 200", i+1, status)
+		}
+	}
+	status, retryAfter := exportStatus(t, app, "ada")
+	if status != fiber.StatusTooManyRequests {
+		t.Errorf("export 3: status = %d, want 429", status)
+	}
+	if retryAfter != "3600" {
+		t.Errorf("Retry-After = %q, want 3600", retryAfter)
+	}
+
+	if status, _ := exportStatus(t, app, "grace"); status != fiber.StatusOK {
+		t.Errorf("another user: status = %d, want 200", status)
+	}
+}
+
+func TestDataExportLimiterRefusesWhenCounterFails(t *testing.T) {
+	counter := &memoryRateCounter{err: errors.New("connection refused")}
+	app := newExportApp(counter, config.DataExportConfig{MaxRequests: 2, Window: time.Hour})
+
+	if status, _ := exportStatus(t, app, "ada"); status != fiber.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", status)
+	}
+}
+
+func TestDataExportLimiterDisabled(t *testing.T) {
+	counter := &memoryRateCounter{err: errors.New("unused")}
+	for _, cfg := range []config.DataExportConfig{{MaxRequests: 0, Window: time.Hour}, {MaxRequests: 2}} {
+		app := newExportApp(counter, cfg)
+		if status, _ := exportStatus(t, app, "ada"); status != fiber.StatusOK {
+			t.Errorf("%+v: status = %d, want 200", cfg, status)
+		}
+	}
+}
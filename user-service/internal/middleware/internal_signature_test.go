@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newSignatureApp(secret string, required bool) *fiber.App {
+	app := fiber.New()
+	app.Get("/", InternalSignature(secret, time.Minute, required), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestInternalSignature(t *testing.T) {
+	sign := func(secret string) map[string]string {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		return map[string]string{
+			InternalServiceHeader:   "shopping-cart-service",
+			InternalTimestampHeader: timestamp,
+			InternalSignatureHeader: hex.EncodeToString(SignInternalRequest(secret, "shopping-cart-service", fiber.MethodGet, "/", timestamp, nil)),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		required bool
+		headers  map[string]string
+		status   int
+	}{
+		{name: "unsigned public request", secret: "shared", status: fiber.StatusNoContent},
+		{name: "unsigned request to required route", secret: "shared", required: true, status: fiber.StatusForbidden},
+		{name: "signed request", secret: "shared", required: true, headers: sign("shared"), status: fiber.StatusNoContent},
+		{name: "wrong secret", secret: "shared", headers: sign("other"), status: fiber.StatusForbidden},
+		{name: "unsigned claim to be internal", secret: "shared", headers: map[string]string{InternalServiceHeader: "shopping-cart-service"}, status: fiber.StatusForbidden},
+		{name: "no secret, unsigned public request", status: fiber.StatusNoContent},
+		{name: "no secret, required route", required: true, status: fiber.StatusForbidden},
+		{name: "no secret, claim to be internal", headers: sign(""), status: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := newSignatureApp(tt.secret, tt.required).Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
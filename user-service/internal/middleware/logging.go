@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"encoding/json"
+	"errors"
+	"math/rand/v2"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -34,20 +37,74 @@ var logEntryPool = sync.Pool{
 	},
 }
 
-func RequestResponseLogger() fiber.Handler {
+// SetLogLevel sets the global log level from debug, info, warn or error,
+// falling back to info for anything else
+func SetLogLevel(level string) {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || parsed == zerolog.NoLevel {
+		log.Warn().Str("log_level", level).Msg("Unknown log level, defaulting to info")
+		parsed = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(parsed)
+}
+
+// sampleBody reports whether this request falls in the 1-in-rate body logging sample;
+// a rate of 1 or less samples every request
+func sampleBody(rate int) bool {
+	return rate <= 1 || rand.IntN(rate) == 0
+}
+
+// responseStatus returns the status the client will receive. A handler error only becomes
+// a response in the app's error handler, after this middleware returns, so it is derived
+// from the error rather than read from the response.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// RequestResponseLogger always logs a summary line per request. At debug level it also logs
+// headers and bodies for every handler error and non-2xx response, and for a
+// 1-in-bodySampleRate sample of the rest.
+func RequestResponseLogger(bodySampleRate func() int) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 		requestID := c.Locals("requestid").(string)
 
-		entry := logEntryPool.Get().(*LogEntry)
-		defer logEntryPool.Put(entry)
-		*entry = LogEntry{}
-
 		// Process request
 		err := c.Next()
 
 		duration := time.Since(start).Milliseconds()
 
+		status := responseStatus(c, err)
+		event := log.Info().
+			Str("request_id", requestID).
+			Str("method", c.Method()).
+			Str("path", c.Path()).
+			Int("status_code", status).
+			Int64("duration_ms", duration)
+		if err != nil {
+			event = event.Str("error", err.Error())
+		}
+		event.Send()
+
+		// Headers and bodies can carry PII, so they are only logged at debug level
+		if zerolog.GlobalLevel() > zerolog.DebugLevel {
+			return err
+		}
+		if err == nil && status >= 200 && status < 300 && !sampleBody(bodySampleRate()) {
+			return err
+		}
+
+		entry := logEntryPool.Get().(*LogEntry)
+		defer logEntryPool.Put(entry)
+		*entry = LogEntry{}
+
 		// Capture request body
 		var requestBody any
 		if len(c.Body()) > 0 && isJSONContent(c) {
@@ -88,7 +145,7 @@ func RequestResponseLogger() fiber.Handler {
 		entry.UserAgent = c.Get("User-Agent")
 		entry.Headers = headers
 		entry.RequestBody = requestBody
-		entry.StatusCode = c.Response().StatusCode()
+		entry.StatusCode = status
 		entry.ResponseBody = responseBody
 		entry.Duration = duration
 
@@ -96,7 +153,7 @@ func RequestResponseLogger() fiber.Handler {
 			entry.Error = err.Error()
 		}
 
-		log.Info().
+		log.Debug().
 			Str("timestamp", entry.Timestamp).
 			Str("request_id", entry.RequestID).
 			Str("method", entry.Method).
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
+)
+
+// MaintenanceFlag stores whether maintenance mode has been switched on at runtime
+type MaintenanceFlag interface {
+	Enabled(ctx context.Context) (bool, error)
+	SetEnabled(ctx context.Context, enabled bool) error
+}
+
+type memoryMaintenanceFlag struct {
+	enabled atomic.Bool
+}
+
+// NewMemoryMaintenanceFlag keeps the flag in process memory, so it only applies to
+// this instance and resets on restart
+func NewMemoryMaintenanceFlag() MaintenanceFlag {
+	return &memoryMaintenanceFlag{}
+}
+
+func (f *memoryMaintenanceFlag) Enabled(ctx context.Context) (bool, error) {
+	return f.enabled.Load(), nil
+}
+
+func (f *memoryMaintenanceFlag) SetEnabled(ctx context.Context, enabled bool) error {
+	f.enabled.Store(enabled)
+	return nil
+}
+
+// Maintenance rejects mutating requests with 503 while maintenance mode is on.
+// The mode is on when forced by configuration or when the runtime flag is set.
+type Maintenance struct {
+	flag       MaintenanceFlag
+	forced     func() bool
+	retryAfter func() time.Duration
+}
+
+// NewMaintenance creates the maintenance middleware. forced and retryAfter are read on
+// every request so configuration reloads take effect immediately.
+func NewMaintenance(flag MaintenanceFlag, forced func() bool, retryAfter func() time.Duration) *Maintenance {
+	return &Maintenance{
+		flag:       flag,
+		forced:     forced,
+		retryAfter: retryAfter,
+	}
+}
+
+// active reports whether maintenance mode is on. A flag lookup failure is logged
+// and treated as off so an unavailable flag store cannot take the service down.
+func (m *Maintenance) active(ctx context.Context) bool {
+	if m.forced() {
+		return true
+	}
+
+	enabled, err := m.flag.Enabled(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to read maintenance flag")
+		return false
+	}
+	return enabled
+}
+
+// Handler lets reads through and answers POST, PUT, PATCH and DELETE with 503 and a
+// Retry-After header while maintenance mode is on
+func (m *Maintenance) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		// The admin endpoints must stay usable to switch maintenance off again
+		if strings.HasPrefix(c.Path(), AdminPathPrefix) {
+			return c.Next()
+		}
+
+		if !m.active(c.UserContext()) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(m.retryAfter().Seconds())))
+		return utils.ErrorResponseWithCode(c, fiber.StatusServiceUnavailable, "MAINTENANCE", "Service is in maintenance mode, please try again later")
+	}
+}
+
+func (m *Maintenance) status(c *fiber.Ctx) error {
+	enabled, err := m.flag.Enabled(c.UserContext())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read maintenance flag")
+		return utils.ErrorResponseWithCode(c, fiber.StatusInternalServerError, "MAINTENANCE_FLAG_UNAVAILABLE", "Failed to read maintenance flag")
+	}
+
+	forced := m.forced()
+	return utils.SuccessResponse(c, "Maintenance status retrieved", fiber.Map{
+		"active":  forced || enabled,
+		"enabled": enabled,
+		"forced":  forced,
+	})
+}
+
+// StatusEndpoint reports whether maintenance mode is on
+func (m *Maintenance) StatusEndpoint() fiber.Handler {
+	return m.status
+}
+
+// UpdateEndpoint switches the runtime flag from a {"enabled": bool} body. It cannot
+// turn off maintenance that is forced by configuration.
+func (m *Maintenance) UpdateEndpoint() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req struct {
+			Enabled *bool `json:"enabled"`
+		}
+		if err := c.BodyParser(&req); err != nil || req.Enabled == nil {
+			return utils.ErrorResponseWithCode(c, fiber.StatusBadRequest, "INVALID_REQUEST", "enabled is required")
+		}
+
+		if err := m.flag.SetEnabled(c.UserContext(), *req.Enabled); err != nil {
+			log.Error().Err(err).Msg("Failed to update maintenance flag")
+			return utils.ErrorResponseWithCode(c, fiber.StatusInternalServerError, "MAINTENANCE_FLAG_UNAVAILABLE", "Failed to update maintenance flag")
+		}
+
+		log.Info().Bool("enabled", *req.Enabled).Str("ip", c.IP()).Msg("Maintenance mode updated")
+		return m.status(c)
+	}
+}
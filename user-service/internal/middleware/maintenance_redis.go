@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisMaintenanceFlag struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisMaintenanceFlag keeps the flag in Redis under maintenance:<service>, so
+// every instance of the service sees the same setting
+func NewRedisMaintenanceFlag(client *redis.Client, service string) MaintenanceFlag {
+	return &redisMaintenanceFlag{
+		client: client,
+		key:    "maintenance:" + service,
+	}
+}
+
+func (f *redisMaintenanceFlag) Enabled(ctx context.Context) (bool, error) {
+	value, err := f.client.Get(ctx, f.key).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return value == "1", nil
+}
+
+func (f *redisMaintenanceFlag) SetEnabled(ctx context.Context, enabled bool) error {
+	if enabled {
+		return f.client.Set(ctx, f.key, "1", 0).Err()
+	}
+	return f.client.Del(ctx, f.key).Err()
+}
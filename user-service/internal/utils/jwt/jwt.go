@@ -2,6 +2,8 @@ package jwt
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -16,7 +18,13 @@ const (
 	// Redis key prefixes
 	refreshTokenPrefix = "refresh:%d"
 	accessTokenPrefix  = "access:%d"
-	blacklistPrefix    = "blacklist:%s"
+	// Blacklist keys hold the SHA-256 hex digest of the token, never the raw JWT.
+	// Entries written under the old raw-token scheme are not migrated; they simply
+	// stop matching and expire on their own TTL.
+	blacklistPrefix = "blacklist:%s"
+	// rbacCachePrefix is the key under which the Kong auth plugin caches a user's
+	// roles and permissions
+	rbacCachePrefix = "user_rbac:"
 )
 
 type TokenType string
@@ -38,9 +46,27 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func NewTokenManager(cfg *config.JWTConfig, redisClient *redis.Client) (*TokenManager, error) {
+// validateConfig rejects settings that would produce unusable tokens, such as a zero
+// expiration that issues tokens which are already expired
+func validateConfig(cfg *config.JWTConfig) error {
 	if cfg.PrivateKey == "" {
-		return nil, errors.New("JWT secret key is required")
+		return errors.New("JWT secret key is required")
+	}
+	if cfg.Expiration <= 0 {
+		return fmt.Errorf("JWT access token expiration must be positive, got %s", cfg.Expiration)
+	}
+	if cfg.RefreshExpiration <= 0 {
+		return fmt.Errorf("JWT refresh token expiration must be positive, got %s", cfg.RefreshExpiration)
+	}
+	if cfg.RefreshExpiration <= cfg.Expiration {
+		return fmt.Errorf("JWT refresh token expiration (%s) must be longer than the access token expiration (%s)", cfg.RefreshExpiration, cfg.Expiration)
+	}
+	return nil
+}
+
+func NewTokenManager(cfg *config.JWTConfig, redisClient *redis.Client) (*TokenManager, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
 	}
 
 	return &TokenManager{
@@ -117,10 +143,16 @@ func (tm *TokenManager) generateToken(user *entities.User, tokenType TokenType,
 	return tokenString, claims, nil
 }
 
+// blacklistKey returns the Redis key used to blacklist a token
+func blacklistKey(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return fmt.Sprintf(blacklistPrefix, hex.EncodeToString(sum[:]))
+}
+
 func (tm *TokenManager) isTokenBlacklisted(tokenString string) (bool, error) {
 	isBlacklisted, err := tm.redis.Exists(
 		context.Background(),
-		fmt.Sprintf(blacklistPrefix, tokenString),
+		blacklistKey(tokenString),
 	).Result()
 
 	if err != nil && err != redis.Nil {
@@ -143,6 +175,19 @@ func (tm *TokenManager) verifyRefreshTokenInRedis(claims *Claims, tokenString st
 	return nil
 }
 
+func (tm *TokenManager) verifyAccessTokenInRedis(claims *Claims, tokenString string) error {
+	tokenInRedis, err := tm.redis.Get(
+		context.Background(),
+		fmt.Sprintf("%s%s", accessTokenPrefix, claims.UserID),
+	).Result()
+
+	if err != nil || tokenInRedis != tokenString {
+		return errors.New("invalid or expired access token")
+	}
+
+	return nil
+}
+
 func (tm *TokenManager) ValidateToken(tokenString string, tokenType TokenType) (*Claims, error) {
 	// Check if token is blacklisted
 	blacklisted, err := tm.isTokenBlacklisted(tokenString)
@@ -181,6 +226,13 @@ func (tm *TokenManager) ValidateToken(tokenString string, tokenType TokenType) (
 		}
 	}
 
+	// In strict mode, access tokens must also match the one stored in Redis
+	if tokenType == AccessToken && tm.config.StrictAccessTokenCheck {
+		if err := tm.verifyAccessTokenInRedis(claims, tokenString); err != nil {
+			return nil, err
+		}
+	}
+
 	return claims, nil
 }
 
@@ -192,7 +244,7 @@ func (tm *TokenManager) Logout(userID string) error {
 	refreshToken, err := tm.redis.Get(ctx, refreshTokenPrefix+userID).Result()
 	if err == nil {
 		// Add refresh token to blacklist
-		tm.redis.Set(ctx, blacklistPrefix+refreshToken, "1", tm.config.RefreshExpiration)
+		tm.redis.Set(ctx, blacklistKey(refreshToken), "1", tm.config.RefreshExpiration)
 	}
 
 	// Delete all tokens for this user
@@ -205,12 +257,35 @@ func (tm *TokenManager) Logout(userID string) error {
 	return err
 }
 
+// RevokeUser logs a user out and also blacklists their latest access token, which the
+// gateway would otherwise accept until it expires, and drops the roles and permissions
+// the gateway cached for them. Use it when an account stops being usable, such as on
+// deletion or suspension.
+func (tm *TokenManager) RevokeUser(userID string) error {
+	ctx := context.Background()
+
+	accessToken, err := tm.redis.Get(ctx, accessTokenPrefix+userID).Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read access token: %w", err)
+	}
+	if err == nil {
+		if err := tm.InvalidateToken(accessToken, AccessToken, tm.config.Expiration); err != nil {
+			return fmt.Errorf("failed to blacklist access token: %w", err)
+		}
+	}
+
+	if err := tm.Logout(userID); err != nil {
+		return err
+	}
+	return tm.redis.Del(ctx, rbacCachePrefix+userID).Err()
+}
+
 // InvalidateToken adds a token to the blacklist
 func (tm *TokenManager) InvalidateToken(tokenString string, tokenType TokenType, expiration time.Duration) error {
 	// Add token to blacklist
 	return tm.redis.Set(
 		context.Background(),
-		blacklistPrefix+tokenString,
+		blacklistKey(tokenString),
 		"1",
 		expiration,
 	).Err()
@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
+)
+
+func TestNewTokenManagerValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.JWTConfig
+		wantErr bool
+	}{
+		{name: "valid", cfg: config.JWTConfig{PrivateKey: "secret", Expiration: 15 * time.Minute, RefreshExpiration: 24 * time.Hour}},
+		{name: "missing secret", cfg: config.JWTConfig{Expiration: 15 * time.Minute, RefreshExpiration: 24 * time.Hour}, wantErr: true},
+		{name: "zero access expiration", cfg: config.JWTConfig{PrivateKey: "secret", RefreshExpiration: 24 * time.Hour}, wantErr: true},
+		{name: "negative access expiration", cfg: config.JWTConfig{PrivateKey: "secret", Expiration: -time.Minute, RefreshExpiration: 24 * time.Hour}, wantErr: true},
+		{name: "zero refresh expiration", cfg: config.JWTConfig{PrivateKey: "secret", Expiration: 15 * time.Minute}, wantErr: true},
+		{name: "refresh equal to access", cfg: config.JWTConfig{PrivateKey: "secret", Expiration: time.Hour, RefreshExpiration: time.Hour}, wantErr: true},
+		{name: "refresh shorter than access", cfg: config.JWTConfig{PrivateKey: "secret", Expiration: time.Hour, RefreshExpiration: time.Minute}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := NewTokenManager(&tt.cfg, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("config accepted, want an error")
+				}
+				if manager != nil {
+					t.Error("manager returned alongside an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTokenManager: %v", err)
+			}
+		})
+	}
+}
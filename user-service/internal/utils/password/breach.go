@@ -0,0 +1,72 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BreachChecker reports whether a password is known to have been exposed in a data breach
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// RangeBreachChecker queries a Have I Been Pwned style range API using k-anonymity: only
+// the first 5 hex characters of the password's SHA-1 hash leave the service, and the
+// returned suffixes are matched locally.
+type RangeBreachChecker struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewRangeBreachChecker creates a checker for the range API at baseURL, e.g.
+// https://api.pwnedpasswords.com/range
+func NewRangeBreachChecker(baseURL string, timeout time.Duration) *RangeBreachChecker {
+	return &RangeBreachChecker{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (c *RangeBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/"+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Padding hides the real number of matches from anyone watching the response size
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to reach breach range API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach range API returned status %d", resp.StatusCode)
+	}
+
+	// Each line is SUFFIX:COUNT; padding entries have a count of 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if found && count != "0" && strings.EqualFold(candidate, suffix) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breach range response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,35 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyMaxLengthCountsBytes(t *testing.T) {
+	policy := Policy{MinLength: 8, MaxLength: 72}
+
+	tests := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{name: "72 ASCII characters", password: strings.Repeat("a", 72), valid: true},
+		{name: "73 ASCII characters", password: strings.Repeat("a", 73)},
+		{name: "36 two-byte characters", password: strings.Repeat("é", 36), valid: true},
+		{name: "37 two-byte characters", password: strings.Repeat("é", 37)},
+		{name: "19 four-byte characters", password: strings.Repeat("🔑", 19)},
+		{name: "8 two-byte characters meet the minimum", password: strings.Repeat("é", 8), valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Validate accepted a %d-byte password", len(tt.password))
+			}
+		})
+	}
+}
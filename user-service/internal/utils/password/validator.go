@@ -0,0 +1,44 @@
+package password
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Validator checks new passwords against a Policy and, optionally, a BreachChecker
+type Validator struct {
+	Policy Policy
+	// Breaches is consulted after the policy passes; nil disables the breach check
+	Breaches BreachChecker
+}
+
+// NewValidator creates a Validator for policy. breaches may be nil.
+func NewValidator(policy Policy, breaches BreachChecker) *Validator {
+	return &Validator{
+		Policy:   policy,
+		Breaches: breaches,
+	}
+}
+
+// Validate returns a *PolicyError if password breaks the policy or is known to be
+// breached. The breach check fails open: if it cannot be completed the password is
+// allowed, so an outage of the breach service does not block signups.
+func (v *Validator) Validate(ctx context.Context, password string) error {
+	if err := v.Policy.Validate(password); err != nil {
+		return err
+	}
+	if v.Breaches == nil {
+		return nil
+	}
+
+	breached, err := v.Breaches.IsBreached(ctx, password)
+	if err != nil {
+		log.Warn().Err(err).Msg("Breached password check failed, allowing password")
+		return nil
+	}
+	if breached {
+		return &PolicyError{Violations: []string{"has appeared in a known data breach, choose a different one"}}
+	}
+	return nil
+}
@@ -5,9 +5,18 @@ import "github.com/gofiber/fiber/v2"
 type Response struct {
 	RequestID string `json:"request_id,omitempty"`
 	Success   bool   `json:"success"`
+	Code      string `json:"code,omitempty"`
 	Message   string `json:"message"`
 	Data      any    `json:"data,omitempty"`
 	Error     string `json:"error,omitempty"`
+	Errors    any    `json:"errors,omitempty"`
+}
+
+// FieldError describes a validation failure on a single request field and the rule it broke
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag,omitempty"`
+	Message string `json:"message"`
 }
 
 func SuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
@@ -31,22 +40,28 @@ func CreatedResponse(c *fiber.Ctx, message string, data interface{}) error {
 }
 
 func ErrorResponse(c *fiber.Ctx, statusCode int, message string) error {
+	return ErrorResponseWithCode(c, statusCode, "", message)
+}
+
+// ErrorResponseWithCode writes an error envelope carrying a machine-readable code alongside the message
+func ErrorResponseWithCode(c *fiber.Ctx, statusCode int, code string, message string) error {
 	requestID := getRequestID(c)
 	return c.Status(statusCode).JSON(Response{
 		RequestID: requestID,
 		Success:   false,
+		Code:      code,
 		Message:   message,
 		Error:     message,
 	})
 }
 
-func ValidationErrorResponse(c *fiber.Ctx, errors []string) error {
+func ValidationErrorResponse(c *fiber.Ctx, errors []FieldError) error {
 	requestID := getRequestID(c)
-	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-		"request_id": requestID,
-		"success":    false,
-		"message":    "Validation failed",
-		"errors":     errors,
+	return c.Status(fiber.StatusBadRequest).JSON(Response{
+		RequestID: requestID,
+		Success:   false,
+		Message:   "Validation failed",
+		Errors:    errors,
 	})
 }
 
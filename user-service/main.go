@@ -4,9 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/config"
@@ -14,6 +14,7 @@ import (
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/infrastructure/db"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/interfaces/http/routes"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/middleware"
+	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils"
 	"github.com/tasiuskenways/scalable-ecommerce/user-service/internal/utils/jwt"
 	"gorm.io/gorm"
 )
@@ -21,6 +22,7 @@ import (
 func main() {
 
 	cfg := config.Load()
+	middleware.SetLogLevel(cfg.LogLevel)
 
 	runMigration := flag.Bool("migrate", false, "Run migration")
 	resetDb := flag.Bool("resetDb", false, "Reset DB")
@@ -78,22 +80,47 @@ func main() {
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 			}
-			return c.Status(code).JSON(fiber.Map{
-				"error":   true,
-				"message": err.Error(),
-			})
+			return utils.ErrorResponse(c, code, err.Error())
 		},
 	})
 
 	app.Use(requestid.New())
 	app.Use(recover.New())
 
+	// Per-service Prometheus metrics, scraped at /metrics
+	metrics := middleware.NewMetrics("user-service")
+	app.Use(metrics.Handler())
+	app.Get(middleware.MetricsPath, metrics.Endpoint())
+
+	// Settings that can be reloaded at runtime are read through configHolder
+	configHolder := config.NewHolder(cfg)
+
 	// Add comprehensive request/response logging
-	app.Use(middleware.RequestResponseLogger())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+	app.Use(middleware.RequestResponseLogger(func() int { return configHolder.Get().LogBodySampleRate }))
+	app.Use(middleware.CORS(func() string { return configHolder.Get().CORSOrigins }, "Origin,Content-Type,Accept,Authorization"))
+
+	// Maintenance mode rejects writes with 503; toggled at runtime via the admin endpoints
+	maintenance := middleware.NewMaintenance(
+		middleware.NewRedisMaintenanceFlag(redis, "user-service"),
+		func() bool { return configHolder.Get().Maintenance.Enabled },
+		func() time.Duration { return configHolder.Get().Maintenance.RetryAfter },
+	)
+	app.Use(maintenance.Handler())
+
+	// Overall deadline for each request, propagated through c.UserContext()
+	app.Use(middleware.RequestTimeout(cfg.RequestTimeout))
+
+	// Operator endpoints, guarded by ADMIN_TOKEN
+	admin := app.Group(middleware.AdminPathPrefix, middleware.AdminOnly(cfg.AdminToken))
+	admin.Get("/maintenance", maintenance.StatusEndpoint())
+	admin.Put("/maintenance", maintenance.UpdateEndpoint())
+	admin.Post("/config/reload", middleware.ReloadEndpoint(func() (map[string]any, error) {
+		reloaded, err := configHolder.Reload()
+		if err != nil {
+			return nil, err
+		}
+		middleware.SetLogLevel(reloaded.LogLevel)
+		return reloaded.Reloadable(), nil
 	}))
 
 	routes.SetupRoutes(app, routes.RoutesDependencies{